@@ -2,8 +2,9 @@ module media-ingest
 
 go 1.22
 
+require github.com/openai/openai-go v1.12.0
+
 require (
-	github.com/openai/openai-go v1.12.0 // indirect
 	github.com/tidwall/gjson v1.14.4 // indirect
 	github.com/tidwall/match v1.1.1 // indirect
 	github.com/tidwall/pretty v1.2.1 // indirect
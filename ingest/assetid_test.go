@@ -0,0 +1,76 @@
+// media-ingest (mingest) - Media Ingestion CLI tool
+// Copyright (C) 2026  Harrison Wang <https://mingest.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ingest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestComputeAssetIDsMatchesSingle(t *testing.T) {
+	dir := t.TempDir()
+	sizes := map[string]int{
+		"empty.mp4":      0,
+		"small.mp4":      1234,
+		"one-chunk.mp4":  1 << 20,
+		"large.mp4":      3<<20 + 17,
+		"large-copy.mkv": 3<<20 + 17,
+	}
+	var paths []string
+	for name, size := range sizes {
+		data := make([]byte, size)
+		for i := range data {
+			data[i] = byte(i*31 + len(name))
+		}
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, p)
+	}
+
+	for _, workers := range []int{0, 1, 3, 16} {
+		ids, err := computeAssetIDs(paths, workers)
+		if err != nil {
+			t.Fatalf("workers=%d: computeAssetIDs: %v", workers, err)
+		}
+		if len(ids) != len(paths) {
+			t.Fatalf("workers=%d: got %d ids, want %d", workers, len(ids), len(paths))
+		}
+		for _, p := range paths {
+			want, err := computeAssetID(p)
+			if err != nil {
+				t.Fatalf("computeAssetID(%s): %v", p, err)
+			}
+			if ids[p] != want {
+				t.Errorf("workers=%d: %s: batched %s, single %s", workers, filepath.Base(p), ids[p], want)
+			}
+		}
+	}
+}
+
+func TestComputeAssetIDsMissingFile(t *testing.T) {
+	dir := t.TempDir()
+	ok := filepath.Join(dir, "ok.mp4")
+	if err := os.WriteFile(ok, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := computeAssetIDs([]string{ok, filepath.Join(dir, "missing.mp4")}, 2); err == nil {
+		t.Fatal("want an error for a missing file")
+	}
+}
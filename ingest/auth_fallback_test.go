@@ -0,0 +1,179 @@
+// media-ingest (mingest) - Media Ingestion CLI tool
+// Copyright (C) 2026  Harrison Wang <https://mingest.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ingest
+
+import (
+	"reflect"
+	"testing"
+)
+
+type fakeRunResult struct {
+	code  int
+	class string
+}
+
+// fakeYtDlpRunner answers each run from a per-browser script and records the
+// browser of every call; an exhausted script repeats its last entry.
+type fakeYtDlpRunner struct {
+	script map[string][]fakeRunResult
+	calls  *[]string
+}
+
+func (f fakeYtDlpRunner) Run(d deps, args []string, platform videoPlatform, cfg ytDlpConfig) (int, []string, string) {
+	browser := ""
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] == "--cookies-from-browser" {
+			browser = args[i+1]
+		}
+	}
+	*f.calls = append(*f.calls, "yt-dlp:"+browser)
+	results := f.script[browser]
+	if len(results) == 0 {
+		return exitDownloadFailed, nil, ""
+	}
+	r := results[0]
+	if len(results) > 1 {
+		f.script[browser] = results[1:]
+	}
+	if r.code == exitOK {
+		return exitOK, []string{"/tmp/out.mp4"}, ""
+	}
+	return r.code, nil, r.class
+}
+
+func TestRunWithAuthFallbackOrder(t *testing.T) {
+	t.Setenv("MINGEST_BROWSER", "")
+	t.Setenv("MINGEST_BROWSER_PROFILE", "")
+	t.Setenv("MINGEST_RATE_LIMIT_COOLDOWN", "1ms")
+
+	authFail := fakeRunResult{code: exitAuthRequired}
+	cookieFail := fakeRunResult{code: exitCookieProblem}
+	success := fakeRunResult{code: exitOK}
+
+	tests := []struct {
+		name      string
+		sources   []string
+		script    map[string][]fakeRunResult
+		cdpCode   int
+		wantCode  int
+		wantCalls []string
+	}{
+		{
+			name:      "chrome ok",
+			sources:   []string{"chrome", "firefox"},
+			script:    map[string][]fakeRunResult{"chrome": {success}},
+			wantCode:  exitOK,
+			wantCalls: []string{"yt-dlp:chrome"},
+		},
+		{
+			name:      "chrome auth failure tries cdp then firefox",
+			sources:   []string{"chrome", "firefox"},
+			script:    map[string][]fakeRunResult{"chrome": {authFail}, "firefox": {success}},
+			cdpCode:   exitAuthRequired,
+			wantCode:  exitOK,
+			wantCalls: []string{"yt-dlp:chrome", "cdp:chrome", "yt-dlp:firefox"},
+		},
+		{
+			name:      "cdp success stops the chain",
+			sources:   []string{"chrome", "firefox"},
+			script:    map[string][]fakeRunResult{"chrome": {cookieFail}},
+			cdpCode:   exitOK,
+			wantCode:  exitOK,
+			wantCalls: []string{"yt-dlp:chrome", "cdp:chrome"},
+		},
+		{
+			name:      "app-bound edge goes through cdp with edge",
+			sources:   []string{"edge", "firefox"},
+			script:    map[string][]fakeRunResult{"edge": {{code: exitCookieProblem, class: failureClassAppBound}}, "firefox": {success}},
+			cdpCode:   exitCookieProblem,
+			wantCode:  exitOK,
+			wantCalls: []string{"yt-dlp:edge", "cdp:edge", "yt-dlp:firefox"},
+		},
+		{
+			name:      "firefox failure skips cdp",
+			sources:   []string{"firefox", "chrome"},
+			script:    map[string][]fakeRunResult{"firefox": {authFail}, "chrome": {success}},
+			wantCode:  exitOK,
+			wantCalls: []string{"yt-dlp:firefox", "yt-dlp:chrome"},
+		},
+		{
+			name:      "all sources fail with auth",
+			sources:   []string{"chrome", "firefox"},
+			script:    map[string][]fakeRunResult{"chrome": {authFail}, "firefox": {authFail}},
+			cdpCode:   exitAuthRequired,
+			wantCode:  exitAuthRequired,
+			wantCalls: []string{"yt-dlp:chrome", "cdp:chrome", "yt-dlp:firefox"},
+		},
+		{
+			name:      "non-auth failure does not fall back",
+			sources:   []string{"chrome", "firefox"},
+			script:    map[string][]fakeRunResult{"chrome": {{code: exitDownloadFailed}}},
+			wantCode:  exitDownloadFailed,
+			wantCalls: []string{"yt-dlp:chrome"},
+		},
+		{
+			name:    "429 retries the same source and does not fall back",
+			sources: []string{"chrome", "firefox"},
+			script: map[string][]fakeRunResult{"chrome": {
+				{code: exitDownloadFailed, class: failureClassRateLimited},
+			}},
+			wantCode:  exitDownloadFailed,
+			wantCalls: []string{"yt-dlp:chrome", "yt-dlp:chrome", "yt-dlp:chrome"},
+		},
+		{
+			name:    "429 then success on the same source",
+			sources: []string{"chrome", "firefox"},
+			script: map[string][]fakeRunResult{"chrome": {
+				{code: exitDownloadFailed, class: failureClassRateLimited},
+				success,
+			}},
+			wantCode:  exitOK,
+			wantCalls: []string{"yt-dlp:chrome", "yt-dlp:chrome"},
+		},
+	}
+
+	platform, found := platformByID("youtube")
+	if !found {
+		t.Fatal("youtube platform missing")
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls []string
+			var sources []authSource
+			for _, b := range tc.sources {
+				sources = append(sources, authSource{Kind: authKindBrowser, Value: b})
+			}
+			cfg := ytDlpConfig{
+				Runner: fakeYtDlpRunner{script: tc.script, calls: &calls},
+				CDPDownload: func(targetURL string, d deps, platform videoPlatform, cookieFile string, cfg ytDlpConfig, browser string) (int, []string) {
+					calls = append(calls, "cdp:"+browser)
+					if tc.cdpCode == exitOK {
+						return exitOK, []string{"/tmp/out.mp4"}
+					}
+					return tc.cdpCode, nil
+				},
+			}
+			code, _ := runWithAuthFallback("https://www.youtube.com/watch?v=dQw4w9WgXcQ", deps{}, platform, sources, "", cfg)
+			if code != tc.wantCode {
+				t.Errorf("code = %d, want %d", code, tc.wantCode)
+			}
+			if !reflect.DeepEqual(calls, tc.wantCalls) {
+				t.Errorf("calls = %q, want %q", calls, tc.wantCalls)
+			}
+		})
+	}
+}
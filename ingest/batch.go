@@ -0,0 +1,305 @@
+// media-ingest (mingest) - Media Ingestion CLI tool
+// Copyright (C) 2026  Harrison Wang <https://mingest.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ingest
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const defaultBatchConcurrency = 2
+
+type batchOptions struct {
+	FilePath    string
+	Goal        string
+	Target      string
+	OutDir      string
+	Concurrency int
+	NoLLM       bool
+}
+
+type batchItemResult struct {
+	Index         int    `json:"index"`
+	URL           string `json:"url"`
+	OK            bool   `json:"ok"`
+	ExitCode      int    `json:"exit_code"`
+	Stage         string `json:"stage,omitempty"`
+	Error         string `json:"error,omitempty"`
+	AssetID       string `json:"asset_id,omitempty"`
+	OutputPath    string `json:"output_path,omitempty"`
+	PrepPlan      string `json:"prep_plan,omitempty"`
+	SemanticDir   string `json:"semantic_dir,omitempty"`
+	SelectedCount int    `json:"selected_count,omitempty"`
+}
+
+type batchJSONResult struct {
+	OK          bool              `json:"ok"`
+	ExitCode    int               `json:"exit_code"`
+	Error       string            `json:"error,omitempty"`
+	File        string            `json:"file,omitempty"`
+	Goal        string            `json:"goal,omitempty"`
+	Concurrency int               `json:"concurrency,omitempty"`
+	Total       int               `json:"total"`
+	Succeeded   int               `json:"succeeded"`
+	Failed      int               `json:"failed"`
+	Items       []batchItemResult `json:"items,omitempty"`
+}
+
+func parseBatchOptions(args []string) (batchOptions, error) {
+	opts := batchOptions{
+		Concurrency: defaultBatchConcurrency,
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		switch {
+		case arg == "--no-llm":
+			opts.NoLLM = true
+		case arg == "--file":
+			if i+1 >= len(args) {
+				return batchOptions{}, fmt.Errorf("`--file` 缺少参数")
+			}
+			i++
+			opts.FilePath = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--file="):
+			opts.FilePath = strings.TrimSpace(strings.TrimPrefix(arg, "--file="))
+		case arg == "--goal":
+			if i+1 >= len(args) {
+				return batchOptions{}, fmt.Errorf("`--goal` 缺少参数")
+			}
+			i++
+			opts.Goal = strings.ToLower(strings.TrimSpace(args[i]))
+		case strings.HasPrefix(arg, "--goal="):
+			opts.Goal = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--goal=")))
+		case arg == "--target":
+			if i+1 >= len(args) {
+				return batchOptions{}, fmt.Errorf("`--target` 缺少参数")
+			}
+			i++
+			opts.Target = strings.ToLower(strings.TrimSpace(args[i]))
+		case strings.HasPrefix(arg, "--target="):
+			opts.Target = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--target=")))
+		case arg == "--out-dir":
+			if i+1 >= len(args) {
+				return batchOptions{}, fmt.Errorf("`--out-dir` 缺少参数")
+			}
+			i++
+			opts.OutDir = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--out-dir="):
+			opts.OutDir = strings.TrimSpace(strings.TrimPrefix(arg, "--out-dir="))
+		case arg == "--concurrency":
+			if i+1 >= len(args) {
+				return batchOptions{}, fmt.Errorf("`--concurrency` 缺少参数")
+			}
+			i++
+			n, err := strconv.Atoi(strings.TrimSpace(args[i]))
+			if err != nil {
+				return batchOptions{}, fmt.Errorf("`--concurrency` 必须是整数: %s", args[i])
+			}
+			opts.Concurrency = n
+		case strings.HasPrefix(arg, "--concurrency="):
+			v := strings.TrimSpace(strings.TrimPrefix(arg, "--concurrency="))
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return batchOptions{}, fmt.Errorf("`--concurrency` 必须是整数: %s", v)
+			}
+			opts.Concurrency = n
+		default:
+			return batchOptions{}, fmt.Errorf("不支持的参数: %s", arg)
+		}
+	}
+
+	if opts.FilePath == "" {
+		return batchOptions{}, fmt.Errorf("缺少 --file。用法: mingest batch --file <urls.txt> --goal <subtitle|highlights|shorts> [--concurrency <n>]")
+	}
+	switch opts.Goal {
+	case "subtitle", "highlights", "shorts":
+	case "":
+		return batchOptions{}, fmt.Errorf("缺少 --goal")
+	default:
+		return batchOptions{}, fmt.Errorf("`--goal` 仅支持 subtitle|highlights|shorts")
+	}
+	if opts.Concurrency < 1 || opts.Concurrency > 8 {
+		return batchOptions{}, fmt.Errorf("`--concurrency` 需在 1-8")
+	}
+	return opts, nil
+}
+
+func readBatchURLs(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var urls []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		urls = append(urls, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return urls, nil
+}
+
+func runBatch(opts batchOptions, cfg appConfig) int {
+	urls, err := readBatchURLs(opts.FilePath)
+	if err != nil {
+		return batchExitWithErr(exitUsage, fmt.Sprintf("读取 URL 列表失败: %v", err))
+	}
+	if len(urls) == 0 {
+		return batchExitWithErr(exitUsage, "URL 列表为空")
+	}
+
+	logInfo("batch.started", "file", opts.FilePath, "total", len(urls), "concurrency", opts.Concurrency)
+
+	items := make([]batchItemResult, len(urls))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < opts.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				items[i] = runBatchItem(opts, cfg, i, urls[i])
+			}
+		}()
+	}
+	for i := range urls {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	result := batchJSONResult{
+		OK:          true,
+		ExitCode:    exitOK,
+		File:        opts.FilePath,
+		Goal:        opts.Goal,
+		Concurrency: opts.Concurrency,
+		Total:       len(items),
+		Items:       items,
+	}
+	for _, item := range items {
+		if item.OK {
+			result.Succeeded++
+			continue
+		}
+		result.Failed++
+		if result.OK {
+			// Report the first failing item's exit code so scripts can branch on it.
+			result.OK = false
+			result.ExitCode = item.ExitCode
+		}
+	}
+
+	logInfo("batch.finished", "total", result.Total, "succeeded", result.Succeeded, "failed", result.Failed)
+	printBatchJSON(result)
+	return result.ExitCode
+}
+
+func runBatchItem(opts batchOptions, cfg appConfig, index int, rawURL string) batchItemResult {
+	item := batchItemResult{Index: index + 1, URL: rawURL}
+	logInfo("batch.item_started", "index", item.Index, "url", rawURL)
+
+	fail := func(stage string, exitCode int, msg string) batchItemResult {
+		item.Stage = stage
+		item.ExitCode = exitCode
+		item.Error = msg
+		logWarn("batch.item_failed", "index", item.Index, "url", rawURL, "stage", stage, "exit_code", exitCode, "error", msg)
+		return item
+	}
+
+	getArgs := []string{rawURL, "--json"}
+	if opts.OutDir != "" {
+		getArgs = append(getArgs, "--out-dir="+opts.OutDir)
+	}
+	getOpts, err := parseGetOptions(cfg.withDefaults("get", getArgs))
+	if err != nil {
+		return fail("get", exitUsage, err.Error())
+	}
+	got := executeGet(getOpts)
+	if !got.OK {
+		return fail("get", got.ExitCode, got.Error)
+	}
+	if strings.TrimSpace(got.AssetID) == "" {
+		return fail("get", exitDownloadFailed, "下载成功，但未能解析输出文件路径")
+	}
+	item.AssetID = got.AssetID
+	item.OutputPath = got.OutputPath
+
+	prepOpts, err := parsePrepOptions(cfg.withDefaults("prep", []string{got.AssetID, "--goal=" + opts.Goal, "--json"}))
+	if err != nil {
+		return fail("prep", exitUsage, err.Error())
+	}
+	prepped := executePrep(prepOpts)
+	if !prepped.OK {
+		return fail("prep", prepped.ExitCode, prepped.Error)
+	}
+	item.PrepPlan = prepped.PlanPath
+
+	semanticArgs := []string{got.AssetID, "--json"}
+	if opts.Target != "" {
+		semanticArgs = append(semanticArgs, "--target="+opts.Target)
+	}
+	if opts.NoLLM {
+		semanticArgs = append(semanticArgs, "--no-llm")
+	}
+	semanticOpts, err := parseSemanticOptions(cfg.withDefaults("semantic", semanticArgs))
+	if err != nil {
+		return fail("semantic", exitUsage, err.Error())
+	}
+	state, code := runSemanticPipeline(semanticOpts)
+	item.SemanticDir = state.Artifacts.BundleDir
+	item.SelectedCount = len(state.Selected)
+	if code != exitOK {
+		return fail("semantic", code, strings.Join(state.Warnings, "; "))
+	}
+
+	item.OK = true
+	item.ExitCode = exitOK
+	logInfo("batch.item_finished", "index", item.Index, "asset_id", item.AssetID, "selected", item.SelectedCount)
+	return item
+}
+
+func batchExitWithErr(exitCode int, msg string) int {
+	logError("batch.failed", "exit_code", exitCode, "detail", msg)
+	printBatchJSON(batchJSONResult{
+		OK:       false,
+		ExitCode: exitCode,
+		Error:    msg,
+	})
+	return exitCode
+}
+
+func printBatchJSON(v batchJSONResult) {
+	data, err := marshalJSONResult(v)
+	if err != nil {
+		logError("json.marshal_failed", "context", "batch_result", "error", err)
+		return
+	}
+	fmt.Println(string(data))
+}
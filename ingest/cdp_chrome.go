@@ -33,70 +33,322 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
-func runAuth(platform videoPlatform) int {
-	chromePath, err := findChromeExecutable()
+type authOptions struct {
+	PlatformID string
+	Browser    string // chrome|chromium|edge|brave
+	Refresh    bool
+	// ConsentURL, when set, is opened headlessly first so known consent
+	// dialogs can be accepted without the interactive prompt.
+	ConsentURL string
+	// ImportPath adopts an existing Netscape cookie file instead of a browser login.
+	ImportPath string
+	JSON       bool
+}
+
+type authJSONResult struct {
+	OK            bool   `json:"ok"`
+	ExitCode      int    `json:"exit_code"`
+	Error         string `json:"error,omitempty"`
+	ErrorCode     string `json:"error_code,omitempty"`
+	Platform      string `json:"platform"`
+	Browser       string `json:"browser,omitempty"`
+	CookieFile    string `json:"cookie_file,omitempty"`
+	Authenticated bool   `json:"authenticated"`
+}
+
+func runAuth(platform videoPlatform, opts authOptions) int {
+	result := executeAuth(platform, opts)
+	if opts.JSON {
+		printAuthJSON(result)
+	}
+	return result.ExitCode
+}
+
+// executeAuth logs into the managed CDP profile and refreshes the platform's
+// cookie cache. Failures are logged where they happen and also returned so
+// `--json` can report them.
+func executeAuth(platform videoPlatform, opts authOptions) authJSONResult {
+	if opts.ImportPath != "" {
+		return importAuthCookieFile(platform, opts.ImportPath)
+	}
+	browser := resolveCDPBrowser(opts.Browser)
+	fail := func(code int, errorCode string, err error) authJSONResult {
+		return authJSONResult{OK: false, ExitCode: code, Error: err.Error(), ErrorCode: errorCode, Platform: platform.ID, Browser: browser}
+	}
+
+	chromePath, err := findCDPBrowserExecutable(browser)
 	if err != nil {
-		logError("auth.chrome_not_found", "error", err)
-		return exitCookieProblem
+		logError("auth.chrome_not_found", "browser", browser, "error", err)
+		return fail(exitCookieProblem, errorCodeCookieProblem, err)
 	}
-	profileDir, err := chromeProfileDir()
+	profileDir, err := cdpProfileDir(browser)
 	if err != nil {
 		logError("auth.chrome_profile_path_resolve_failed", "error", err)
-		return exitCookieProblem
+		return fail(exitCookieProblem, errorCodeCookieProblem, err)
 	}
 	if err := os.MkdirAll(profileDir, 0o700); err != nil {
 		logError("auth.chrome_profile_dir_create_failed", "error", err, "path", profileDir)
-		return exitCookieProblem
+		return fail(exitCookieProblem, errorCodeCookieProblem, err)
 	}
 
-	logInfo("auth.chrome_selected", "path", chromePath)
+	logInfo("auth.chrome_selected", "browser", browser, "path", chromePath)
 	logInfo("auth.chrome_profile_selected", "path", profileDir)
-	name := platform.Name
-	if strings.TrimSpace(name) == "" {
-		name = platform.ID
+
+	var cookies []chromeCookie
+	if opts.ConsentURL != "" {
+		cookies = autoConsentViaCDP(chromePath, profileDir, platform, opts.ConsentURL)
+	}
+	if cookies == nil && opts.Refresh {
+		cookies = refreshCookiesViaCDP(chromePath, profileDir, platform)
 	}
-	logInfo("auth.user_login_prompt", "platform", name)
+	if cookies == nil {
+		name := platform.Name
+		if strings.TrimSpace(name) == "" {
+			name = platform.ID
+		}
+		logInfo("auth.user_login_prompt", "platform", name)
 
-	cookies, err := chromeAuthViaCDP(chromePath, profileDir, platform)
-	if err != nil {
-		logError("auth.cdp_login_failed", "error", err, "platform", platform.ID)
-		return exitAuthRequired
+		cookies, err = chromeAuthViaCDP(chromePath, profileDir, platform)
+		if err != nil {
+			if errors.Is(err, errCDPTimeout) {
+				logError("auth.cdp_timeout", "error", err, "platform", platform.ID, "hint", "MINGEST_CDP_TIMEOUT")
+				return fail(exitCookieProblem, errorCodeTimedOut, err)
+			}
+			logError("auth.cdp_login_failed", "error", err, "platform", platform.ID)
+			return fail(exitAuthRequired, errorCodeAuthRequired, err)
+		}
 	}
 
 	cookiePath, err := cookiesCacheFilePath(platform)
 	if err != nil {
 		logError("auth.cookie_cache_path_resolve_failed", "error", err, "platform", platform.ID)
-		return exitCookieProblem
+		return fail(exitCookieProblem, errorCodeCookieProblem, err)
 	}
 	// Best effort: keep the cookie file private. Windows ignores chmod.
 	if err := writeNetscapeCookieFile(cookiePath, cookies, platform.AllowsCookieDomain); err != nil {
 		logError("auth.cookie_cache_write_failed", "error", err, "path", cookiePath)
-		return exitCookieProblem
+		return fail(exitCookieProblem, errorCodeCookieProblem, err)
 	}
 	_ = os.Chmod(cookiePath, 0o600)
 
-	logInfo("auth.ready", "path", cookiePath)
-	return exitOK
+	authenticated, err := cookieFileLooksLikeAuthenticated(cookiePath, platform)
+	if err != nil {
+		logWarn("auth.cookie_cache_check_failed", "error", err, "path", cookiePath)
+	}
+	logInfo("auth.ready", "path", cookiePath, "authenticated", authenticated)
+	return authJSONResult{
+		OK:            true,
+		ExitCode:      exitOK,
+		Platform:      platform.ID,
+		Browser:       browser,
+		CookieFile:    cookiePath,
+		Authenticated: authenticated,
+	}
+}
+
+// importAuthCookieFile (`auth --import`) validates a Netscape cookie export,
+// scopes it to the platform's domains and atomically replaces the cookie cache
+// with it. Files without the platform's auth cookies are rejected so a stale
+// or logged-out export never clobbers a working cache.
+func importAuthCookieFile(platform videoPlatform, srcPath string) authJSONResult {
+	fail := func(code int, errorCode string, err error) authJSONResult {
+		logError("auth.cookie_import_failed", "error", err, "path", srcPath, "platform", platform.ID)
+		return authJSONResult{OK: false, ExitCode: code, Error: err.Error(), ErrorCode: errorCode, Platform: platform.ID}
+	}
+
+	if !fileExists(srcPath) {
+		return fail(exitUsage, errorCodeInvalidArgument, fmt.Errorf("cookie 文件不存在: %s", srcPath))
+	}
+	cookiePath, err := cookiesCacheFilePath(platform)
+	if err != nil {
+		return fail(exitCookieProblem, errorCodeCookieProblem, err)
+	}
+
+	// Work on a private copy next to the cache so the replace stays on one filesystem.
+	tmpPath, cleanup, err := createTempCookieJarFile(filepath.Dir(cookiePath))
+	if err != nil {
+		return fail(exitCookieProblem, errorCodeCookieProblem, err)
+	}
+	defer cleanup()
+	if err := copyFileAtomic(srcPath, tmpPath); err != nil {
+		return fail(exitCookieProblem, errorCodeCookieProblem, err)
+	}
+	cookies, err := readNetscapeCookieFile(tmpPath)
+	if err != nil {
+		return fail(exitCookieProblem, errorCodeCookieFile, err)
+	}
+	if len(cookies) == 0 {
+		return fail(exitCookieProblem, errorCodeCookieFile, fmt.Errorf("不是有效的 Netscape cookie 文件（未解析到 cookie）: %s", srcPath))
+	}
+	if err := filterCookieFileForPlatform(tmpPath, platform); err != nil {
+		return fail(exitCookieProblem, errorCodeCookieFile, err)
+	}
+	ok, err := cookieFileLooksLikeAuthenticated(tmpPath, platform)
+	if err != nil {
+		return fail(exitCookieProblem, errorCodeCookieFile, err)
+	}
+	if !ok {
+		return fail(exitAuthRequired, errorCodeAuthRequired, fmt.Errorf("cookie 文件中没有 %s 的登录 cookie（需要 %s 之一）", platform.ID, strings.Join(platform.AuthCookieNames, "/")))
+	}
+	if err := copyFileAtomic(tmpPath, cookiePath); err != nil {
+		return fail(exitCookieProblem, errorCodeCookieProblem, err)
+	}
+
+	logInfo("auth.cookie_imported", "source", srcPath, "path", cookiePath, "platform", platform.ID)
+	return authJSONResult{
+		OK:            true,
+		ExitCode:      exitOK,
+		Platform:      platform.ID,
+		CookieFile:    cookiePath,
+		Authenticated: true,
+	}
+}
+
+func printAuthJSON(v authJSONResult) {
+	data, err := marshalJSONResult(v)
+	if err != nil {
+		logError("json.marshal_failed", "context", "auth_result", "error", err)
+		return
+	}
+	fmt.Println(string(data))
+}
+
+// refreshCookiesViaCDP exports cookies from the managed profile headlessly and returns them
+// only when the profile is already logged in; nil means an interactive login is needed.
+func refreshCookiesViaCDP(chromePath, profileDir string, platform videoPlatform) []chromeCookie {
+	logInfo("auth.cdp_refresh_started", "platform", platform.ID)
+	_, cleanup, cookies, err := exportCookiesFromChromeCDP(chromePath, profileDir, platform, true, "")
+	if err != nil {
+		logWarn("auth.cdp_refresh_failed", "error", err, "platform", platform.ID)
+		return nil
+	}
+	cleanup()
+	if !looksLikeLoggedIn(cookies, platform) {
+		logInfo("auth.cdp_refresh_not_logged_in", "platform", platform.ID, "action", "interactive_login")
+		return nil
+	}
+	logInfo("auth.cdp_refresh_succeeded", "platform", platform.ID)
+	return cookies
 }
 
-func tryDownloadWithChromeCDP(targetURL string, d deps, platform videoPlatform, cookieCacheFile string, cfg ytDlpConfig) (int, []string) {
-	chromePath, err := findChromeExecutable()
+// cdpConsentWait is how long a clicked consent button gets to record its cookies.
+const cdpConsentWait = 2 * time.Second
+
+// autoConsentViaCDP opens targetURL headlessly in the managed profile, clicks
+// the first visible platform.ConsentSelectors match and returns the cookies
+// if the profile is logged in and (when the platform names them) a consent
+// cookie is present. nil means the interactive login is still needed.
+func autoConsentViaCDP(chromePath, profileDir string, platform videoPlatform, targetURL string) []chromeCookie {
+	logInfo("auth.consent_started", "platform", platform.ID, "url", targetURL)
+	_, port, stop, err := startChrome(chromePath, profileDir, true, targetURL)
 	if err != nil {
-		logWarn("auth.chrome_not_found", "error", err)
+		logWarn("auth.consent_failed", "error", err, "platform", platform.ID)
+		return nil
+	}
+	defer stop()
+
+	wsURL, err := waitForFirstPageWSURL(port, 15*time.Second)
+	if err != nil {
+		logWarn("auth.consent_failed", "error", err, "platform", platform.ID)
+		return nil
+	}
+	time.Sleep(cdpWarmupWait)
+
+	if len(platform.ConsentSelectors) == 0 {
+		logInfo("auth.consent_no_selectors", "platform", platform.ID)
+	} else if matched, err := cdpClickFirst(wsURL, platform.ConsentSelectors); err != nil {
+		logWarn("auth.consent_click_failed", "error", err, "platform", platform.ID)
+	} else if matched != "" {
+		logInfo("auth.consent_clicked", "platform", platform.ID, "selector", matched)
+		time.Sleep(cdpConsentWait)
+	} else {
+		logInfo("auth.consent_not_shown", "platform", platform.ID)
+	}
+
+	cookies, err := cdpGetAllCookies(wsURL)
+	if err != nil {
+		logWarn("auth.consent_failed", "error", err, "platform", platform.ID)
+		return nil
+	}
+	if !looksLikeLoggedIn(cookies, platform) {
+		logInfo("auth.consent_not_logged_in", "platform", platform.ID, "action", "interactive_login")
+		return nil
+	}
+	if len(platform.ConsentCookieNames) > 0 && !hasCookieNamed(cookies, platform, platform.ConsentCookieNames) {
+		logInfo("auth.consent_cookie_missing", "platform", platform.ID, "action", "interactive_login")
+		return nil
+	}
+	logInfo("auth.consent_succeeded", "platform", platform.ID)
+	return cookies
+}
+
+// cdpClickFirst clicks the first visible element matching one of selectors
+// and returns that selector, or "" when none is on the page.
+func cdpClickFirst(wsURL string, selectors []string) (string, error) {
+	ws, err := wsDial(wsURL, 5*time.Second)
+	if err != nil {
+		return "", err
+	}
+	defer ws.Close()
+
+	list, err := json.Marshal(selectors)
+	if err != nil {
+		return "", err
+	}
+	expr := `(() => {
+  for (const sel of ` + string(list) + `) {
+    for (const el of document.querySelectorAll(sel)) {
+      if (el.offsetParent === null) continue;
+      el.click();
+      return sel;
+    }
+  }
+  return "";
+})()`
+
+	cdp := newCDPClient(ws)
+	var res struct {
+		Result struct {
+			Value string `json:"value"`
+		} `json:"result"`
+		ExceptionDetails *struct {
+			Text string `json:"text"`
+		} `json:"exceptionDetails"`
+	}
+	if err := cdp.Call("Runtime.evaluate", map[string]any{"expression": expr, "returnByValue": true}, &res); err != nil {
+		return "", err
+	}
+	if res.ExceptionDetails != nil {
+		return "", errors.New(res.ExceptionDetails.Text)
+	}
+	return res.Result.Value, nil
+}
+
+func tryDownloadWithChromeCDP(targetURL string, d deps, platform videoPlatform, cookieCacheFile string, cfg ytDlpConfig, browser string) (int, []string) {
+	browser = resolveCDPBrowser(browser)
+	chromePath, err := findCDPBrowserExecutable(browser)
+	if err != nil {
+		logWarn("auth.chrome_not_found", "browser", browser, "error", err)
 		return exitCookieProblem, nil
 	}
-	profileDir, err := chromeProfileDir()
+	profileDir, err := cdpProfileDir(browser)
 	if err != nil {
 		logWarn("auth.chrome_profile_path_resolve_failed", "error", err)
 		return exitCookieProblem, nil
 	}
 
-	cookieFile, cleanup, cookies, err := exportCookiesFromChromeCDP(chromePath, profileDir, platform, true)
+	// Visit the watch page too so consent/age-gate cookies set there are captured.
+	cookieFile, cleanup, cookies, err := exportCookiesFromChromeCDP(chromePath, profileDir, platform, true, targetURL)
 	if err != nil {
-		logWarn("auth.cdp_cookie_export_failed", "error", err)
+		if errors.Is(err, errCDPTimeout) {
+			logWarn("auth.cdp_timeout", "error", err, "hint", "MINGEST_CDP_TIMEOUT")
+		} else {
+			logWarn("auth.cdp_cookie_export_failed", "error", err)
+		}
 		return exitCookieProblem, nil
 	}
 	defer cleanup()
@@ -114,19 +366,55 @@ func tryDownloadWithChromeCDP(targetURL string, d deps, platform videoPlatform,
 		}
 	}
 
-	args := buildYtDlpArgsWithCookiesFile(targetURL, d, cookieFile, cfg)
+	args, done := buildYtDlpArgsWithCookiesFile(targetURL, d, platform, cookieFile, cfg)
+	defer done()
 	return runYtDlp(d, args, platform, cfg)
 }
 
-func chromeProfileDir() (string, error) {
+// canPromptInteractiveAuth reports whether runAuth can wait for the user in this session.
+func canPromptInteractiveAuth(platform videoPlatform, cfg ytDlpConfig) bool {
+	if strings.TrimSpace(platform.ID) == "" || cfg.Quiet {
+		return false
+	}
+	return stdinIsTerminal()
+}
+
+// isCDPBrowser reports whether browser is a Chromium-family browser usable over CDP.
+func isCDPBrowser(browser string) bool {
+	switch browser {
+	case "chrome", "chromium", "edge", "brave":
+		return true
+	}
+	return false
+}
+
+// resolveCDPBrowser picks the CDP browser: explicit value, then MINGEST_BROWSER, then chrome.
+func resolveCDPBrowser(browser string) string {
+	if v := strings.ToLower(strings.TrimSpace(browser)); isCDPBrowser(v) {
+		return v
+	}
+	if v, _ := splitBrowserKeyring(os.Getenv("MINGEST_BROWSER")); isCDPBrowser(v) {
+		return v
+	}
+	return "chrome"
+}
+
+// cdpProfileDir returns the managed profile for browser. Chrome keeps the original
+// "chrome-profile" dir so existing logins survive.
+func cdpProfileDir(browser string) (string, error) {
 	base, err := appStateDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(base, "chrome-profile"), nil
+	return filepath.Join(base, resolveCDPBrowser(browser)+"-profile"), nil
 }
 
+// appStateDir holds the assets index, cookie caches, config and managed browser
+// profiles. MINGEST_STATE_DIR replaces it entirely (portable installs, isolated tests).
 func appStateDir() (string, error) {
+	if v := strings.TrimSpace(os.Getenv("MINGEST_STATE_DIR")); v != "" {
+		return filepath.Abs(v)
+	}
 	// Prefer LocalAppData on Windows since this is large, non-roaming state.
 	if runtime.GOOS == "windows" {
 		if v := strings.TrimSpace(os.Getenv("LOCALAPPDATA")); v != "" {
@@ -140,43 +428,114 @@ func appStateDir() (string, error) {
 	return filepath.Join(base, "mingest"), nil
 }
 
-func findChromeExecutable() (string, error) {
-	if p := strings.TrimSpace(os.Getenv("MINGEST_CHROME_PATH")); p != "" {
-		if isRunnableFile(p) {
-			return p, nil
+// ensureStateDirOverride creates MINGEST_STATE_DIR when set and checks that it is
+// writable, so a bad override fails up front instead of midway through a download.
+func ensureStateDirOverride() error {
+	if strings.TrimSpace(os.Getenv("MINGEST_STATE_DIR")) == "" {
+		return nil
+	}
+	dir, err := appStateDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, ".mingest-write-check-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	_ = f.Close()
+	return os.Remove(name)
+}
+
+func findCDPBrowserExecutable(browser string) (string, error) {
+	if browser == "chrome" {
+		if p := strings.TrimSpace(os.Getenv("MINGEST_CHROME_PATH")); p != "" {
+			if isRunnableFile(p) {
+				return p, nil
+			}
+			return "", fmt.Errorf("MINGEST_CHROME_PATH 无效: %s", p)
 		}
-		return "", fmt.Errorf("MINGEST_CHROME_PATH 无效: %s", p)
 	}
 
+	var names, dirs, bundles []string
 	switch runtime.GOOS {
 	case "windows":
-		dirs := []string{
-			filepath.Join(os.Getenv("PROGRAMFILES"), "Google", "Chrome", "Application"),
-			filepath.Join(os.Getenv("PROGRAMFILES(X86)"), "Google", "Chrome", "Application"),
-			filepath.Join(os.Getenv("LOCALAPPDATA"), "Google", "Chrome", "Application"),
-		}
-		if p, ok := findBinaryPreferPath("chrome", dirs...); ok {
-			return p, nil
+		programFiles := os.Getenv("PROGRAMFILES")
+		programFilesX86 := os.Getenv("PROGRAMFILES(X86)")
+		localAppData := os.Getenv("LOCALAPPDATA")
+		switch browser {
+		case "chrome":
+			names = []string{"chrome"}
+			dirs = []string{
+				filepath.Join(programFiles, "Google", "Chrome", "Application"),
+				filepath.Join(programFilesX86, "Google", "Chrome", "Application"),
+				filepath.Join(localAppData, "Google", "Chrome", "Application"),
+			}
+		case "chromium":
+			names = []string{"chrome"}
+			dirs = []string{
+				filepath.Join(localAppData, "Chromium", "Application"),
+				filepath.Join(programFiles, "Chromium", "Application"),
+			}
+		case "edge":
+			names = []string{"msedge"}
+			dirs = []string{
+				filepath.Join(programFilesX86, "Microsoft", "Edge", "Application"),
+				filepath.Join(programFiles, "Microsoft", "Edge", "Application"),
+			}
+		case "brave":
+			names = []string{"brave"}
+			dirs = []string{
+				filepath.Join(programFiles, "BraveSoftware", "Brave-Browser", "Application"),
+				filepath.Join(programFilesX86, "BraveSoftware", "Brave-Browser", "Application"),
+				filepath.Join(localAppData, "BraveSoftware", "Brave-Browser", "Application"),
+			}
 		}
 	case "linux":
-		if p, ok := findBinaryPreferPath("google-chrome", ""); ok {
-			return p, nil
-		}
-		if p, ok := findBinaryPreferPath("chrome", ""); ok {
-			return p, nil
+		switch browser {
+		case "chrome":
+			names = []string{"google-chrome", "chrome"}
+		case "chromium":
+			names = []string{"chromium", "chromium-browser"}
+		case "edge":
+			names = []string{"microsoft-edge", "microsoft-edge-stable"}
+		case "brave":
+			names = []string{"brave-browser", "brave"}
 		}
 	case "darwin":
-		// macOS packaged Chrome path
-		candidate := "/Applications/Google Chrome.app/Contents/MacOS/Google Chrome"
+		// macOS packaged app paths
+		switch browser {
+		case "chrome":
+			bundles = []string{"/Applications/Google Chrome.app/Contents/MacOS/Google Chrome"}
+			names = []string{"google-chrome"}
+		case "chromium":
+			bundles = []string{"/Applications/Chromium.app/Contents/MacOS/Chromium"}
+			names = []string{"chromium"}
+		case "edge":
+			bundles = []string{"/Applications/Microsoft Edge.app/Contents/MacOS/Microsoft Edge"}
+		case "brave":
+			bundles = []string{"/Applications/Brave Browser.app/Contents/MacOS/Brave Browser"}
+		}
+	}
+
+	for _, candidate := range bundles {
 		if isRunnableFile(candidate) {
 			return candidate, nil
 		}
-		if p, ok := findBinaryPreferPath("google-chrome", ""); ok {
+	}
+	for _, name := range names {
+		if p, ok := findBinaryPreferPath(name, dirs...); ok {
 			return p, nil
 		}
 	}
 
-	return "", errors.New("未找到 Chrome。可通过 MINGEST_CHROME_PATH 指定 chrome 可执行文件路径")
+	if browser == "chrome" {
+		return "", errors.New("未找到 Chrome。可通过 MINGEST_CHROME_PATH 指定 chrome 可执行文件路径")
+	}
+	return "", fmt.Errorf("未找到 %s 可执行文件", browser)
 }
 
 type chromeCookie struct {
@@ -188,7 +547,10 @@ type chromeCookie struct {
 	Secure  bool    `json:"secure"`
 }
 
-func exportCookiesFromChromeCDP(chromePath, profileDir string, platform videoPlatform, headless bool) (string, func(), []chromeCookie, error) {
+// cdpWarmupWait is how long we let a navigated page set its cookies before exporting.
+const cdpWarmupWait = 3 * time.Second
+
+func exportCookiesFromChromeCDP(chromePath, profileDir string, platform videoPlatform, headless bool, warmupURL string) (string, func(), []chromeCookie, error) {
 	// Start Chrome with our managed profile and export cookies from inside Chrome (no SQLite access).
 	// Opening the target site helps ensure the profile cookie store is initialized before we read it.
 	openURL := strings.TrimSpace(platform.LoginURL)
@@ -209,6 +571,13 @@ func exportCookiesFromChromeCDP(chromePath, profileDir string, platform videoPla
 	// Give Chrome a moment to finish initializing the cookie store for the profile.
 	time.Sleep(500 * time.Millisecond)
 
+	if u := strings.TrimSpace(warmupURL); u != "" {
+		if err := cdpNavigate(wsURL, u, cdpWarmupWait); err != nil {
+			// Not fatal: the login page cookies are usually enough.
+			logWarn("auth.cdp_warmup_failed", "url", u, "error", err)
+		}
+	}
+
 	cookies, err := cdpGetAllCookies(wsURL)
 	if err != nil {
 		return "", nil, nil, err
@@ -222,13 +591,18 @@ func exportCookiesFromChromeCDP(chromePath, profileDir string, platform videoPla
 	}
 	path := f.Name()
 	_ = f.Close()
+	unregister := registerCleanup(func() { _ = os.Remove(path) })
 
 	if err := writeNetscapeCookieFile(path, cookies, platform.AllowsCookieDomain); err != nil {
+		unregister()
 		_ = os.Remove(path)
 		return "", nil, nil, err
 	}
 
-	cleanup := func() { _ = os.Remove(path) }
+	cleanup := func() {
+		unregister()
+		_ = os.Remove(path)
+	}
 	return path, cleanup, cookies, nil
 }
 
@@ -271,11 +645,17 @@ func looksLikeLoggedIn(cookies []chromeCookie, platform videoPlatform) bool {
 	if len(platform.AuthCookieNames) == 0 {
 		return false
 	}
+	return hasCookieNamed(cookies, platform, platform.AuthCookieNames)
+}
+
+// hasCookieNamed reports whether a non-empty cookie with one of names exists
+// on the platform's domains.
+func hasCookieNamed(cookies []chromeCookie, platform videoPlatform, names []string) bool {
 	for _, c := range cookies {
 		if !platform.AllowsCookieDomain(c.Domain) {
 			continue
 		}
-		for _, want := range platform.AuthCookieNames {
+		for _, want := range names {
 			if c.Name == want && c.Value != "" {
 				return true
 			}
@@ -371,7 +751,12 @@ func startChrome(chromePath, profileDir string, headless bool, openURL string) (
 		return nil, 0, nil, err
 	}
 
+	unregister := registerCleanup(func() {
+		_ = proc.Kill()
+		_, _ = proc.Wait()
+	})
 	stop := func() {
+		unregister()
 		_ = proc.Kill()
 		_, _ = proc.Wait()
 	}
@@ -397,9 +782,18 @@ func pickFreePort() (int, error) {
 	return addr.Port, nil
 }
 
+// newDevToolsHTTPClient talks to Chrome's loopback DevTools endpoint directly;
+// MINGEST_PROXY / HTTP_PROXY must never apply to it.
+func newDevToolsHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout:   1 * time.Second,
+		Transport: &http.Transport{Proxy: nil},
+	}
+}
+
 func waitForDevTools(port int, timeout time.Duration) error {
 	deadline := time.Now().Add(timeout)
-	client := &http.Client{Timeout: 1 * time.Second}
+	client := newDevToolsHTTPClient()
 	u := fmt.Sprintf("http://127.0.0.1:%d/json/version", port)
 
 	for time.Now().Before(deadline) {
@@ -425,7 +819,7 @@ type devToolsTarget struct {
 
 func waitForFirstPageWSURL(port int, timeout time.Duration) (string, error) {
 	deadline := time.Now().Add(timeout)
-	client := &http.Client{Timeout: 1 * time.Second}
+	client := newDevToolsHTTPClient()
 	u := fmt.Sprintf("http://127.0.0.1:%d/json/list", port)
 
 	for time.Now().Before(deadline) {
@@ -458,6 +852,27 @@ func waitForFirstPageWSURL(port int, timeout time.Duration) (string, error) {
 	return "", errors.New("未找到可用的 DevTools page target")
 }
 
+func cdpNavigate(wsURL, targetURL string, wait time.Duration) error {
+	ws, err := wsDial(wsURL, 5*time.Second)
+	if err != nil {
+		return err
+	}
+	defer ws.Close()
+
+	cdp := newCDPClient(ws)
+	var res struct {
+		ErrorText string `json:"errorText"`
+	}
+	if err := cdp.Call("Page.navigate", map[string]any{"url": targetURL}, &res); err != nil {
+		return err
+	}
+	if strings.TrimSpace(res.ErrorText) != "" {
+		return errors.New(res.ErrorText)
+	}
+	time.Sleep(wait)
+	return nil
+}
+
 func cdpGetAllCookies(wsURL string) ([]chromeCookie, error) {
 	ws, err := wsDial(wsURL, 5*time.Second)
 	if err != nil {
@@ -465,7 +880,7 @@ func cdpGetAllCookies(wsURL string) ([]chromeCookie, error) {
 	}
 	defer ws.Close()
 
-	cdp := &cdpClient{ws: ws, nextID: 1}
+	cdp := newCDPClient(ws)
 	if err := cdp.Call("Network.enable", nil, nil); err != nil {
 		return nil, err
 	}
@@ -479,15 +894,63 @@ func cdpGetAllCookies(wsURL string) ([]chromeCookie, error) {
 	return res.Cookies, nil
 }
 
+const (
+	defaultCDPCallTimeout = 30 * time.Second
+	cdpPingInterval       = 5 * time.Second
+)
+
+// errCDPTimeout marks a CDP call that hit its read deadline (Chrome hung or stopped responding).
+var errCDPTimeout = errors.New("CDP 调用超时")
+
 type cdpClient struct {
-	ws     *wsConn
-	nextID int
+	ws      *wsConn
+	nextID  int
+	timeout time.Duration
+}
+
+func newCDPClient(ws *wsConn) *cdpClient {
+	return &cdpClient{ws: ws, nextID: 1, timeout: cdpCallTimeout()}
+}
+
+// cdpCallTimeout reads MINGEST_CDP_TIMEOUT (e.g. 60s), defaulting to 30s.
+func cdpCallTimeout() time.Duration {
+	raw := strings.TrimSpace(os.Getenv("MINGEST_CDP_TIMEOUT"))
+	if raw == "" {
+		return defaultCDPCallTimeout
+	}
+	d, err := parseTimeoutValue(raw)
+	if err != nil {
+		logWarn("auth.cdp_timeout_env_invalid", "env", "MINGEST_CDP_TIMEOUT", "value", raw, "error", err)
+		return defaultCDPCallTimeout
+	}
+	return d
 }
 
 func (c *cdpClient) Call(method string, params any, out any) error {
 	id := c.nextID
 	c.nextID++
 
+	if c.timeout > 0 {
+		_ = c.ws.c.SetReadDeadline(time.Now().Add(c.timeout))
+		defer func() { _ = c.ws.c.SetReadDeadline(time.Time{}) }()
+
+		// Keep the connection alive while we wait for slow responses.
+		stopPing := make(chan struct{})
+		defer close(stopPing)
+		go func() {
+			ticker := time.NewTicker(cdpPingInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-stopPing:
+					return
+				case <-ticker.C:
+					_ = c.ws.writeFrame(0x9, nil)
+				}
+			}
+		}()
+	}
+
 	req := map[string]any{
 		"id":     id,
 		"method": method,
@@ -502,6 +965,10 @@ func (c *cdpClient) Call(method string, params any, out any) error {
 	for {
 		msg, err := c.ws.ReadJSONRaw()
 		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				return fmt.Errorf("%w: %s（%s）", errCDPTimeout, method, c.timeout)
+			}
 			return err
 		}
 		var envelope struct {
@@ -532,6 +999,7 @@ func (c *cdpClient) Call(method string, params any, out any) error {
 type wsConn struct {
 	c  net.Conn
 	br *bufio.Reader
+	mu sync.Mutex // serializes frame writes (requests, pings, pongs)
 }
 
 func wsDial(rawURL string, timeout time.Duration) (*wsConn, error) {
@@ -606,30 +1074,76 @@ func (w *wsConn) WriteJSON(v any) error {
 	return w.writeFrame(0x1, b)
 }
 
+// wsMaxMessageSize caps a single (possibly fragmented) message.
+const wsMaxMessageSize = 10 * 1024 * 1024
+
+// Fragmentation state of the message being read. Control frames may arrive
+// between fragments and never change it.
+const (
+	wsMessageNone = iota
+	wsMessageText
+	wsMessageSkipped // fragments of an ignored (non-text) message
+)
+
 func (w *wsConn) ReadJSONRaw() ([]byte, error) {
+	var message []byte
+	state := wsMessageNone
 	for {
-		op, payload, err := w.readFrame()
+		fin, op, payload, err := w.readFrame()
 		if err != nil {
 			return nil, err
 		}
+		if op >= 0x8 {
+			switch op {
+			case 0x9: // ping
+				_ = w.writeFrame(0xA, payload)
+			case 0x8: // close
+				return nil, io.EOF
+			}
+			// pong and reserved control frames are ignored
+			continue
+		}
+		if op != 0x0 && state != wsMessageNone {
+			return nil, errors.New("WebSocket 分片消息未结束时收到新的数据帧")
+		}
 		switch op {
+		case 0x0: // continuation
+			switch state {
+			case wsMessageNone:
+				return nil, errors.New("WebSocket 收到意外的 continuation 帧")
+			case wsMessageSkipped:
+				if fin {
+					state = wsMessageNone
+				}
+				continue
+			}
+			if len(message)+len(payload) > wsMaxMessageSize {
+				return nil, errors.New("WebSocket payload 过大")
+			}
+			message = append(message, payload...)
+			if fin {
+				return message, nil
+			}
 		case 0x1: // text
-			return payload, nil
-		case 0x9: // ping
-			_ = w.writeFrame(0xA, payload)
-			continue
-		case 0xA: // pong
-			continue
-		case 0x8: // close
-			return nil, io.EOF
+			if fin {
+				return payload, nil
+			}
+			// First fragment; continuation frames follow until FIN.
+			message = append(message[:0], payload...)
+			state = wsMessageText
 		default:
-			// ignore other frames
-			continue
+			// ignore other data frames, including their continuations
+			if !fin {
+				state = wsMessageSkipped
+			}
 		}
 	}
 }
 
 func (w *wsConn) writeFrame(opcode byte, payload []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
 	// Client-to-server frames must be masked.
 	const fin = 0x80
 	header := []byte{fin | opcode, 0x80}
@@ -668,15 +1182,16 @@ func (w *wsConn) writeFrame(opcode byte, payload []byte) error {
 	return err
 }
 
-func (w *wsConn) readFrame() (byte, []byte, error) {
+func (w *wsConn) readFrame() (bool, byte, []byte, error) {
 	b0, err := w.br.ReadByte()
 	if err != nil {
-		return 0, nil, err
+		return false, 0, nil, err
 	}
 	b1, err := w.br.ReadByte()
 	if err != nil {
-		return 0, nil, err
+		return false, 0, nil, err
 	}
+	fin := (b0 & 0x80) != 0
 	opcode := b0 & 0x0f
 	mask := (b1 & 0x80) != 0
 	payloadLen := int(b1 & 0x7f)
@@ -685,17 +1200,17 @@ func (w *wsConn) readFrame() (byte, []byte, error) {
 	case 126:
 		ext := make([]byte, 2)
 		if _, err := io.ReadFull(w.br, ext); err != nil {
-			return 0, nil, err
+			return false, 0, nil, err
 		}
 		payloadLen = int(binary.BigEndian.Uint16(ext))
 	case 127:
 		ext := make([]byte, 8)
 		if _, err := io.ReadFull(w.br, ext); err != nil {
-			return 0, nil, err
+			return false, 0, nil, err
 		}
 		n := binary.BigEndian.Uint64(ext)
 		if n > 10*1024*1024 {
-			return 0, nil, errors.New("WebSocket payload 过大")
+			return false, 0, nil, errors.New("WebSocket payload 过大")
 		}
 		payloadLen = int(n)
 	}
@@ -704,13 +1219,13 @@ func (w *wsConn) readFrame() (byte, []byte, error) {
 	if mask {
 		maskKey = make([]byte, 4)
 		if _, err := io.ReadFull(w.br, maskKey); err != nil {
-			return 0, nil, err
+			return false, 0, nil, err
 		}
 	}
 
 	payload := make([]byte, payloadLen)
 	if _, err := io.ReadFull(w.br, payload); err != nil {
-		return 0, nil, err
+		return false, 0, nil, err
 	}
 	if mask {
 		for i := 0; i < payloadLen; i++ {
@@ -718,5 +1233,5 @@ func (w *wsConn) readFrame() (byte, []byte, error) {
 		}
 	}
 
-	return opcode, payload, nil
+	return fin, opcode, payload, nil
 }
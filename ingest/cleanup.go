@@ -0,0 +1,84 @@
+// media-ingest (mingest) - Media Ingestion CLI tool
+// Copyright (C) 2026  Harrison Wang <https://mingest.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ingest
+
+import (
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+
+	"media-ingest/ingest/embedtools"
+)
+
+// Cleanup registry: child processes and temp files that must not outlive an
+// interrupted run register here, and unregister once released normally.
+var (
+	cleanupMu    sync.Mutex
+	cleanupNext  int
+	cleanupFuncs = map[int]func(){}
+)
+
+// registerCleanup adds fn to the registry run on SIGINT/SIGTERM. The returned
+// func removes it again and is safe to call more than once.
+func registerCleanup(fn func()) func() {
+	cleanupMu.Lock()
+	defer cleanupMu.Unlock()
+	id := cleanupNext
+	cleanupNext++
+	cleanupFuncs[id] = fn
+	return func() {
+		cleanupMu.Lock()
+		defer cleanupMu.Unlock()
+		delete(cleanupFuncs, id)
+	}
+}
+
+// runCleanups drains the registry, newest registration first.
+func runCleanups() {
+	cleanupMu.Lock()
+	ids := make([]int, 0, len(cleanupFuncs))
+	for id := range cleanupFuncs {
+		ids = append(ids, id)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(ids)))
+	fns := make([]func(), 0, len(ids))
+	for _, id := range ids {
+		fns = append(fns, cleanupFuncs[id])
+		delete(cleanupFuncs, id)
+	}
+	cleanupMu.Unlock()
+
+	for _, fn := range fns {
+		fn()
+	}
+}
+
+// installSignalCleanup kills registered children (yt-dlp, CDP Chrome), removes
+// temp cookie jars and exits with exitInterrupted on SIGINT/SIGTERM.
+func installSignalCleanup() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-ch
+		logWarn("cli.interrupted", "signal", sig.String(), "exit_code", exitInterrupted)
+		runCleanups()
+		embedtools.Cleanup()
+		os.Exit(exitInterrupted)
+	}()
+}
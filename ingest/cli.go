@@ -19,6 +19,7 @@ package ingest
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -27,12 +28,15 @@ import (
 	"io"
 	"net/url"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"text/tabwriter"
 	"time"
 
@@ -51,6 +55,8 @@ const (
 	exitDownloadFailed = 40
 	exitDoctorFailed   = 41
 	exitSemanticFailed = 42
+	exitVerifyFailed   = 43
+	exitInterrupted    = 130 // SIGINT/SIGTERM, 128+SIGINT by shell convention
 )
 
 const (
@@ -81,14 +87,56 @@ const (
 type authSource struct {
 	Kind  authKind
 	Value string
+	// Profile overrides MINGEST_BROWSER_PROFILE for this source when set.
+	Profile string
+	// Keyring is the Linux keyring backend yt-dlp uses to decrypt Chromium cookies.
+	Keyring string
+}
+
+// ytDlpCookieBrowsers lists the browsers yt-dlp's --cookies-from-browser accepts.
+var ytDlpCookieBrowsers = []string{"brave", "chrome", "chromium", "edge", "firefox", "opera", "safari", "vivaldi", "whale"}
+
+// ytDlpKeyrings lists the keyring backends yt-dlp accepts as BROWSER+KEYRING.
+var ytDlpKeyrings = []string{"basictext", "gnomekeyring", "kwallet", "kwallet5", "kwallet6"}
+
+// splitBrowserKeyring splits yt-dlp's `browser+keyring` form (e.g. chrome+gnomekeyring).
+func splitBrowserKeyring(v string) (string, string) {
+	browser, keyring, _ := strings.Cut(strings.ToLower(strings.TrimSpace(v)), "+")
+	return strings.TrimSpace(browser), strings.TrimSpace(keyring)
+}
+
+// browserUsesKeyring reports whether yt-dlp decrypts the browser's cookies via a keyring
+// (Chromium family only; Firefox and Safari ignore it).
+func browserUsesKeyring(browser string) bool {
+	return browser != "firefox" && browser != "safari"
 }
 
 type getOptions struct {
-	TargetURL    string
-	OutDir       string
-	NameTemplate string
-	AssetIDOnly  bool
-	JSON         bool
+	TargetURL      string
+	OutDir         string
+	NameTemplate   string
+	CookiesBrowser string
+	CookiesProfile string
+	Keyring        string
+	OnComplete     string
+	Proxy          string
+	LimitRate      string
+	MaxFilesize    string
+	SleepInterval  float64
+	Section        string
+	SponsorBlock   string
+	SponsorCats    string
+	EmbedChapters  bool
+	NoThumbnail    bool
+	NoMetadata     bool
+	WaitForVideo   string
+	LiveFromStart  bool
+	RestrictNames  bool
+	Timeout        time.Duration
+	AssetIDOnly    bool
+	Verify         bool
+	Info           bool
+	JSON           bool
 }
 
 type lsOptions struct {
@@ -106,8 +154,23 @@ type getJSONResult struct {
 	Platform     string `json:"platform,omitempty"`
 	OutputPath   string `json:"output_path,omitempty"`
 	AssetID      string `json:"asset_id,omitempty"`
+	ContentSHA   string `json:"content_sha256,omitempty"`
+	Section      string `json:"section,omitempty"`
+	PartialPath  string `json:"partial_path,omitempty"`
+	ErrorCode    string `json:"error_code,omitempty"`
+	Hint         string `json:"hint,omitempty"`
 	OutputDir    string `json:"out_dir,omitempty"`
 	NameTemplate string `json:"name_template,omitempty"`
+	// ChaptersEmbedded reports whether `--embed-chapters` left chapters in the file.
+	ChaptersEmbedded bool `json:"chapters_embedded,omitempty"`
+	// PlaylistItems lists the files after the first when the URL was a playlist;
+	// each is indexed under its own asset_id.
+	PlaylistItems []getPlaylistItem `json:"playlist_items,omitempty"`
+}
+
+type getPlaylistItem struct {
+	AssetID    string `json:"asset_id"`
+	OutputPath string `json:"output_path"`
 }
 
 type ytDlpConfig struct {
@@ -115,6 +178,58 @@ type ytDlpConfig struct {
 	CaptureMovedPath bool
 	Quiet            bool
 	ProgressOnly     bool
+	Timeout          time.Duration
+	Proxy            string
+	LimitRate        string
+	MaxFilesize      string
+	SleepInterval    float64
+	Section          string
+	SponsorBlock     string
+	SponsorCats      string
+	EmbedChapters    bool
+	NoThumbnail      bool
+	NoMetadata       bool
+	WaitForVideo     string
+	LiveFromStart    bool
+	RestrictNames    bool
+	// Failure, when set, receives the classification of each failed yt-dlp run.
+	Failure *ytDlpFailure
+	// Destinations, when set, collects the files yt-dlp announced it was
+	// writing ("[download] Destination: ..."), so a leftover .part can be
+	// matched to this download rather than a neighbour in the same dir.
+	Destinations *[]string
+	// Runner executes yt-dlp; nil starts the real binary. Tests substitute a
+	// fake to script exit codes through runWithAuthFallback.
+	Runner ytDlpRunner
+	// CDPDownload replaces tryDownloadWithChromeCDP in the auth fallback; nil
+	// drives the real browser.
+	CDPDownload func(targetURL string, d deps, platform videoPlatform, cookieFile string, cfg ytDlpConfig, browser string) (int, []string)
+}
+
+// ytDlpRunner runs one yt-dlp invocation and returns the classified exit
+// code, the moved output paths and the failure class (see failureClassAppBound).
+type ytDlpRunner interface {
+	Run(d deps, args []string, platform videoPlatform, cfg ytDlpConfig) (int, []string, string)
+}
+
+// processYtDlpRunner starts d.YtDlp.Path as a child process.
+type processYtDlpRunner struct{}
+
+func (processYtDlpRunner) Run(d deps, args []string, platform videoPlatform, cfg ytDlpConfig) (int, []string, string) {
+	return runYtDlpClassified(d, args, platform, cfg)
+}
+
+func (cfg ytDlpConfig) runner() ytDlpRunner {
+	if cfg.Runner != nil {
+		return cfg.Runner
+	}
+	return processYtDlpRunner{}
+}
+
+type ytDlpFailure struct {
+	ExitCode  int
+	ErrorCode string
+	Hint      string
 }
 
 type streamOptions struct {
@@ -131,12 +246,16 @@ type progressRenderer struct {
 }
 
 type assetRecord struct {
-	AssetID    string `json:"asset_id"`
-	URL        string `json:"url"`
-	Platform   string `json:"platform"`
-	Title      string `json:"title"`
-	OutputPath string `json:"output_path"`
-	CreatedAt  string `json:"created_at"`
+	AssetID       string `json:"asset_id"`
+	URL           string `json:"url"`
+	Platform      string `json:"platform"`
+	Title         string `json:"title"`
+	OutputPath    string `json:"output_path"`
+	CreatedAt     string `json:"created_at"`
+	ContentSHA256 string `json:"content_sha256,omitempty"`
+	// Section marks a partial capture (get --section). Its asset_id hashes the
+	// downloaded section, so it differs from a full download of the same URL.
+	Section string `json:"section,omitempty"`
 }
 
 type lsJSONResult struct {
@@ -147,9 +266,11 @@ type lsJSONResult struct {
 }
 
 func Main(args []string) int {
+	args = applyVerbosityFlags(args)
 	configureLogger()
 	console.EnsureUTF8()
 	defer embedtools.Cleanup()
+	installSignalCleanup()
 
 	if len(args) == 1 {
 		usage()
@@ -166,9 +287,26 @@ func Main(args []string) int {
 		return exitOK
 	}
 
+	cfg, err := loadAppConfig()
+	if err != nil {
+		logWarn("config.load_failed", "path", cfg.Path, "error", err)
+	}
+	cfg.applyEnv()
+	if err := ensureStateDirOverride(); err != nil {
+		logError("state_dir.unusable", "env", "MINGEST_STATE_DIR", "path", os.Getenv("MINGEST_STATE_DIR"), "error", err)
+		return exitUsage
+	}
+	libArgs, err := applyLibraryFlag(args)
+	if err != nil {
+		logError("cli.invalid_arguments", "command", args[1], "error", err)
+		usage()
+		return exitUsage
+	}
+	args = applyJSONPrettyFlag(libArgs)
+
 	switch strings.ToLower(strings.TrimSpace(args[1])) {
 	case "get":
-		opts, err := parseGetOptions(args[2:])
+		opts, err := parseGetOptions(cfg.withDefaults("get", args[2:]))
 		if err != nil {
 			logError("cli.invalid_arguments", "command", "get", "error", err)
 			usage()
@@ -176,7 +314,7 @@ func Main(args []string) int {
 		}
 		return runGet(opts)
 	case "prep":
-		opts, err := parsePrepOptions(args[2:])
+		opts, err := parsePrepOptions(cfg.withDefaults("prep", args[2:]))
 		if err != nil {
 			logError("cli.invalid_arguments", "command", "prep", "error", err)
 			usage()
@@ -184,15 +322,31 @@ func Main(args []string) int {
 		}
 		return runPrep(opts)
 	case "export":
-		opts, err := parseExportOptions(args[2:])
+		opts, err := parseExportOptions(cfg.withDefaults("export", args[2:]))
 		if err != nil {
 			logError("cli.invalid_arguments", "command", "export", "error", err)
 			usage()
 			return exitUsage
 		}
 		return runExport(opts)
+	case "version", "--version", "-v":
+		opts, err := parseVersionOptions(args[2:])
+		if err != nil {
+			logError("cli.invalid_arguments", "command", "version", "error", err)
+			usage()
+			return exitUsage
+		}
+		return runVersion(opts)
+	case "verify":
+		opts, err := parseVerifyOptions(args[2:])
+		if err != nil {
+			logError("cli.invalid_arguments", "command", "verify", "error", err)
+			usage()
+			return exitUsage
+		}
+		return runVerify(opts)
 	case "ls":
-		opts, err := parseLsOptions(args[2:])
+		opts, err := parseLsOptions(cfg.withDefaults("ls", args[2:]))
 		if err != nil {
 			logError("cli.invalid_arguments", "command", "ls", "error", err)
 			usage()
@@ -200,68 +354,334 @@ func Main(args []string) int {
 		}
 		return runLs(opts)
 	case "doctor":
-		opts, err := parseDoctorOptions(args[2:])
+		opts, err := parseDoctorOptions(cfg.withDefaults("doctor", args[2:]))
 		if err != nil {
 			logError("cli.invalid_arguments", "command", "doctor", "error", err)
 			usage()
 			return exitUsage
 		}
 		return runDoctor(opts)
+	case "doctor-env":
+		opts, err := parseDoctorEnvOptions(args[2:])
+		if err != nil {
+			logError("cli.invalid_arguments", "command", "doctor-env", "error", err)
+			usage()
+			return exitUsage
+		}
+		return runDoctorEnv(opts)
 	case "semantic":
-		opts, err := parseSemanticOptions(args[2:])
+		opts, err := parseSemanticOptions(cfg.withDefaults("semantic", args[2:]))
 		if err != nil {
 			logError("cli.invalid_arguments", "command", "semantic", "error", err)
 			usage()
 			return exitUsage
 		}
 		return runSemantic(opts)
+	case "thumbnail":
+		opts, err := parseThumbnailOptions(args[2:])
+		if err != nil {
+			logError("cli.invalid_arguments", "command", "thumbnail", "error", err)
+			usage()
+			return exitUsage
+		}
+		return runThumbnail(opts)
+	case "transcribe":
+		opts, err := parseTranscribeOptions(args[2:])
+		if err != nil {
+			logError("cli.invalid_arguments", "command", "transcribe", "error", err)
+			usage()
+			return exitUsage
+		}
+		return runTranscribe(opts)
+	case "translate-subtitles":
+		opts, err := parseTranslateOptions(args[2:])
+		if err != nil {
+			logError("cli.invalid_arguments", "command", "translate-subtitles", "error", err)
+			usage()
+			return exitUsage
+		}
+		return runTranslate(opts)
+	case "open":
+		opts, err := parseOpenOptions(args[2:])
+		if err != nil {
+			logError("cli.invalid_arguments", "command", "open", "error", err)
+			usage()
+			return exitUsage
+		}
+		return runOpen(opts)
+	case "import":
+		opts, err := parseImportOptions(args[2:])
+		if err != nil {
+			logError("cli.invalid_arguments", "command", "import", "error", err)
+			usage()
+			return exitUsage
+		}
+		return runImport(opts)
+	case "batch":
+		opts, err := parseBatchOptions(args[2:])
+		if err != nil {
+			logError("cli.invalid_arguments", "command", "batch", "error", err)
+			usage()
+			return exitUsage
+		}
+		return runBatch(opts, cfg)
+	case "pipeline":
+		opts, err := parsePipelineOptions(args[2:])
+		if err != nil {
+			logError("cli.invalid_arguments", "command", "pipeline", "error", err)
+			usage()
+			return exitUsage
+		}
+		return runPipeline(opts, cfg)
 	case "auth", "login":
-		if len(args) != 3 {
+		opts, err := parseAuthOptions(args[2:])
+		if err != nil {
+			logError("cli.invalid_arguments", "command", "auth", "error", err)
 			usage()
 			return exitUsage
 		}
-		p, ok := platformByID(args[2])
+		p, ok := platformByID(opts.PlatformID)
 		if !ok {
-			logError("auth.unsupported_platform", "platform", strings.TrimSpace(args[2]))
+			logError("auth.unsupported_platform", "platform", opts.PlatformID)
+			usage()
+			return exitUsage
+		}
+		return runAuth(p, opts)
+	case "cookies":
+		opts, err := parseCookiesOptions(args[2:])
+		if err != nil {
+			logError("cli.invalid_arguments", "command", "cookies", "error", err)
 			usage()
 			return exitUsage
 		}
-		return runAuth(p)
+		return runCookies(opts)
 	default:
 		usage()
 		return exitUsage
 	}
 }
 
+// jsonPretty makes every --json printer emit indented output. Compact stays
+// the default so piping into other tools is unaffected.
+var jsonPretty bool
+
+// applyJSONPrettyFlag strips the global --json-pretty flag (also enabled by
+// MINGEST_JSON_INDENT) and, for commands that have --json, turns it on.
+func applyJSONPrettyFlag(args []string) []string {
+	if v, err := strconv.ParseBool(strings.TrimSpace(os.Getenv("MINGEST_JSON_INDENT"))); err == nil && v {
+		jsonPretty = true
+	}
+
+	out := make([]string, 0, len(args))
+	found := false
+	for i, arg := range args {
+		if i >= 2 && strings.TrimSpace(arg) == "--json-pretty" {
+			found = true
+			continue
+		}
+		out = append(out, arg)
+	}
+	if !found {
+		return args
+	}
+	jsonPretty = true
+	switch strings.ToLower(strings.TrimSpace(args[1])) {
+	case "get", "prep", "export", "verify", "doctor", "semantic", "version", "thumbnail", "transcribe", "translate-subtitles", "cookies", "pipeline", "doctor-env", "auth", "login", "import":
+		if !contains(out[2:], "--json") {
+			out = append(out, "--json")
+		}
+	}
+	return out
+}
+
+// applyLibraryFlag strips the global --library <dir> flag and exports it as
+// MINGEST_LIBRARY, so every command that reads or writes bundles agrees on it.
+func applyLibraryFlag(args []string) ([]string, error) {
+	out := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		if i < 2 {
+			out = append(out, args[i])
+			continue
+		}
+		value := ""
+		switch {
+		case arg == "--library":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("`--library` 缺少参数")
+			}
+			i++
+			value = args[i]
+		case strings.HasPrefix(arg, "--library="):
+			value = strings.TrimPrefix(arg, "--library=")
+		default:
+			out = append(out, args[i])
+			continue
+		}
+		if strings.TrimSpace(value) == "" {
+			return nil, fmt.Errorf("`--library` 不能为空")
+		}
+		_ = os.Setenv("MINGEST_LIBRARY", strings.TrimSpace(value))
+	}
+	return out, nil
+}
+
+func marshalJSONResult(v interface{}) ([]byte, error) {
+	if jsonPretty {
+		return json.MarshalIndent(v, "", "  ")
+	}
+	return json.Marshal(v)
+}
+
+func parseAuthOptions(args []string) (authOptions, error) {
+	opts := authOptions{}
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		switch {
+		case arg == "--refresh":
+			opts.Refresh = true
+		case arg == "--json":
+			opts.JSON = true
+		case arg == "--browser":
+			if i+1 >= len(args) {
+				return authOptions{}, fmt.Errorf("`--browser` 缺少参数")
+			}
+			i++
+			opts.Browser = strings.ToLower(strings.TrimSpace(args[i]))
+		case strings.HasPrefix(arg, "--browser="):
+			opts.Browser = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--browser=")))
+		case arg == "--consent-url":
+			if i+1 >= len(args) {
+				return authOptions{}, fmt.Errorf("`--consent-url` 缺少参数")
+			}
+			i++
+			opts.ConsentURL = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--consent-url="):
+			opts.ConsentURL = strings.TrimSpace(strings.TrimPrefix(arg, "--consent-url="))
+		case arg == "--import", arg == "--cookies-from-file":
+			if i+1 >= len(args) {
+				return authOptions{}, fmt.Errorf("`%s` 缺少参数", arg)
+			}
+			i++
+			opts.ImportPath = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--import="):
+			opts.ImportPath = strings.TrimSpace(strings.TrimPrefix(arg, "--import="))
+		case strings.HasPrefix(arg, "--cookies-from-file="):
+			opts.ImportPath = strings.TrimSpace(strings.TrimPrefix(arg, "--cookies-from-file="))
+		case strings.HasPrefix(arg, "-"):
+			return authOptions{}, fmt.Errorf("不支持的参数: %s", arg)
+		default:
+			if opts.PlatformID != "" {
+				return authOptions{}, fmt.Errorf("`mingest auth` 仅支持一个 platform")
+			}
+			opts.PlatformID = arg
+		}
+	}
+	if opts.PlatformID == "" {
+		return authOptions{}, fmt.Errorf("缺少 platform。用法: mingest auth <platform> [--browser <chrome|chromium|edge|brave>] [--refresh] [--consent-url <url>] [--import <cookies.txt>] [--json]")
+	}
+	if opts.Browser != "" && !isCDPBrowser(opts.Browser) {
+		return authOptions{}, fmt.Errorf("`--browser` 仅支持 chrome|chromium|edge|brave")
+	}
+	if opts.ConsentURL != "" {
+		u, err := url.Parse(opts.ConsentURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return authOptions{}, fmt.Errorf("`--consent-url` 需为 http(s) URL: %s", opts.ConsentURL)
+		}
+	}
+	if opts.ImportPath != "" && (opts.Refresh || opts.ConsentURL != "" || opts.Browser != "") {
+		return authOptions{}, fmt.Errorf("`--import` 不能与 --browser/--refresh/--consent-url 同时使用")
+	}
+	return opts, nil
+}
+
 func usage() {
+	if uiLang() == "en" {
+		usageEN()
+		return
+	}
 	fmt.Println("用法:")
-	fmt.Println("  mingest get <url> [--out-dir <dir>] [--name-template <tpl>] [--asset-id-only] [--json]")
-	fmt.Println("  mingest prep <asset_ref> --goal <subtitle|highlights|shorts> [--lang <auto|zh|en>] [--max-clips <n>] [--clip-seconds <sec>] [--subtitle-style <clean|shorts>] [--json]")
-	fmt.Println("  mingest export <asset_ref> --to <premiere|resolve|capcut> [--with <srt,edl,csv,fcpxml>] [--out-dir <dir>] [--zip] [--json]")
+	fmt.Println("  mingest get <url> [--out-dir <dir>] [--name-template <tpl>] [--timeout <dur>] [--cookies-browser <v>] [--cookies-profile <name>] [--keyring <v>] [--limit-rate <rate>] [--max-filesize <size>] [--sleep-interval <sec>] [--section <range>] [--sponsorblock <remove|mark>] [--embed-chapters] [--no-embed-thumbnail] [--no-metadata] [--live-from-start] [--wait-for-video <sec|min-max>] [--restrict-filenames] [--proxy <url>] [--on-complete <cmd>] [--verify] [--info] [--asset-id-only] [--json]")
+	fmt.Println("  mingest prep <asset_ref> --goal <subtitle|highlights|shorts|chapters> [--lang <auto|zh|en|all>] [--max-clips <n>] [--clip-seconds <sec>] [--strategy <even|frontload|skip-intro>] [--min-gap <sec>] [--single-file] [--summary] [--subtitle-style <clean|shorts>] [--subtitle-segment <off|words|sentences>] [--aspect <16:9|9:16|1:1|4:5>] [--keep-temp] [--whisper-device <cpu|cuda>] [--json]")
+	fmt.Println("  mingest export <asset_ref> --to <premiere|resolve|capcut|youtube-chapters|audacity> [--with <srt,vtt,edl,csv,fcpxml,chapters,labels,json>] [--timeline-layout <sequential|source>] [--out-dir <dir>] [--zip] [--archive-format <zip|tgz>] [--json]")
+	fmt.Println("  mingest verify <asset_ref> [--json]")
+	fmt.Println("  mingest open <asset_ref> [--what <html|bundle|export>] [--bundle-dir <dir>]")
+	fmt.Println("  mingest thumbnail <asset_ref> [--at <sec|percent>] [--grid <NxM>] [--out <path>] [--json]")
+	fmt.Println("  mingest transcribe <asset_ref|path> [--lang <auto|zh|en|...>] [--model <name>] [--format <srt|vtt|txt>] [--out <path>] [--whisper-device <cpu|cuda>] [--json]")
+	fmt.Println("  mingest translate-subtitles <asset_ref> --to <lang> [--provider <v>] [--model <name>] [--batch-size <n>] [--out <path>] [--json]")
+	fmt.Println("  mingest version [--check] [--json]")
 	fmt.Println("  mingest ls [--limit <n>] [--query <text>] [--format <table|json>] [--dedupe]")
-	fmt.Println("  mingest doctor <asset_ref> [--target <youtube|bilibili|shorts>] [--strict] [--json]")
-	fmt.Println("  mingest semantic <asset_ref> [--target <youtube|bilibili|shorts>] [--provider <auto|openai|openrouter>] [--model <name>] [--visual-diversity <0-1>] [--apply] [--json]")
-	fmt.Println("  mingest auth <platform>")
+	fmt.Println("  mingest import <path> [--url <url>] [--recursive] [--json]")
+	fmt.Println("  mingest doctor <asset_ref> [--target <youtube|bilibili|shorts|douyin|xiaohongshu>] [--strict] [--explain] [--min-score <n>] [--json]")
+	fmt.Println("  mingest doctor-env [--json]")
+	fmt.Println("  mingest batch --file <urls.txt> --goal <subtitle|highlights|shorts> [--concurrency <n>] [--target <v>] [--out-dir <dir>] [--no-llm]")
+	fmt.Println("  mingest pipeline <url> --to <premiere|resolve|capcut|youtube-chapters|audacity> [--target <v>] [--goal <v>] [--with <formats>] [--out-dir <dir>] [--no-llm] [--zip] [--archive-format <zip|tgz>] [--json]")
+	fmt.Println("  mingest semantic <asset_ref> [--target <youtube|bilibili|shorts|douyin|xiaohongshu>] [--provider <auto|openai|openrouter|anthropic|gemini>] [--model <name>] [--visual-diversity <0-1>] [--pick] [--apply] [--json]")
+	fmt.Println("  mingest auth <platform> [--browser <chrome|chromium|edge|brave>] [--refresh] [--consent-url <url>] [--import <cookies.txt>] [--json]")
+	fmt.Println("  mingest cookies inspect <platform> [--show-values] [--json]")
+	fmt.Println("  mingest cookies export <platform> --out <jar.txt> [--browser <chrome|chromium|edge|brave>] [--json]")
 	fmt.Println()
 	fmt.Println("get 参数:")
 	fmt.Println("  --out-dir <dir>           设置下载目录（默认当前工作目录）")
-	fmt.Println("  --name-template <tpl>     设置输出模板（默认 %(title)s.%(ext)s）")
+	fmt.Println("  --name-template <tpl>     设置输出模板（默认 %(title)s.%(ext)s；不可包含 .. 路径段）")
+	fmt.Println("  --timeout <dur>           单次 yt-dlp 调用超时（如 90s、10m；纯数字按秒），超时后终止进程组")
+	fmt.Println("  --cookies-browser <v>     本次仅从该浏览器读取 cookies（brave|chrome|chromium|edge|firefox|opera|safari|vivaldi|whale），覆盖 MINGEST_BROWSER")
+	fmt.Println("  --cookies-profile <name>  本次使用的浏览器 profile（Firefox 可写 Profile::Container），覆盖 MINGEST_BROWSER_PROFILE")
+	fmt.Println("  --keyring <v>             Linux 下解密 Chromium 系 cookies 使用的 keyring：basictext|gnomekeyring|kwallet|kwallet5|kwallet6（也可写 --cookies-browser chrome+gnomekeyring）")
+	fmt.Println("  --limit-rate <rate>       限制下载速度（字节/秒，可带 K/M/G，如 2M）")
+	fmt.Println("  --max-filesize <size>     文件大小上限（字节，可带 K/M/G，如 2G）；超出时不下载，error_code 为 FILE_TOO_LARGE")
+	fmt.Println("  --sleep-interval <sec>    播放列表各条目下载之间的等待秒数")
+	fmt.Println("  --section <range>         仅下载时间段（yt-dlp --download-sections），如 \"*01:20:00-01:35:00\"、\"*90-300\"")
+	fmt.Println("                            索引记录 section 字段；asset_id 按片段文件计算，与完整下载不同")
+	fmt.Println("  --sponsorblock <v>        SponsorBlock：remove（剪掉赞助段）|mark（写入章节，prep/doctor/semantic 据此避开），默认关闭")
+	fmt.Println("  --sponsorblock-categories <list> 分类（逗号分隔，默认 sponsor）：sponsor,intro,outro,selfpromo,preview,filler,interaction,music_offtopic,poi_highlight,chapter,all")
+	fmt.Println("  --embed-chapters          确保来源章节写入输出文件（含 --no-metadata 时）并在结果中报告；prep 会用这些章节对齐片段起点，--goal chapters 直接采用")
+	fmt.Println("  --no-embed-thumbnail      不嵌入封面（部分站点仅在嵌入封面这一步失败时使用）")
+	fmt.Println("  --no-metadata             不写入标题/作者等元数据")
+	fmt.Println("  --restrict-filenames      文件名仅用 ASCII，去掉空格、emoji 等特殊字符（传给 yt-dlp --restrict-filenames）")
+	fmt.Println("  --live-from-start         允许下载正在进行的直播，并从开头录制到直播结束（默认检测到直播中会拒绝，error_code 为 LIVE_STREAM）")
+	fmt.Println("  --wait-for-video <v>      直播/首映未开始时按间隔秒数（或 最小-最大）轮询等待开播（默认拒绝，error_code 为 LIVE_NOT_STARTED）")
+	fmt.Println("                            poi_highlight/chapter 仅可用于 mark")
+	fmt.Println("  --proxy <url>             yt-dlp 使用的代理（http|https|socks5|socks5h），可用 MINGEST_PROXY 设置")
+	fmt.Println("  --on-complete <cmd>       下载成功并写入索引后经系统 shell 执行命令（sh -c / cmd /C），可用 MINGEST_ON_COMPLETE 设置")
+	fmt.Println("                            环境变量: MINGEST_OUTPUT_PATH、MINGEST_ASSET_ID、MINGEST_URL；命令失败仅记录日志，不影响退出码")
+	fmt.Println("  --verify                  下载后计算完整文件 SHA-256，写入索引 content_sha256（供 mingest verify 校验）")
+	fmt.Println("  --info                    仅读取元信息（标题、时长、格式、字幕语言），不下载、不写索引；--json 时附带 yt-dlp 完整元信息")
 	fmt.Println("  --asset-id-only           仅输出 asset_id（便于脚本串联）")
 	fmt.Println("  --json                    输出 JSON 结果")
 	fmt.Println()
 	fmt.Println("prep 参数:")
-	fmt.Println("  --goal <v>                处理目标：subtitle|highlights|shorts")
-	fmt.Println("  --lang <v>                语言（默认 auto）")
-	fmt.Println("  --max-clips <n>           建议片段数（默认 subtitle/highlights=5, shorts=3）")
-	fmt.Println("  --clip-seconds <n>        单片段建议时长秒数（默认 subtitle/highlights=45, shorts=30）")
+	fmt.Println("  --goal <v>                处理目标：subtitle|highlights|shorts|chapters（纯音频素材仅支持 subtitle|chapters）")
+	fmt.Println("                            chapters 按字幕停顿与 hook 关键词检测话题切换，额外输出 chapters.csv")
+	fmt.Println("  --lang <v>                语言：auto|zh|en|all（默认 auto）")
+	fmt.Println("                            all 额外下载全部人工字幕轨为 subtitle.<lang>.srt（不含自动字幕）")
+	fmt.Println("  --max-clips <n>           建议片段数（默认 subtitle/highlights=5, shorts=3）；chapters 下为章节上限（默认 12）")
+	fmt.Println("  --clip-seconds <n>        单片段建议时长秒数（默认 subtitle/highlights=45, shorts=30）；chapters 下为最短章节秒数（默认 60）")
+	fmt.Println("  --strategy <v>            片段分布：even（均匀，默认）|frontload（前段更密）|skip-intro（跳过片头片尾）")
+	fmt.Println("  --skip-intro-sec <n>      skip-intro 时片头/片尾各排除的秒数（默认 30）")
+	fmt.Println("  --min-gap <sec>           相邻片段的最小间隔秒数（默认 0；放不下时减少片段数并给出 warning）")
+	fmt.Println("  --label-template <t>      片段名模板，占位符 {index} {type} {title} {start}（如 {title}-{type}-{index}；默认 clip-NN，结果会做文件名安全处理）")
 	fmt.Println("  --subtitle-style <v>      字幕模板风格：clean|shorts（默认 clean）")
+	fmt.Println("  --subtitle-segment <v>    重新切分 subtitle.srt：off|words|sentences（合并过短条目、拆分过长条目；shorts 风格默认 words，否则 off）")
+	fmt.Println("  --aspect <v>              目标画幅：16:9|9:16|1:1|4:5，写入 prep-plan 供 semantic 预览/FCPXML 重构图与 doctor 裁切检查（默认保持源画幅）")
+	fmt.Println("  --bundle-dir <dir>        bundle 根目录（默认素材目录下 .mingest，可用 MINGEST_BUNDLE_ROOT 设置）")
+	fmt.Println("  --single-file             仅输出 prep-plan.json（markers/字幕内容内嵌于 embedded 字段，读取时在内存中解析，不回写文件）")
+	fmt.Println("  --summary                 附带片段分布摘要：覆盖秒数与占比、片段最短/平均/最长、片段间隔")
+	fmt.Println("  --keep-temp               保留平台字幕/Whisper 尝试的临时目录（路径记入 prep-plan 的 attempts[].temp_dir），便于排查")
+	fmt.Println("  --whisper-device <v>      Whisper 运行设备：cpu|cuda（默认取 MINGEST_WHISPER_DEVICE；cuda 启用 fp16）")
 	fmt.Println("  --json                    输出 JSON 结果")
 	fmt.Println()
 	fmt.Println("export 参数:")
-	fmt.Println("  --to <v>                  目标软件：premiere|resolve|capcut（jianying 也可）")
-	fmt.Println("  --with <srt,edl,csv,fcpxml> 导出内容（默认 premiere/resolve=fcpxml,srt；capcut=srt,csv）")
-	fmt.Println("  --out-dir <dir>           导出目录（默认素材目录下 .mingest/export）")
-	fmt.Println("  --zip                     额外打包 zip")
+	fmt.Println("  --to <v>                  目标软件：premiere|resolve|capcut|youtube-chapters|audacity（jianying 也可）")
+	fmt.Println("  --with <srt,vtt,edl,csv,fcpxml,chapters,labels,json> 导出内容（默认 premiere/resolve=fcpxml,srt；capcut=srt,csv；youtube-chapters=chapters；audacity=labels；json 为通用时间线，任意目标可加）")
+	fmt.Println("                            vtt 为 WebVTT（仅 premiere/resolve；goal=shorts 时附带 STYLE 与下三分之一定位）")
+	fmt.Println("                            chapters 为 YouTube 描述章节（MM:SS 标题，首行 00:00；少于 3 章或单章不足 10s 时给出 warning）")
+	fmt.Println("  --out-dir <dir>           导出目录（默认 bundle 根目录下 export）")
+	fmt.Println("  --bundle-dir <dir>        从该 bundle 根目录读取 prep 结果")
+	fmt.Println("  --bundle <ts|path>        使用指定 prep bundle（时间戳目录名或路径，默认最新；找不到时回退到最新）")
+	fmt.Println("  --zip                     额外打包 zip（导出目录内的 manifest.json 一并打包）")
+	fmt.Println("  --archive-format <v>      打包格式：zip|tgz（默认 zip；指定即隐含 --zip；tgz 输出 <导出目录>.tar.gz 并保留文件权限）")
+	fmt.Println("  --timeline-layout <v>     EDL/FCPXML 片段摆放：sequential（首尾相接，粗剪，默认）|source（按源素材时间摆放，中间留空）")
 	fmt.Println("  --json                    输出 JSON 结果")
 	fmt.Println()
 	fmt.Println("ls 参数:")
@@ -270,25 +690,124 @@ func usage() {
 	fmt.Println("  --format <table|json>     输出格式（默认 table）")
 	fmt.Println("  --dedupe                  按 asset_id 去重（仅保留最新一条）")
 	fmt.Println()
+	fmt.Println("import 参数:")
+	fmt.Println("  <path>                    已有的本地媒体文件或目录；写入素材索引，之后可用 asset_id 做 prep/semantic")
+	fmt.Println("  --url <url>               记录来源 URL 并据此推断 platform（仅限单个文件）")
+	fmt.Println("  --recursive, -r           导入目录时包含所有子目录（默认只导入目录下一层的媒体文件）")
+	fmt.Println("  --json                    输出 JSON 结果")
+	fmt.Println()
 	fmt.Println("doctor 参数:")
-	fmt.Println("  --target <v>              发布目标：youtube|bilibili|shorts（默认 youtube）")
+	fmt.Println("  --target <v>              发布目标：youtube|bilibili|shorts|douyin|xiaohongshu（默认 youtube）")
 	fmt.Println("  --strict                  启用更严格阈值")
+	fmt.Println("  --explain                 为未通过的检查附加修复建议（JSON 中为 remediation 字段）")
+	fmt.Println("  --min-score <n>           健康分（0-100，fail 扣 30、warn 扣 8）低于 n 时返回失败")
+	fmt.Println("  --bundle-dir <dir>        从该 bundle 根目录读取 prep 结果")
+	fmt.Println("  --bundle <ts|path>        使用指定 prep bundle（时间戳目录名或路径，默认最新；找不到时回退到最新）")
 	fmt.Println("  --json                    输出 JSON 诊断结果")
 	fmt.Println()
+	fmt.Println("batch 参数:")
+	fmt.Println("  --file <path>             URL 列表文件（每行一个，# 开头为注释）")
+	fmt.Println("  --goal <v>                prep 处理目标：subtitle|highlights|shorts")
+	fmt.Println("  --concurrency <n>         并发处理数（默认 2，范围 1-8）")
+	fmt.Println("  --target <v>              semantic 目标场景（默认 shorts）")
+	fmt.Println("  --out-dir <dir>           下载目录")
+	fmt.Println("  --no-llm                  semantic 跳过 Stage B")
+	fmt.Println("  逐条执行 get → prep → semantic，结束时输出 JSON 汇总；任一失败时返回首个失败项的退出码")
+	fmt.Println()
+	fmt.Println("pipeline 参数:")
+	fmt.Println("  --to <v>                  export 目标：premiere|resolve|capcut|youtube-chapters|audacity（必填）")
+	fmt.Println("  --target <v>              semantic 目标场景（默认 shorts）")
+	fmt.Println("  --goal <v>                prep 处理目标（默认竖屏目标用 shorts，否则 highlights）")
+	fmt.Println("  --with <formats>          export 格式（默认按 --to）")
+	fmt.Println("  --out-dir <dir>           下载目录")
+	fmt.Println("  --no-llm                  semantic 跳过 Stage B")
+	fmt.Println("  --zip                     export 同时打包 zip")
+	fmt.Println("  --archive-format <v>      export 打包格式：zip|tgz")
+	fmt.Println("  --json                    输出合并的 JSON 结果（含各阶段结果）")
+	fmt.Println("  依次执行 get → prep → semantic --apply（doctor 闸门）→ export，在 asset_id 间串联；首个失败即停止并标明阶段")
+	fmt.Println()
 	fmt.Println("semantic 参数:")
-	fmt.Println("  --target <v>              目标场景：youtube|bilibili|shorts（默认 shorts）")
-	fmt.Println("  --provider <v>            LLM 提供方：auto|openai|openrouter（默认 auto）")
-	fmt.Println("  --model <v>               模型名（默认 openai: gpt-4.1-mini / openrouter: openai/gpt-4.1-mini）")
+	fmt.Println("  --target <v>              目标场景：youtube|bilibili|shorts|douyin(15-60s)|xiaohongshu(30-90s)（默认 shorts）")
+	fmt.Println("  --provider <v>            LLM 提供方：auto|openai|openrouter|anthropic|gemini（默认 auto，按 openrouter→openai→anthropic→gemini 取第一个已设置 Key 的）")
+	fmt.Println("  --model <v>               模型名（默认 openai: gpt-4.1-mini / openrouter: openai/gpt-4.1-mini / anthropic: claude-3-5-haiku-latest / gemini: gemini-2.0-flash）")
+	fmt.Println("  --fallback-model <a,b>    主模型限流/5xx/不可用时依次改用的模型（逗号分隔；默认每个 provider 内置一个轻量模型，none 关闭）")
+	fmt.Println("  --llm-timeout <dur>       单次 LLM 请求超时（如 90s、2m；默认 90s）；429/5xx 最多重试 2 次（指数退避）")
+	fmt.Println("  --temperature <0-2>       Stage B 采样温度（默认 0.2；0 更稳定）")
+	fmt.Println("  --seed <n>                Stage B 采样种子（默认不传；并非所有 provider 都支持，仅 OpenAI 等会尽量复现）")
+	fmt.Println("                            anthropic/gemini 通过其 OpenAI 兼容接口调用")
 	fmt.Println("  --base-url <url>          自定义 OpenAI 兼容网关地址（可用于 OpenRouter）")
+	fmt.Println("  --proxy <url>             LLM 请求使用的代理，可用 MINGEST_PROXY 设置")
 	fmt.Println("  --api-key <key>           API Key（也可通过环境变量注入）")
-	fmt.Println("  --candidate-limit <n>     Stage A 候选上限（默认 20）")
+	fmt.Println("  --candidate-limit <n>     Stage A 候选上限（1-100；默认按时长约每分钟 1 个，不少于 --top-k，时长未知时为 20）")
 	fmt.Println("  --preview-limit <n>       Stage D 预览数量（默认 8）")
+	fmt.Println("  --normalize-audio         预览编码时用 ffmpeg loudnorm（EBU R128）统一响度；素材无音轨时跳过")
+	fmt.Println("  --loudness-target <lufs>  响度目标（默认 -14 LUFS，范围 -70 到 -5）")
+	fmt.Println("  --loudnorm-two-pass       两遍 loudnorm：先测量再线性增益，更准确但每段多解码一次，耗时约翻倍")
+	fmt.Println("  --burn-subs               把与片段相交的字幕烧录到预览画面（需真实字幕；只有字幕模板时跳过）")
+	fmt.Println("  --vertical                预览输出 9:16（1080x1920），仅 --target shorts|douyin|xiaohongshu；默认保持原画幅")
+	fmt.Println("  --aspect <v>              预览画幅：16:9|9:16|1:1|4:5（默认取 prep-plan 中的 aspect，否则保持原画幅）；--apply 时写回 prep-plan")
+	fmt.Println("  --reframe <v>             重构图方式：center（居中裁切，默认）|blur-pad（完整画面叠在模糊背景上）")
 	fmt.Println("  --visual-diversity <0-1>  视觉去重强度（默认 0.5，越大越严格）")
 	fmt.Println("  --top-k <n>               Stage C/E 最终片段数（默认 3）")
+	fmt.Println("  --visual-gaps             Stage A 额外在字幕空档内生成画面候选（type=visual，约占候选上限 1/5）")
+	fmt.Println("  --visual-gap-sec <sec>    字幕空档超过该秒数才生成画面候选（默认 20）")
 	fmt.Println("  --no-llm                  跳过 Stage B，仅使用规则分")
+	fmt.Println("  --resume                  续跑最近一次 semantic 目录：输入未变的 Stage A 候选与 Stage B 打分直接复用（记录于 semantic-state.json）")
+	fmt.Println("  --save-prompt             在 stage-b-llm.json 中保存实际发送的 system/user prompt（便于复现与 prompt 回归对比）")
 	fmt.Println("  --decisions <path>        Stage E 使用指定评审决策文件")
-	fmt.Println("  --apply                   Stage E：写回 prep-plan 并执行 doctor 闸门")
+	fmt.Println("  --pick                    在终端逐条评审候选（k 保留 / d 丢弃 / 数字设排名），结果写入评审决策文件")
+	fmt.Println("  --apply                   Stage E：写回 prep-plan 并执行 doctor 闸门；同时在 semantic 目录 clips/ 下写出每段从 00:00 起算的字幕")
+	fmt.Println("  --chronological           最终片段按时间线排序输出（rank 字段保留评分顺序）")
+	fmt.Println("  --explain-scores          为每个入选片段写出评分拆解（signals/base/semantic/final/novelty）到 stage-c-explain.json 并打印")
+	fmt.Println("  --enforce-spread          入选片段都不在时间线最后三分之一时，用该段得分最高的候选替换最后一个入选片段")
+	fmt.Println("  --position-bias <v>       按片段在时间线上的位置微调得分：front（偏向开头，适合新闻）|back（偏向结尾，适合教程）|none（默认）")
+	fmt.Println("  --label-template <t>      --apply 写回的片段名模板（占位符同 prep，{type} 为候选类型；默认沿用 prep 的模板，否则 semantic-NN）")
+	fmt.Println("  --self-contained-review   把预览视频以 base64 内嵌进 review.html，生成可单独发送的单文件（体积较大，默认按相对路径引用）")
 	fmt.Println("  --strict                  Stage E doctor 使用严格阈值")
+	fmt.Println("  --bundle-dir <dir>        bundle 根目录（读取 prep、写入 semantic 结果）")
+	fmt.Println("  --bundle <ts|path>        使用指定 prep bundle（时间戳目录名或路径，默认最新；找不到时回退到最新）")
+	fmt.Println("  --json                    输出 JSON 结果")
+	fmt.Println()
+	fmt.Println("open 参数:")
+	fmt.Println("  --what <v>                打开对象：html（最新 semantic review.html，默认）|bundle（最新 prep bundle 目录）|export（最新导出目录）")
+	fmt.Println("  --bundle-dir <dir>        从该 bundle 根目录查找")
+	fmt.Println("  使用 xdg-open/open/start 打开；无可用程序（如无图形界面）时仅打印路径并正常退出")
+	fmt.Println()
+	fmt.Println("thumbnail 参数:")
+	fmt.Println("  --at <sec|percent>        截图位置：秒数（如 90）或百分比（如 25%），默认 50%")
+	fmt.Println("  --grid <NxM>              生成 N 列 M 行的缩略图墙，全片均匀取帧（不能与 --at 同用）")
+	fmt.Println("  --out <path>              输出图片路径（.jpg|.png）或目录（默认素材同目录 <名称>.thumbnail.jpg / <名称>.contact-sheet.jpg）")
+	fmt.Println("  --json                    输出 JSON 结果")
+	fmt.Println()
+	fmt.Println("transcribe 参数:")
+	fmt.Println("  --lang <v>                转写语言（Whisper 语言代码，如 zh|en|ja；默认 auto 自动检测并打印检测结果）")
+	fmt.Println("  --model <v>               Whisper 模型（默认取 MINGEST_WHISPER_MODEL，否则 small）")
+	fmt.Println("  --whisper-device <v>      Whisper 运行设备：cpu|cuda（默认取 MINGEST_WHISPER_DEVICE）")
+	fmt.Println("  --format <v>              输出格式：srt|vtt|txt（默认 srt，或取 --out 的扩展名）")
+	fmt.Println("  --out <path>              输出文件或目录（默认素材同目录 <名称>.<格式>）")
+	fmt.Println("  --json                    输出 JSON 结果（含字幕质量评分，仅供参考）")
+	fmt.Println()
+	fmt.Println("translate-subtitles 参数:")
+	fmt.Println("  --to <lang>               目标语言（如 zh|en|ja）；逐条翻译 prep 选中的字幕，时间轴保持不变")
+	fmt.Println("  --provider/--model/--api-key/--base-url/--proxy/--llm-timeout 同 semantic")
+	fmt.Println("  --batch-size <n>          每次请求的字幕条数（1-200，默认 40）")
+	fmt.Println("  --out <path>              输出 .srt 路径（默认字幕同目录 <名称>.<lang>.translated.srt）")
+	fmt.Println("  --bundle <ts|path>        使用指定 prep bundle（时间戳目录名或路径，默认最新；找不到时回退到最新）")
+	fmt.Println("  --json                    输出 JSON 结果（含实际使用的 provider/model）")
+	fmt.Println()
+	fmt.Println("auth 参数:")
+	fmt.Println("  --browser <v>             CDP 使用的浏览器：chrome|chromium|edge|brave（默认取 MINGEST_BROWSER，否则 chrome；各自独立 profile）")
+	fmt.Println("  --refresh                 先用无界面方式刷新工具专用 profile 的 cookies；未登录时再进入交互登录")
+	fmt.Println("  --consent-url <url>       先无界面打开该页面并自动点击已知的同意/年龄确认按钮；未拿到所需 cookies 时再进入交互登录")
+	fmt.Println("  --import <file>           不开浏览器，直接采用已有的 Netscape cookie 文件：按平台域名过滤、需含登录 cookie，再原子替换 cookie 缓存（别名 --cookies-from-file）")
+	fmt.Println("  --json                    输出 JSON 结果（含 cookie_file 与 authenticated；失败时含 error_code）")
+	fmt.Println()
+	fmt.Println("cookies 参数:")
+	fmt.Println("  inspect <platform>        列出 cookie 缓存中每条 cookie 的名称、域名、secure 与过期时间；* 标记登录态 cookie")
+	fmt.Println("  --show-values             显示 cookie 值（默认仅显示长度）")
+	fmt.Println("  export <platform>         从工具专用浏览器 profile（同 auth）导出仅含该平台域名的 Netscape cookie 文件，供 CI 等无浏览器环境使用；不写入内部缓存")
+	fmt.Println("  --out <path>              export 的输出路径（必填；文件权限 0600，等同登录凭据，请妥善保管）")
+	fmt.Println("  --browser <v>             export 使用的浏览器：chrome|chromium|edge|brave（同 auth）")
 	fmt.Println("  --json                    输出 JSON 结果")
 	fmt.Println()
 	fmt.Println("平台:")
@@ -299,19 +818,51 @@ func usage() {
 	fmt.Println("  - 自动检测并调用 yt-dlp / ffmpeg / ffprobe / deno|node")
 	fmt.Println("  - 自动维护 cookies 缓存（优先使用；必要时从浏览器读取 cookies 刷新账户登录信息）")
 	fmt.Println("  - 若 Windows 下 Chrome cookies 读取/解密失败，可用 `mingest auth <platform>`（CDP）准备工具专用账户登录信息")
+	fmt.Println("  - 遇到 App-Bound Cookie Encryption 时自动改走 CDP；工具专用 profile 未登录且处于交互终端时会直接引导登录")
+	fmt.Println("  - 任意命令可加 --quiet（仅输出结果与警告/错误，隐藏 info 日志和 yt-dlp 进度）或 --verbose（debug 日志），优先于 MINGEST_LOG_LEVEL")
+	fmt.Println("  - 任意命令可加 --json-pretty 输出缩进 JSON（隐含 --json），便于终端查看；默认仍为紧凑 JSON")
+	fmt.Println("  - 任意命令可加 --library <dir>（或 MINGEST_LIBRARY）：所有 bundle 集中存放在 <dir>/<asset_id>/{prep,semantic,export}，asset.json 记录素材路径；显式 --bundle-dir 仍优先")
+	fmt.Println("  - 下载中断时保留 .part 部分文件（JSON 中为 partial_path，不写入索引）；重新执行相同命令会自动续传")
+	fmt.Println()
+	fmt.Println("配置文件:")
+	fmt.Println("  - 默认路径: <状态目录>/config.json（Linux: ~/.config/mingest/config.json），可用 MINGEST_CONFIG 指定")
+	fmt.Println("  - 按命令设置默认参数，键为去掉 `--` 的参数名，如 {\"semantic\": {\"target\": \"shorts\"}, \"export\": {\"zip\": true}}")
+	fmt.Println("  - \"env\" 段可设置环境变量默认值，如 {\"env\": {\"MINGEST_BROWSER\": \"firefox\"}}")
+	fmt.Println("  - 优先级: 命令行参数 > 环境变量 > 配置文件 > 内置默认值")
+	fmt.Println("  - 配置中为 true 的开关可用 --no-<键名> 单次关闭（如 --no-zip）；--library 请用 env 段的 MINGEST_LIBRARY")
 	fmt.Println()
 	fmt.Println("可选环境变量:")
-	fmt.Println("  - MINGEST_BROWSER=chrome|firefox|chromium|edge")
+	fmt.Println("  - MINGEST_LANG=zh|en（界面语言，默认跟随系统 locale，否则中文）")
+	fmt.Println("  - MINGEST_CONFIG=/path/to/config.json")
+	fmt.Println("  - MINGEST_STATE_DIR=/path/to/state（替换状态目录：素材索引、cookies 缓存、配置文件与浏览器 profile 均放在此处；不存在时自动创建，需可写）")
+	fmt.Println("  - MINGEST_BUNDLE_ROOT=/path/to/bundles（prep/semantic/export 输出根目录，适用于只读媒体目录）")
+	fmt.Println("  - MINGEST_BROWSER=chrome|firefox|chromium|edge|brave[+basictext|gnomekeyring|kwallet|kwallet5|kwallet6]")
 	fmt.Println("  - MINGEST_BROWSER_PROFILE=Default|Profile 1|...")
+	fmt.Println("    Firefox 容器: <profile>::<container> 或 ::<container>（如 default-release::Work）")
 	fmt.Println("  - MINGEST_JS_RUNTIME=node|deno")
 	fmt.Println("  - MINGEST_CHROME_PATH=C:\\\\Path\\\\To\\\\chrome.exe")
+	fmt.Println("  - MINGEST_WHISPER_BACKEND=openai|faster|cpp（本地转写后端，默认 openai；faster 查找 whisper-ctranslate2/faster-whisper，cpp 查找 whisper-cli 并需 ffmpeg）")
 	fmt.Println("  - MINGEST_WHISPER_PATH=/path/to/whisper")
-	fmt.Println("  - MINGEST_WHISPER_MODEL=tiny|base|small|medium|large")
+	fmt.Println("  - MINGEST_WHISPER_MODEL=tiny|base|small|medium|large（cpp 后端为 models/ggml-<名称>.bin 或模型文件路径）")
+	fmt.Println("  - MINGEST_WHISPER_DEVICE=cpu|cuda（本地转写设备；openai 后端 cuda 启用 fp16，未设置时按 CPU 安全方式关闭 fp16）")
 	fmt.Println("  - MINGEST_OPENAI_API_KEY / OPENAI_API_KEY")
 	fmt.Println("  - MINGEST_OPENROUTER_API_KEY / OPENROUTER_API_KEY")
 	fmt.Println("  - MINGEST_OPENROUTER_BASE_URL=https://openrouter.ai/api/v1")
+	fmt.Println("  - MINGEST_ANTHROPIC_API_KEY / ANTHROPIC_API_KEY（MINGEST_ANTHROPIC_BASE_URL 可覆盖接口地址）")
+	fmt.Println("  - MINGEST_GEMINI_API_KEY / GEMINI_API_KEY（MINGEST_GEMINI_BASE_URL 可覆盖接口地址）")
 	fmt.Println("  - MINGEST_LLM_MODEL=gpt-4.1-mini|openai/gpt-4.1-mini")
-	fmt.Println("  - MINGEST_LOG_LEVEL=debug|info|warn|error（默认 info）")
+	fmt.Println("  - MINGEST_DOWNLOAD_TIMEOUT=10m（get 下载超时，--timeout 优先）")
+	fmt.Println("  - MINGEST_CDP_TIMEOUT=30s（CDP 单次调用读取超时，默认 30s）")
+	fmt.Println("  - MINGEST_RATE_LIMIT_COOLDOWN=60s（遇到 HTTP 429 后的基础等待，默认按平台：YouTube 60s、B 站 30s）")
+	fmt.Println("  - MINGEST_LLM_TIMEOUT=90s（semantic 单次 LLM 请求超时，--llm-timeout 优先）")
+	fmt.Println("  - MINGEST_RELEASE_URL=<url>（version --check 查询的发布接口，默认 GitHub Releases latest）")
+	fmt.Println("  - MINGEST_KEEP_TEMP=1（等同 prep --keep-temp）")
+	fmt.Println("  - MINGEST_PROXY=http://127.0.0.1:7890|socks5://host:1080（yt-dlp 与 LLM 请求的代理，--proxy 优先；CDP 本地连接不走代理）")
+	fmt.Println("  - MINGEST_ON_COMPLETE=<cmd>（get 下载完成后执行的命令，--on-complete 优先）")
+	fmt.Println("  - MINGEST_ON_COMPLETE_TIMEOUT=10m（on-complete 命令的超时，默认 10m，超时后终止命令）")
+	fmt.Println("  - MINGEST_LIBRARY=/path/to/library（集中 bundle 库，等同 --library）")
+	fmt.Println("  - MINGEST_JSON_INDENT=1（JSON 输出缩进，等同 --json-pretty）")
+	fmt.Println("  - MINGEST_LOG_LEVEL=debug|info|warn|error（默认 info；--quiet/--verbose 优先）")
 	fmt.Println("  - MINGEST_LOG_FORMAT=text|json（默认 text）")
 	fmt.Println()
 	fmt.Println("退出码:")
@@ -323,6 +874,286 @@ func usage() {
 	fmt.Println("  - 40: 下载失败（DOWNLOAD_FAILED）")
 	fmt.Println("  - 41: doctor 检查未通过（DOCTOR_FAILED）")
 	fmt.Println("  - 42: semantic 流程执行失败（SEMANTIC_FAILED）")
+	fmt.Println("  - 43: 文件校验失败（VERIFY_FAILED）")
+	fmt.Println("  - 130: 被 Ctrl-C/SIGTERM 中断（已终止 yt-dlp 与 CDP 浏览器并清理临时 cookie 文件）")
+}
+func usageEN() {
+	fmt.Println("Usage:")
+	fmt.Println("  mingest get <url> [--out-dir <dir>] [--name-template <tpl>] [--timeout <dur>] [--cookies-browser <v>] [--cookies-profile <name>] [--keyring <v>] [--limit-rate <rate>] [--max-filesize <size>] [--sleep-interval <sec>] [--section <range>] [--sponsorblock <remove|mark>] [--embed-chapters] [--no-embed-thumbnail] [--no-metadata] [--live-from-start] [--wait-for-video <sec|min-max>] [--restrict-filenames] [--proxy <url>] [--on-complete <cmd>] [--verify] [--info] [--asset-id-only] [--json]")
+	fmt.Println("  mingest prep <asset_ref> --goal <subtitle|highlights|shorts|chapters> [--lang <auto|zh|en|all>] [--max-clips <n>] [--clip-seconds <sec>] [--strategy <even|frontload|skip-intro>] [--min-gap <sec>] [--single-file] [--summary] [--subtitle-style <clean|shorts>] [--subtitle-segment <off|words|sentences>] [--aspect <16:9|9:16|1:1|4:5>] [--keep-temp] [--whisper-device <cpu|cuda>] [--json]")
+	fmt.Println("  mingest export <asset_ref> --to <premiere|resolve|capcut|youtube-chapters|audacity> [--with <srt,vtt,edl,csv,fcpxml,chapters,labels,json>] [--timeline-layout <sequential|source>] [--out-dir <dir>] [--zip] [--archive-format <zip|tgz>] [--json]")
+	fmt.Println("  mingest verify <asset_ref> [--json]")
+	fmt.Println("  mingest open <asset_ref> [--what <html|bundle|export>] [--bundle-dir <dir>]")
+	fmt.Println("  mingest thumbnail <asset_ref> [--at <sec|percent>] [--grid <NxM>] [--out <path>] [--json]")
+	fmt.Println("  mingest transcribe <asset_ref|path> [--lang <auto|zh|en|...>] [--model <name>] [--format <srt|vtt|txt>] [--out <path>] [--whisper-device <cpu|cuda>] [--json]")
+	fmt.Println("  mingest translate-subtitles <asset_ref> --to <lang> [--provider <v>] [--model <name>] [--batch-size <n>] [--out <path>] [--json]")
+	fmt.Println("  mingest version [--check] [--json]")
+	fmt.Println("  mingest ls [--limit <n>] [--query <text>] [--format <table|json>] [--dedupe]")
+	fmt.Println("  mingest import <path> [--url <url>] [--recursive] [--json]")
+	fmt.Println("  mingest doctor <asset_ref> [--target <youtube|bilibili|shorts|douyin|xiaohongshu>] [--strict] [--explain] [--min-score <n>] [--json]")
+	fmt.Println("  mingest doctor-env [--json]")
+	fmt.Println("  mingest batch --file <urls.txt> --goal <subtitle|highlights|shorts> [--concurrency <n>] [--target <v>] [--out-dir <dir>] [--no-llm]")
+	fmt.Println("  mingest pipeline <url> --to <premiere|resolve|capcut|youtube-chapters|audacity> [--target <v>] [--goal <v>] [--with <formats>] [--out-dir <dir>] [--no-llm] [--zip] [--archive-format <zip|tgz>] [--json]")
+	fmt.Println("  mingest semantic <asset_ref> [--target <youtube|bilibili|shorts|douyin|xiaohongshu>] [--provider <auto|openai|openrouter|anthropic|gemini>] [--model <name>] [--visual-diversity <0-1>] [--pick] [--apply] [--json]")
+	fmt.Println("  mingest auth <platform> [--browser <chrome|chromium|edge|brave>] [--refresh] [--consent-url <url>] [--import <cookies.txt>] [--json]")
+	fmt.Println("  mingest cookies inspect <platform> [--show-values] [--json]")
+	fmt.Println("  mingest cookies export <platform> --out <jar.txt> [--browser <chrome|chromium|edge|brave>] [--json]")
+	fmt.Println()
+	fmt.Println("get options:")
+	fmt.Println("  --out-dir <dir>           Download directory (default: current working directory)")
+	fmt.Println("  --name-template <tpl>     Output template (default %(title)s.%(ext)s; \"..\" path segments are rejected)")
+	fmt.Println("  --timeout <dur>           Timeout per yt-dlp call (e.g. 90s, 10m; bare numbers are seconds); kills the process group")
+	fmt.Println("  --cookies-browser <v>     Read cookies only from this browser for this run (brave|chrome|chromium|edge|firefox|opera|safari|vivaldi|whale); overrides MINGEST_BROWSER")
+	fmt.Println("  --cookies-profile <name>  Browser profile for this run (Firefox accepts Profile::Container); overrides MINGEST_BROWSER_PROFILE")
+	fmt.Println("  --keyring <v>             Linux keyring for decrypting Chromium-family cookies: basictext|gnomekeyring|kwallet|kwallet5|kwallet6 (or --cookies-browser chrome+gnomekeyring)")
+	fmt.Println("  --limit-rate <rate>       Limit download speed (bytes/s, optional K/M/G suffix, e.g. 2M)")
+	fmt.Println("  --max-filesize <size>     Size cap (bytes, optional K/M/G suffix, e.g. 2G); larger files are skipped with error_code FILE_TOO_LARGE")
+	fmt.Println("  --sleep-interval <sec>    Seconds to wait between playlist items")
+	fmt.Println("  --section <range>         Download only a time range (yt-dlp --download-sections), e.g. \"*01:20:00-01:35:00\", \"*90-300\"")
+	fmt.Println("                            The index records section; asset_id hashes the section file, so it differs from a full download")
+	fmt.Println("  --sponsorblock <v>        SponsorBlock: remove (cut sponsor segments)|mark (embed as chapters so prep/doctor/semantic avoid them); off by default")
+	fmt.Println("  --sponsorblock-categories <list> Categories (comma-separated, default sponsor): sponsor,intro,outro,selfpromo,preview,filler,interaction,music_offtopic,poi_highlight,chapter,all")
+	fmt.Println("  --embed-chapters          Make sure the source's chapters are in the output file (even with --no-metadata) and report it; prep snaps clip starts to them and --goal chapters uses them directly")
+	fmt.Println("  --no-embed-thumbnail      Don't embed the thumbnail (for sources where only that step fails)")
+	fmt.Println("  --no-metadata             Don't write title/uploader metadata")
+	fmt.Println("  --restrict-filenames      ASCII-only file names without spaces, emoji or other special characters (yt-dlp --restrict-filenames)")
+	fmt.Println("  --live-from-start         Allow ongoing live streams and record from the beginning until they end (by default live streams are refused with error_code LIVE_STREAM)")
+	fmt.Println("  --wait-for-video <v>      For streams/premieres that have not started, poll every <sec> (or min-max) until they do (refused by default with error_code LIVE_NOT_STARTED)")
+	fmt.Println("                            poi_highlight/chapter only work with mark")
+	fmt.Println("  --proxy <url>             Proxy for yt-dlp (http|https|socks5|socks5h); can be set with MINGEST_PROXY")
+	fmt.Println("  --on-complete <cmd>       Run a command through the system shell (sh -c / cmd /C) after a successful download is indexed; or MINGEST_ON_COMPLETE")
+	fmt.Println("                            Env: MINGEST_OUTPUT_PATH, MINGEST_ASSET_ID, MINGEST_URL; a failing command is only logged and never changes the exit code")
+	fmt.Println("  --verify                  Hash the whole file (SHA-256) after download and store content_sha256 in the index (for mingest verify)")
+	fmt.Println("  --info                    Fetch metadata only (title, duration, formats, subtitle languages); no download, no index; --json includes yt-dlp's full metadata")
+	fmt.Println("  --asset-id-only           Print only the asset_id (for scripting)")
+	fmt.Println("  --json                    Print the result as JSON")
+	fmt.Println()
+	fmt.Println("prep options:")
+	fmt.Println("  --goal <v>                Goal: subtitle|highlights|shorts|chapters (audio-only media supports subtitle|chapters)")
+	fmt.Println("                            chapters detects topic shifts from subtitle pauses and hook keywords and also writes chapters.csv")
+	fmt.Println("  --lang <v>                Language: auto|zh|en|all (default auto)")
+	fmt.Println("                            all also saves every manual subtitle track as subtitle.<lang>.srt (auto captions excluded)")
+	fmt.Println("  --max-clips <n>           Suggested clip count (default subtitle/highlights=5, shorts=3); chapter cap for chapters (default 12)")
+	fmt.Println("  --clip-seconds <n>        Suggested clip length in seconds (default subtitle/highlights=45, shorts=30); minimum chapter length for chapters (default 60)")
+	fmt.Println("  --strategy <v>            Clip placement: even (default)|frontload (denser early)|skip-intro (skip intro/outro)")
+	fmt.Println("  --skip-intro-sec <n>      Seconds excluded at each end with skip-intro (default 30)")
+	fmt.Println("  --min-gap <sec>           Minimum gap between adjacent clips (default 0; drops clips with a warning when they don't fit)")
+	fmt.Println("  --label-template <t>      Clip name template with {index} {type} {title} {start} (e.g. {title}-{type}-{index}; default clip-NN; sanitized for file names)")
+	fmt.Println("  --subtitle-style <v>      Subtitle template style: clean|shorts (default clean)")
+	fmt.Println("  --subtitle-segment <v>    Re-cut subtitle.srt: off|words|sentences (merge tiny cues, split long ones; default words for the shorts style, else off)")
+	fmt.Println("  --aspect <v>              Intended aspect: 16:9|9:16|1:1|4:5, stored in prep-plan for semantic preview/FCPXML reframing and doctor crop checks (default: source aspect)")
+	fmt.Println("  --bundle-dir <dir>        Bundle root (default .mingest next to the media; or MINGEST_BUNDLE_ROOT)")
+	fmt.Println("  --single-file             Write only prep-plan.json (markers/subtitles embedded under \"embedded\", read in memory, never written back)")
+	fmt.Println("  --summary                 Add a clip distribution summary: covered seconds and ratio, min/mean/max clip length, inter-clip gaps")
+	fmt.Println("  --keep-temp               Keep platform-subtitle/Whisper temp dirs (recorded as attempts[].temp_dir) for debugging")
+	fmt.Println("  --whisper-device <v>      Whisper device: cpu|cuda (default MINGEST_WHISPER_DEVICE; cuda enables fp16)")
+	fmt.Println("  --json                    Print the result as JSON")
+	fmt.Println()
+	fmt.Println("export options:")
+	fmt.Println("  --to <v>                  Target: premiere|resolve|capcut|youtube-chapters|audacity (jianying also accepted)")
+	fmt.Println("  --with <srt,vtt,edl,csv,fcpxml,chapters,labels,json> What to export (default premiere/resolve=fcpxml,srt; capcut=srt,csv; youtube-chapters=chapters; audacity=labels; json is a neutral timeline any target can add)")
+	fmt.Println("                            vtt is WebVTT (premiere/resolve only; goal=shorts adds a STYLE block and lower-third placement)")
+	fmt.Println("                            chapters is YouTube description chapters (MM:SS title, first line 00:00; warns under 3 chapters or any under 10s)")
+	fmt.Println("  --out-dir <dir>           Export directory (default: export under the bundle root)")
+	fmt.Println("  --bundle-dir <dir>        Read prep results from this bundle root")
+	fmt.Println("  --bundle <ts|path>        Use a specific prep bundle (timestamp dir name or path; default latest, falls back to latest if not found)")
+	fmt.Println("  --zip                     Also create a zip (includes manifest.json from the export directory)")
+	fmt.Println("  --archive-format <v>      Archive format: zip|tgz (default zip; implies --zip; tgz writes <export dir>.tar.gz and keeps file modes)")
+	fmt.Println("  --timeline-layout <v>     EDL/FCPXML clip placement: sequential (back to back, rough cut; default) | source (at source times, with gaps)")
+	fmt.Println("  --json                    Print the result as JSON")
+	fmt.Println()
+	fmt.Println("ls options:")
+	fmt.Println("  --limit <n>               Return at most n records (default 20)")
+	fmt.Println("  --query <text>            Filter by keyword (matches asset_id/url/title/path/platform)")
+	fmt.Println("  --format <table|json>     Output format (default table)")
+	fmt.Println("  --dedupe                  Keep only the latest record per asset_id")
+	fmt.Println()
+	fmt.Println("import options:")
+	fmt.Println("  <path>                    Existing local media file or directory; added to the asset index for prep/semantic by asset_id")
+	fmt.Println("  --url <url>               Record the source URL and infer the platform from it (single file only)")
+	fmt.Println("  --recursive, -r           Include all subdirectories when importing a directory (default: top level only)")
+	fmt.Println("  --json                    Print the result as JSON")
+	fmt.Println()
+	fmt.Println("doctor options:")
+	fmt.Println("  --target <v>              Publish target: youtube|bilibili|shorts|douyin|xiaohongshu (default youtube)")
+	fmt.Println("  --strict                  Use stricter thresholds")
+	fmt.Println("  --explain                 Attach remediation hints to non-passing checks (remediation field in JSON)")
+	fmt.Println("  --min-score <n>           Fail when the health score (0-100; fail -30, warn -8) is below n")
+	fmt.Println("  --bundle-dir <dir>        Read prep results from this bundle root")
+	fmt.Println("  --bundle <ts|path>        Use a specific prep bundle (timestamp dir name or path; default latest, falls back to latest if not found)")
+	fmt.Println("  --json                    Print the diagnosis as JSON")
+	fmt.Println()
+	fmt.Println("batch options:")
+	fmt.Println("  --file <path>             URL list file (one per line, # starts a comment)")
+	fmt.Println("  --goal <v>                prep goal: subtitle|highlights|shorts")
+	fmt.Println("  --concurrency <n>         Parallel items (default 2, range 1-8)")
+	fmt.Println("  --target <v>              semantic target (default shorts)")
+	fmt.Println("  --out-dir <dir>           Download directory")
+	fmt.Println("  --no-llm                  Skip semantic Stage B")
+	fmt.Println("  Runs get → prep → semantic per URL and prints a JSON summary; exits with the first failing item's code")
+	fmt.Println()
+	fmt.Println("pipeline options:")
+	fmt.Println("  --to <v>                  export target: premiere|resolve|capcut|youtube-chapters|audacity (required)")
+	fmt.Println("  --target <v>              semantic target (default shorts)")
+	fmt.Println("  --goal <v>                prep goal (default shorts for vertical targets, else highlights)")
+	fmt.Println("  --with <formats>          export formats (default depends on --to)")
+	fmt.Println("  --out-dir <dir>           Download directory")
+	fmt.Println("  --no-llm                  Skip semantic Stage B")
+	fmt.Println("  --zip                     Also zip the export")
+	fmt.Println("  --archive-format <v>      Export archive format: zip|tgz")
+	fmt.Println("  --json                    Print a combined JSON result (includes each stage)")
+	fmt.Println("  Runs get → prep → semantic --apply (doctor gate) → export, threading the asset_id; stops at the first failure and names the stage")
+	fmt.Println()
+	fmt.Println("semantic options:")
+	fmt.Println("  --target <v>              Target: youtube|bilibili|shorts|douyin (15-60s)|xiaohongshu (30-90s) (default shorts)")
+	fmt.Println("  --provider <v>            LLM provider: auto|openai|openrouter|anthropic|gemini (default auto: first with a key in openrouter→openai→anthropic→gemini order)")
+	fmt.Println("  --model <v>               Model name (default openai: gpt-4.1-mini / openrouter: openai/gpt-4.1-mini / anthropic: claude-3-5-haiku-latest / gemini: gemini-2.0-flash)")
+	fmt.Println("  --fallback-model <a,b>    Models tried in order when the primary is rate-limited/5xx/unavailable (comma-separated; defaults to a small per-provider model, none disables)")
+	fmt.Println("  --llm-timeout <dur>       Per-request LLM timeout (e.g. 90s, 2m; default 90s); 429/5xx are retried up to 2 times with backoff")
+	fmt.Println("  --temperature <0-2>       Stage B sampling temperature (default 0.2; 0 is more stable)")
+	fmt.Println("  --seed <n>                Stage B sampling seed (unset by default; not every provider honors it, reproducibility is best-effort)")
+	fmt.Println("                            anthropic/gemini are called through their OpenAI-compatible endpoints")
+	fmt.Println("  --base-url <url>          Custom OpenAI-compatible gateway (works for OpenRouter)")
+	fmt.Println("  --proxy <url>             Proxy for LLM requests; can be set with MINGEST_PROXY")
+	fmt.Println("  --api-key <key>           API key (environment variables also work)")
+	fmt.Println("  --candidate-limit <n>     Stage A candidate cap (1-100; default about 1 per minute of video, at least --top-k, 20 if the duration is unknown)")
+	fmt.Println("  --preview-limit <n>       Stage D preview count (default 8)")
+	fmt.Println("  --normalize-audio         Normalize preview loudness with ffmpeg loudnorm (EBU R128); skipped when the asset has no audio")
+	fmt.Println("  --loudness-target <lufs>  Loudness target (default -14 LUFS, range -70 to -5)")
+	fmt.Println("  --loudnorm-two-pass       Two-pass loudnorm: measure first, then apply linear gain; more accurate but decodes each clip twice (about 2x slower)")
+	fmt.Println("  --burn-subs               Burn the cues overlapping each clip into its preview (needs a real subtitle; skipped for templates)")
+	fmt.Println("  --vertical                Render previews as 9:16 (1080x1920), --target shorts|douyin|xiaohongshu only; default keeps the source aspect ratio")
+	fmt.Println("  --aspect <v>              Preview aspect: 16:9|9:16|1:1|4:5 (default: the prep-plan aspect, else the source); written back on --apply")
+	fmt.Println("  --reframe <v>             Reframing: center (center crop, default)|blur-pad (full frame over a blurred background)")
+	fmt.Println("  --visual-diversity <0-1>  Visual de-duplication strength (default 0.5; higher is stricter)")
+	fmt.Println("  --top-k <n>               Stage C/E final clip count (default 3)")
+	fmt.Println("  --visual-gaps             Stage A also adds visual candidates inside subtitle gaps (type=visual, about 1/5 of the cap)")
+	fmt.Println("  --visual-gap-sec <sec>    Only gaps longer than this produce visual candidates (default 20)")
+	fmt.Println("  --no-llm                  Skip Stage B and use rule scores only")
+	fmt.Println("  --resume                  Continue the latest semantic dir: reuse Stage A candidates and Stage B scores whose inputs are unchanged (tracked in semantic-state.json)")
+	fmt.Println("  --save-prompt             Store the exact system/user prompt sent in stage-b-llm.json (for reproducing runs and prompt regression tracking)")
+	fmt.Println("  --decisions <path>        Stage E uses this review decisions file")
+	fmt.Println("  --pick                    Review candidates in the terminal (k keep / d drop / number sets rank); saved to the decisions file")
+	fmt.Println("  --apply                   Stage E: write back to prep-plan and run the doctor gate; also writes per-clip subtitles starting at 00:00 under clips/")
+	fmt.Println("  --chronological           Order final clips by timeline (rank keeps the score order)")
+	fmt.Println("  --explain-scores          Write a per-clip score breakdown (signals/base/semantic/final/novelty) to stage-c-explain.json and print it")
+	fmt.Println("  --enforce-spread          If no pick lands in the final third of the timeline, swap the last pick for the best final-third candidate")
+	fmt.Println("  --position-bias <v>       Nudge scores by timeline position: front (favor the opening, news) | back (favor the ending, tutorials) | none (default)")
+	fmt.Println("  --label-template <t>      Clip name template for --apply (same placeholders as prep, {type} is the candidate type; defaults to the prep template, else semantic-NN)")
+	fmt.Println("  --self-contained-review   Inline preview videos into review.html as base64 so it is one portable file (larger; default references them by relative path)")
+	fmt.Println("  --strict                  Stage E doctor uses strict thresholds")
+	fmt.Println("  --bundle-dir <dir>        Bundle root (reads prep, writes semantic results)")
+	fmt.Println("  --bundle <ts|path>        Use a specific prep bundle (timestamp dir name or path; default latest, falls back to latest if not found)")
+	fmt.Println("  --json                    Print the result as JSON")
+	fmt.Println()
+	fmt.Println("open options:")
+	fmt.Println("  --what <v>                What to open: html (latest semantic review.html, default)|bundle (latest prep bundle dir)|export (latest export dir)")
+	fmt.Println("  --bundle-dir <dir>        Look under this bundle root")
+	fmt.Println("  Uses xdg-open/open/start; without an opener (e.g. headless) it only prints the path and exits normally")
+	fmt.Println()
+	fmt.Println("thumbnail options:")
+	fmt.Println("  --at <sec|percent>        Frame position: seconds (e.g. 90) or percent (e.g. 25%), default 50%")
+	fmt.Println("  --grid <NxM>              Contact sheet with N columns and M rows sampled evenly across the video (not with --at)")
+	fmt.Println("  --out <path>              Output image (.jpg|.png) or directory (default next to the asset: <name>.thumbnail.jpg / <name>.contact-sheet.jpg)")
+	fmt.Println("  --json                    Print the result as JSON")
+	fmt.Println()
+	fmt.Println("transcribe options:")
+	fmt.Println("  --lang <v>                Language (Whisper code such as zh|en|ja; default auto, which detects and prints the language)")
+	fmt.Println("  --model <v>               Whisper model (default MINGEST_WHISPER_MODEL, else small)")
+	fmt.Println("  --whisper-device <v>      Whisper device: cpu|cuda (default MINGEST_WHISPER_DEVICE)")
+	fmt.Println("  --format <v>              Output format: srt|vtt|txt (default srt, or the --out extension)")
+	fmt.Println("  --out <path>              Output file or directory (default next to the asset: <name>.<format>)")
+	fmt.Println("  --json                    Print the result as JSON (includes an informational subtitle quality score)")
+	fmt.Println()
+	fmt.Println("translate-subtitles options:")
+	fmt.Println("  --to <lang>               Target language (e.g. zh|en|ja); translates the prep-selected subtitle cue by cue, timings unchanged")
+	fmt.Println("  --provider/--model/--api-key/--base-url/--proxy/--llm-timeout as in semantic")
+	fmt.Println("  --batch-size <n>          Cues per request (1-200, default 40)")
+	fmt.Println("  --out <path>              Output .srt (default next to the subtitle: <name>.<lang>.translated.srt)")
+	fmt.Println("  --bundle <ts|path>        Use a specific prep bundle (timestamp dir name or path; default latest, falls back to latest if not found)")
+	fmt.Println("  --json                    Print the result as JSON (includes the provider/model used)")
+	fmt.Println()
+	fmt.Println("auth options:")
+	fmt.Println("  --browser <v>             Browser for CDP: chrome|chromium|edge|brave (default MINGEST_BROWSER, else chrome; each has its own profile)")
+	fmt.Println("  --refresh                 Refresh the dedicated profile's cookies headlessly first; fall back to interactive login if signed out")
+	fmt.Println("  --consent-url <url>       Open this page headlessly first and click known consent/age-gate buttons; fall back to interactive login if the needed cookies are missing")
+	fmt.Println("  --import <file>           Adopt an existing Netscape cookie file without a browser: filtered to the platform, must hold sign-in cookies, then atomically replaces the cookie cache (alias --cookies-from-file)")
+	fmt.Println("  --json                    Print the result as JSON (includes cookie_file and authenticated; error_code on failure)")
+	fmt.Println()
+	fmt.Println("cookies options:")
+	fmt.Println("  inspect <platform>        List each cookie in the cache with name, domain, secure flag and expiry; * marks auth cookies")
+	fmt.Println("  --show-values             Show cookie values (redacted to their length by default)")
+	fmt.Println("  export <platform>         Export a Netscape jar scoped to the platform's domains from the dedicated browser profile (as auth), for CI and other browserless hosts; the internal cache is not touched")
+	fmt.Println("  --out <path>              Output path for export (required; written 0600 - treat it like a login credential)")
+	fmt.Println("  --browser <v>             Browser for export: chrome|chromium|edge|brave (as auth)")
+	fmt.Println("  --json                    Print the result as JSON")
+	fmt.Println()
+	fmt.Println("Platforms:")
+	fmt.Println("  - youtube")
+	fmt.Println("  - bilibili")
+	fmt.Println()
+	fmt.Println("Behavior:")
+	fmt.Println("  - Detects and runs yt-dlp / ffmpeg / ffprobe / deno|node automatically")
+	fmt.Println("  - Maintains a cookie cache (used first; refreshed from the browser when needed)")
+	fmt.Println("  - If Chrome cookies cannot be read/decrypted on Windows, `mingest auth <platform>` (CDP) prepares a dedicated login")
+	fmt.Println("  - App-Bound Cookie Encryption switches to CDP automatically; a signed-out dedicated profile prompts for login in an interactive terminal")
+	fmt.Println("  - Any command accepts --quiet (results and warnings/errors only; hides info logs and yt-dlp progress) or --verbose (debug logs); both override MINGEST_LOG_LEVEL")
+	fmt.Println("  - Any command accepts --json-pretty for indented JSON (implies --json); compact JSON stays the default")
+	fmt.Println("  - Any command accepts --library <dir> (or MINGEST_LIBRARY): all bundles live under <dir>/<asset_id>/{prep,semantic,export} with asset.json recording the media path; --bundle-dir still wins")
+	fmt.Println("  - Interrupted downloads keep their .part files (partial_path in JSON, not indexed); rerunning the same command resumes")
+	fmt.Println()
+	fmt.Println("Config file:")
+	fmt.Println("  - Default path: <state dir>/config.json (Linux: ~/.config/mingest/config.json); override with MINGEST_CONFIG")
+	fmt.Println("  - Per-command default options keyed by flag name without `--`, e.g. {\"semantic\": {\"target\": \"shorts\"}, \"export\": {\"zip\": true}}")
+	fmt.Println("  - An \"env\" section sets environment defaults, e.g. {\"env\": {\"MINGEST_BROWSER\": \"firefox\"}}")
+	fmt.Println("  - Precedence: command-line flags > environment > config file > built-in defaults")
+	fmt.Println("  - Turn off a switch the config sets to true with --no-<key> (e.g. --no-zip); set --library via MINGEST_LIBRARY in \"env\"")
+	fmt.Println()
+	fmt.Println("Environment variables:")
+	fmt.Println("  - MINGEST_LANG=zh|en (message language; defaults to the system locale, otherwise zh)")
+	fmt.Println("  - MINGEST_CONFIG=/path/to/config.json")
+	fmt.Println("  - MINGEST_STATE_DIR=/path/to/state (replaces the state dir for the asset index, cookie caches, config and browser profiles; created if missing, must be writable)")
+	fmt.Println("  - MINGEST_BUNDLE_ROOT=/path/to/bundles (output root for prep/semantic/export; useful for read-only media dirs)")
+	fmt.Println("  - MINGEST_BROWSER=chrome|firefox|chromium|edge|brave[+basictext|gnomekeyring|kwallet|kwallet5|kwallet6]")
+	fmt.Println("  - MINGEST_BROWSER_PROFILE=Default|Profile 1|...")
+	fmt.Println("    Firefox containers: <profile>::<container> or ::<container> (e.g. default-release::Work)")
+	fmt.Println("  - MINGEST_JS_RUNTIME=node|deno")
+	fmt.Println("  - MINGEST_CHROME_PATH=C:\\\\Path\\\\To\\\\chrome.exe")
+	fmt.Println("  - MINGEST_WHISPER_BACKEND=openai|faster|cpp (local transcription backend, default openai; faster looks for whisper-ctranslate2/faster-whisper, cpp for whisper-cli and needs ffmpeg)")
+	fmt.Println("  - MINGEST_WHISPER_PATH=/path/to/whisper")
+	fmt.Println("  - MINGEST_WHISPER_MODEL=tiny|base|small|medium|large (cpp backend: models/ggml-<name>.bin or a model file path)")
+	fmt.Println("  - MINGEST_WHISPER_DEVICE=cpu|cuda (local transcription device; openai backend enables fp16 on cuda, and disables it when unset as the CPU-safe default)")
+	fmt.Println("  - MINGEST_OPENAI_API_KEY / OPENAI_API_KEY")
+	fmt.Println("  - MINGEST_OPENROUTER_API_KEY / OPENROUTER_API_KEY")
+	fmt.Println("  - MINGEST_OPENROUTER_BASE_URL=https://openrouter.ai/api/v1")
+	fmt.Println("  - MINGEST_ANTHROPIC_API_KEY / ANTHROPIC_API_KEY (MINGEST_ANTHROPIC_BASE_URL overrides the endpoint)")
+	fmt.Println("  - MINGEST_GEMINI_API_KEY / GEMINI_API_KEY (MINGEST_GEMINI_BASE_URL overrides the endpoint)")
+	fmt.Println("  - MINGEST_LLM_MODEL=gpt-4.1-mini|openai/gpt-4.1-mini")
+	fmt.Println("  - MINGEST_DOWNLOAD_TIMEOUT=10m (get download timeout; --timeout wins)")
+	fmt.Println("  - MINGEST_CDP_TIMEOUT=30s (read timeout per CDP call, default 30s)")
+	fmt.Println("  - MINGEST_RATE_LIMIT_COOLDOWN=60s (base wait after HTTP 429; per-platform default: YouTube 60s, Bilibili 30s)")
+	fmt.Println("  - MINGEST_LLM_TIMEOUT=90s (semantic per-request LLM timeout; --llm-timeout wins)")
+	fmt.Println("  - MINGEST_RELEASE_URL=<url> (release endpoint for version --check, default GitHub Releases latest)")
+	fmt.Println("  - MINGEST_KEEP_TEMP=1 (same as prep --keep-temp)")
+	fmt.Println("  - MINGEST_PROXY=http://127.0.0.1:7890|socks5://host:1080 (proxy for yt-dlp and LLM requests; --proxy wins; local CDP bypasses it)")
+	fmt.Println("  - MINGEST_ON_COMPLETE=<cmd> (command run after get finishes; --on-complete wins)")
+	fmt.Println("  - MINGEST_ON_COMPLETE_TIMEOUT=10m (on-complete command timeout, default 10m; the command is killed when it expires)")
+	fmt.Println("  - MINGEST_JSON_INDENT=1 (indented JSON, same as --json-pretty)")
+	fmt.Println("  - MINGEST_LIBRARY=/path/to/library (central bundle library, same as --library)")
+	fmt.Println("  - MINGEST_LOG_LEVEL=debug|info|warn|error (default info; --quiet/--verbose win)")
+	fmt.Println("  - MINGEST_LOG_FORMAT=text|json (default text)")
+	fmt.Println()
+	fmt.Println("Exit codes:")
+	fmt.Println("  - 20: sign-in required (AUTH_REQUIRED)")
+	fmt.Println("  - 21: cookie read/decrypt problem (COOKIE_PROBLEM)")
+	fmt.Println("  - 30: JS runtime missing (RUNTIME_MISSING)")
+	fmt.Println("  - 31: ffmpeg missing (FFMPEG_MISSING)")
+	fmt.Println("  - 32: yt-dlp missing (YTDLP_MISSING)")
+	fmt.Println("  - 40: download failed (DOWNLOAD_FAILED)")
+	fmt.Println("  - 41: doctor checks failed (DOCTOR_FAILED)")
+	fmt.Println("  - 42: semantic pipeline failed (SEMANTIC_FAILED)")
+	fmt.Println("  - 43: file verification failed (VERIFY_FAILED)")
+	fmt.Println("  - 130: interrupted by Ctrl-C/SIGTERM (yt-dlp and the CDP browser are stopped and temp cookie jars removed)")
 }
 
 func isHelpArg(v string) bool {
@@ -359,6 +1190,28 @@ func parseGetOptions(args []string) (getOptions, error) {
 		switch {
 		case arg == "--asset-id-only":
 			opts.AssetIDOnly = true
+		case arg == "--verify":
+			opts.Verify = true
+		case arg == "--info":
+			opts.Info = true
+		case arg == "--embed-chapters":
+			opts.EmbedChapters = true
+		case arg == "--no-embed-thumbnail":
+			opts.NoThumbnail = true
+		case arg == "--no-metadata":
+			opts.NoMetadata = true
+		case arg == "--live-from-start":
+			opts.LiveFromStart = true
+		case arg == "--restrict-filenames":
+			opts.RestrictNames = true
+		case arg == "--wait-for-video":
+			if i+1 >= len(args) {
+				return getOptions{}, fmt.Errorf("`--wait-for-video` 缺少参数")
+			}
+			i++
+			opts.WaitForVideo = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--wait-for-video="):
+			opts.WaitForVideo = strings.TrimSpace(strings.TrimPrefix(arg, "--wait-for-video="))
 		case arg == "--json":
 			opts.JSON = true
 		case arg == "--out-dir":
@@ -381,6 +1234,119 @@ func parseGetOptions(args []string) (getOptions, error) {
 		case strings.HasPrefix(arg, "--name-template="):
 			opts.NameTemplate = strings.TrimSpace(strings.TrimPrefix(arg, "--name-template="))
 			nameTemplateProvided = true
+		case arg == "--limit-rate":
+			if i+1 >= len(args) {
+				return getOptions{}, fmt.Errorf("`--limit-rate` 缺少参数")
+			}
+			i++
+			opts.LimitRate = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--limit-rate="):
+			opts.LimitRate = strings.TrimSpace(strings.TrimPrefix(arg, "--limit-rate="))
+		case arg == "--max-filesize":
+			if i+1 >= len(args) {
+				return getOptions{}, fmt.Errorf("`--max-filesize` 缺少参数")
+			}
+			i++
+			opts.MaxFilesize = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--max-filesize="):
+			opts.MaxFilesize = strings.TrimSpace(strings.TrimPrefix(arg, "--max-filesize="))
+		case arg == "--sleep-interval":
+			if i+1 >= len(args) {
+				return getOptions{}, fmt.Errorf("`--sleep-interval` 缺少参数")
+			}
+			i++
+			v, err := strconv.ParseFloat(strings.TrimSpace(args[i]), 64)
+			if err != nil {
+				return getOptions{}, fmt.Errorf("`--sleep-interval` 必须是数字: %s", args[i])
+			}
+			opts.SleepInterval = v
+		case strings.HasPrefix(arg, "--sleep-interval="):
+			raw := strings.TrimSpace(strings.TrimPrefix(arg, "--sleep-interval="))
+			v, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return getOptions{}, fmt.Errorf("`--sleep-interval` 必须是数字: %s", raw)
+			}
+			opts.SleepInterval = v
+		case arg == "--sponsorblock":
+			if i+1 >= len(args) {
+				return getOptions{}, fmt.Errorf("`--sponsorblock` 缺少参数")
+			}
+			i++
+			opts.SponsorBlock = strings.ToLower(strings.TrimSpace(args[i]))
+		case strings.HasPrefix(arg, "--sponsorblock="):
+			opts.SponsorBlock = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--sponsorblock=")))
+		case arg == "--sponsorblock-categories":
+			if i+1 >= len(args) {
+				return getOptions{}, fmt.Errorf("`--sponsorblock-categories` 缺少参数")
+			}
+			i++
+			opts.SponsorCats = strings.ToLower(strings.TrimSpace(args[i]))
+		case strings.HasPrefix(arg, "--sponsorblock-categories="):
+			opts.SponsorCats = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--sponsorblock-categories=")))
+		case arg == "--section":
+			if i+1 >= len(args) {
+				return getOptions{}, fmt.Errorf("`--section` 缺少参数")
+			}
+			i++
+			opts.Section = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--section="):
+			opts.Section = strings.TrimSpace(strings.TrimPrefix(arg, "--section="))
+		case arg == "--proxy":
+			if i+1 >= len(args) {
+				return getOptions{}, fmt.Errorf("`--proxy` 缺少参数")
+			}
+			i++
+			opts.Proxy = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--proxy="):
+			opts.Proxy = strings.TrimSpace(strings.TrimPrefix(arg, "--proxy="))
+		case arg == "--on-complete":
+			if i+1 >= len(args) {
+				return getOptions{}, fmt.Errorf("`--on-complete` 缺少参数")
+			}
+			i++
+			opts.OnComplete = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--on-complete="):
+			opts.OnComplete = strings.TrimSpace(strings.TrimPrefix(arg, "--on-complete="))
+		case arg == "--cookies-browser":
+			if i+1 >= len(args) {
+				return getOptions{}, fmt.Errorf("`--cookies-browser` 缺少参数")
+			}
+			i++
+			opts.CookiesBrowser = strings.ToLower(strings.TrimSpace(args[i]))
+		case strings.HasPrefix(arg, "--cookies-browser="):
+			opts.CookiesBrowser = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--cookies-browser=")))
+		case arg == "--keyring":
+			if i+1 >= len(args) {
+				return getOptions{}, fmt.Errorf("`--keyring` 缺少参数")
+			}
+			i++
+			opts.Keyring = strings.ToLower(strings.TrimSpace(args[i]))
+		case strings.HasPrefix(arg, "--keyring="):
+			opts.Keyring = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--keyring=")))
+		case arg == "--cookies-profile":
+			if i+1 >= len(args) {
+				return getOptions{}, fmt.Errorf("`--cookies-profile` 缺少参数")
+			}
+			i++
+			opts.CookiesProfile = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--cookies-profile="):
+			opts.CookiesProfile = strings.TrimSpace(strings.TrimPrefix(arg, "--cookies-profile="))
+		case arg == "--timeout":
+			if i+1 >= len(args) {
+				return getOptions{}, fmt.Errorf("`--timeout` 缺少参数")
+			}
+			i++
+			d, err := parseTimeoutValue(args[i])
+			if err != nil {
+				return getOptions{}, fmt.Errorf("`--timeout` %v", err)
+			}
+			opts.Timeout = d
+		case strings.HasPrefix(arg, "--timeout="):
+			d, err := parseTimeoutValue(strings.TrimPrefix(arg, "--timeout="))
+			if err != nil {
+				return getOptions{}, fmt.Errorf("`--timeout` %v", err)
+			}
+			opts.Timeout = d
 		case strings.HasPrefix(arg, "-"):
 			return getOptions{}, fmt.Errorf("不支持的参数: %s", arg)
 		default:
@@ -391,19 +1357,275 @@ func parseGetOptions(args []string) (getOptions, error) {
 		}
 	}
 
-	if strings.TrimSpace(opts.TargetURL) == "" {
-		return getOptions{}, fmt.Errorf("缺少 URL。用法: mingest get <url>")
+	if strings.TrimSpace(opts.TargetURL) == "" {
+		return getOptions{}, fmt.Errorf("缺少 URL。用法: mingest get <url>")
+	}
+	if opts.AssetIDOnly && opts.JSON {
+		return getOptions{}, fmt.Errorf("`--asset-id-only` 与 `--json` 不能同时使用")
+	}
+	if opts.Info && (opts.AssetIDOnly || opts.Verify || opts.OnComplete != "") {
+		return getOptions{}, fmt.Errorf("`--info` 只读取元信息，不能与 `--asset-id-only`、`--verify`、`--on-complete` 同时使用")
+	}
+	if outDirProvided && strings.TrimSpace(opts.OutDir) == "" {
+		return getOptions{}, fmt.Errorf("`--out-dir` 不能为空")
+	}
+	if nameTemplateProvided && strings.TrimSpace(opts.NameTemplate) == "" {
+		return getOptions{}, fmt.Errorf("`--name-template` 不能为空")
+	}
+	if browser, keyring := splitBrowserKeyring(opts.CookiesBrowser); keyring != "" {
+		if opts.Keyring != "" && opts.Keyring != keyring {
+			return getOptions{}, fmt.Errorf("`--cookies-browser` 中的 keyring 与 `--keyring` 冲突")
+		}
+		opts.CookiesBrowser = browser
+		opts.Keyring = keyring
+	}
+	if opts.CookiesBrowser != "" && !contains(ytDlpCookieBrowsers, opts.CookiesBrowser) {
+		return getOptions{}, fmt.Errorf("`--cookies-browser` 仅支持 %s", strings.Join(ytDlpCookieBrowsers, "|"))
+	}
+	if opts.Keyring != "" && !contains(ytDlpKeyrings, opts.Keyring) {
+		return getOptions{}, fmt.Errorf("`--keyring` 仅支持 %s", strings.Join(ytDlpKeyrings, "|"))
+	}
+	if opts.LimitRate != "" && !ytDlpRateRE.MatchString(opts.LimitRate) {
+		return getOptions{}, fmt.Errorf("`--limit-rate` 格式无效（示例: 500K、2M、1.5M）: %s", opts.LimitRate)
+	}
+	if opts.MaxFilesize != "" && !ytDlpRateRE.MatchString(opts.MaxFilesize) {
+		return getOptions{}, fmt.Errorf("`--max-filesize` 格式无效（字节数，可带 K/M/G，示例: 500M、2G）: %s", opts.MaxFilesize)
+	}
+	if opts.WaitForVideo != "" && !waitForVideoRE.MatchString(opts.WaitForVideo) {
+		return getOptions{}, fmt.Errorf("`--wait-for-video` 格式无效（重试间隔秒数，或 最小-最大，示例: 60、30-300）: %s", opts.WaitForVideo)
+	}
+	if opts.SleepInterval < 0 {
+		return getOptions{}, fmt.Errorf("`--sleep-interval` 不能为负数")
+	}
+	switch opts.SponsorBlock {
+	case "":
+		if opts.SponsorCats != "" {
+			return getOptions{}, fmt.Errorf("`--sponsorblock-categories` 需配合 `--sponsorblock` 使用")
+		}
+	case "mark", "remove":
+		if opts.SponsorCats == "" {
+			opts.SponsorCats = "sponsor"
+		}
+		cats, err := normalizeSponsorBlockCategories(opts.SponsorBlock, opts.SponsorCats)
+		if err != nil {
+			return getOptions{}, err
+		}
+		opts.SponsorCats = cats
+	default:
+		return getOptions{}, fmt.Errorf("`--sponsorblock` 仅支持 remove|mark")
+	}
+	if opts.Section != "" {
+		section, err := normalizeDownloadSection(opts.Section)
+		if err != nil {
+			return getOptions{}, err
+		}
+		opts.Section = section
+	}
+	proxy, err := resolveProxy(opts.Proxy)
+	if err != nil {
+		return getOptions{}, err
+	}
+	opts.Proxy = proxy
+	return opts, nil
+}
+
+// parseTimeoutValue accepts Go durations (90s, 10m, 1h30m) or plain seconds.
+func parseTimeoutValue(raw string) (time.Duration, error) {
+	v := strings.TrimSpace(raw)
+	if n, err := strconv.Atoi(v); err == nil {
+		if n <= 0 {
+			return 0, fmt.Errorf("必须大于 0: %s", v)
+		}
+		return time.Duration(n) * time.Second, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("格式无效（示例: 90s、10m、1h）: %s", v)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("必须大于 0: %s", v)
+	}
+	return d, nil
+}
+
+func resolveDownloadTimeout(flagValue time.Duration) time.Duration {
+	if flagValue > 0 {
+		return flagValue
+	}
+	raw := strings.TrimSpace(os.Getenv("MINGEST_DOWNLOAD_TIMEOUT"))
+	if raw == "" {
+		return 0
+	}
+	d, err := parseTimeoutValue(raw)
+	if err != nil {
+		logWarn("get.timeout_env_invalid", "env", "MINGEST_DOWNLOAD_TIMEOUT", "value", raw, "error", err)
+		return 0
+	}
+	return d
+}
+
+// ytDlpRateRE matches yt-dlp's --limit-rate syntax: bytes per second with an
+// optional K/M/G suffix. --max-filesize takes the same form (in bytes).
+var ytDlpRateRE = regexp.MustCompile(`^\d+(\.\d+)?[KMGkmg]?$`)
+
+// waitForVideoRE matches yt-dlp's --wait-for-video MIN[-MAX] retry interval in seconds.
+var waitForVideoRE = regexp.MustCompile(`^\d+(-\d+)?$`)
+
+// sponsorBlockCategories are the SponsorBlock categories yt-dlp accepts.
+// poi_highlight and chapter are points/labels, so they can only be marked.
+var sponsorBlockCategories = []string{"sponsor", "intro", "outro", "selfpromo", "preview", "filler", "interaction", "music_offtopic", "poi_highlight", "chapter", "all"}
+
+// sponsorBlockChapterPrefix prefixes the chapter titles written by
+// --sponsorblock mark; prep reads them back as sponsor segments.
+const sponsorBlockChapterPrefix = "[SponsorBlock]:"
+
+func normalizeSponsorBlockCategories(mode, raw string) (string, error) {
+	var out []string
+	for _, c := range strings.Split(raw, ",") {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		if !contains(sponsorBlockCategories, c) {
+			return "", fmt.Errorf("`--sponsorblock-categories` 不支持 %q，可选: %s", c, strings.Join(sponsorBlockCategories, ","))
+		}
+		if mode == "remove" && (c == "poi_highlight" || c == "chapter") {
+			return "", fmt.Errorf("`--sponsorblock remove` 不支持分类 %s（仅可 mark）", c)
+		}
+		out = append(out, c)
+	}
+	if len(out) == 0 {
+		return "", fmt.Errorf("`--sponsorblock-categories` 不能为空")
+	}
+	return strings.Join(out, ","), nil
+}
+
+// ytDlpSectionRE matches a --download-sections time range: [*]START-END where
+// each bound is seconds or [HH:]MM:SS with optional fraction; END may be inf.
+var ytDlpSectionRE = regexp.MustCompile(`^\*?(\d+(?::\d{1,2}){0,2}(?:\.\d+)?)-(\d+(?::\d{1,2}){0,2}(?:\.\d+)?|inf)$`)
+
+// normalizeDownloadSection validates a --section value and returns it with the
+// leading "*" yt-dlp needs to treat it as a time range (not a chapter regex).
+func normalizeDownloadSection(raw string) (string, error) {
+	m := ytDlpSectionRE.FindStringSubmatch(strings.TrimSpace(raw))
+	if m == nil {
+		return "", fmt.Errorf("`--section` 格式无效（示例: \"*01:20:00-01:35:00\"、\"*90-300\"）: %s", raw)
+	}
+	if m[2] != "inf" && parseSectionSeconds(m[2]) <= parseSectionSeconds(m[1]) {
+		return "", fmt.Errorf("`--section` 结束时间必须晚于开始时间: %s", raw)
+	}
+	return "*" + m[1] + "-" + m[2], nil
+}
+
+func parseSectionSeconds(v string) float64 {
+	total := 0.0
+	for _, part := range strings.Split(v, ":") {
+		n, _ := strconv.ParseFloat(part, 64)
+		total = total*60 + n
+	}
+	return total
+}
+
+// resolveProxy returns the flag value, falling back to MINGEST_PROXY, after
+// checking it is a usable proxy URL.
+func resolveProxy(flagValue string) (string, error) {
+	if flagValue != "" {
+		if err := validateProxyURL(flagValue); err != nil {
+			return "", fmt.Errorf("`--proxy` %v", err)
+		}
+		return flagValue, nil
+	}
+	raw := strings.TrimSpace(os.Getenv("MINGEST_PROXY"))
+	if raw == "" {
+		return "", nil
+	}
+	if err := validateProxyURL(raw); err != nil {
+		return "", fmt.Errorf("MINGEST_PROXY %v", err)
+	}
+	return raw, nil
+}
+
+func validateProxyURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("不是有效的代理 URL（示例: http://127.0.0.1:7890、socks5://host:1080）: %s", raw)
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return fmt.Errorf("仅支持 http|https|socks5|socks5h 代理: %s", raw)
+	}
+	return nil
+}
+
+func resolveOnCompleteCommand(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	return strings.TrimSpace(os.Getenv("MINGEST_ON_COMPLETE"))
+}
+
+// defaultOnCompleteTimeout bounds the on-complete hook so a hanging command
+// can't keep `get` (and a batch run) from finishing.
+const defaultOnCompleteTimeout = 10 * time.Minute
+
+func resolveOnCompleteTimeout() time.Duration {
+	raw := strings.TrimSpace(os.Getenv("MINGEST_ON_COMPLETE_TIMEOUT"))
+	if raw == "" {
+		return defaultOnCompleteTimeout
 	}
-	if opts.AssetIDOnly && opts.JSON {
-		return getOptions{}, fmt.Errorf("`--asset-id-only` 与 `--json` 不能同时使用")
+	d, err := parseTimeoutValue(raw)
+	if err != nil {
+		logWarn("get.on_complete_timeout_env_invalid", "env", "MINGEST_ON_COMPLETE_TIMEOUT", "value", raw, "error", err)
+		return defaultOnCompleteTimeout
 	}
-	if outDirProvided && strings.TrimSpace(opts.OutDir) == "" {
-		return getOptions{}, fmt.Errorf("`--out-dir` 不能为空")
+	return d
+}
+
+// runOnCompleteHook runs the user's post-download command through the system
+// shell. Its output goes to stderr so `--json` stdout stays parseable; a
+// failing hook is logged but never fails the download. The shell and anything
+// it started are killed after timeout.
+func runOnCompleteHook(command, outputPath, assetID, rawURL string, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	env := withEnvVar(os.Environ(), "MINGEST_OUTPUT_PATH", outputPath)
+	env = withEnvVar(env, "MINGEST_ASSET_ID", assetID)
+	env = withEnvVar(env, "MINGEST_URL", rawURL)
+	cmd.Env = env
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	cmd.SysProcAttr = newProcessGroupAttr()
+	cmd.Cancel = func() error { return killProcessTree(cmd.Process) }
+	cmd.WaitDelay = 5 * time.Second
+
+	logInfo("get.on_complete_started", "command", command, "asset_id", assetID)
+	err := cmd.Start()
+	if err == nil {
+		unregister := registerCleanup(func() { _ = killProcessTree(cmd.Process) })
+		err = cmd.Wait()
+		unregister()
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		logWarn("hook.timeout", "command", command, "timeout", timeout.String())
+		return
 	}
-	if nameTemplateProvided && strings.TrimSpace(opts.NameTemplate) == "" {
-		return getOptions{}, fmt.Errorf("`--name-template` 不能为空")
+	if err != nil {
+		exitCode := -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exitCode = exitErr.ExitCode()
+		}
+		logWarn("get.on_complete_failed", "command", command, "exit_code", exitCode, "error", err)
+		return
 	}
-	return opts, nil
+	logInfo("get.on_complete_finished", "command", command, "exit_code", 0)
 }
 
 func parseLsOptions(args []string) (lsOptions, error) {
@@ -471,55 +1693,64 @@ func parseLsOptions(args []string) (lsOptions, error) {
 }
 
 func runGet(opts getOptions) int {
+	if opts.Info {
+		return runGetInfo(opts)
+	}
+	result := executeGet(opts)
+	if opts.JSON {
+		printGetJSON(result)
+		return result.ExitCode
+	}
+	if opts.AssetIDOnly && result.OK {
+		fmt.Println(result.AssetID)
+	}
+	return result.ExitCode
+}
+
+// executeGet downloads the URL and records the asset without printing results,
+// so batch runs can reuse it. Failures are logged here.
+func executeGet(opts getOptions) getJSONResult {
 	u, err := validateURL(opts.TargetURL)
 	if err != nil {
-		if opts.JSON {
-			printGetJSON(getJSONResult{
-				OK:       false,
-				ExitCode: exitUsage,
-				Error:    fmt.Sprintf("输入的 URL 无效: %v", err),
-			})
-		}
 		logError("get.url_invalid", "url", opts.TargetURL, "error", err)
-		return exitUsage
+		return getJSONResult{
+			OK:        false,
+			ExitCode:  exitUsage,
+			Error:     fmt.Sprintf("输入的 URL 无效: %v", err),
+			ErrorCode: errorCodeInvalidArgument,
+		}
 	}
 
 	outputTemplate, outputDir, err := resolveGetOutput(opts.OutDir, opts.NameTemplate)
 	if err != nil {
-		if opts.JSON {
-			printGetJSON(getJSONResult{
-				OK:       false,
-				ExitCode: exitUsage,
-				Error:    err.Error(),
-			})
-		}
 		logError("get.output_options_invalid", "out_dir", opts.OutDir, "name_template", opts.NameTemplate, "error", err)
-		return exitUsage
+		return getJSONResult{
+			OK:        false,
+			ExitCode:  exitUsage,
+			Error:     err.Error(),
+			ErrorCode: errorCodeInvalidArgument,
+		}
 	}
 
 	found, err := detectDeps()
 	if err != nil {
 		var depErr dependencyError
 		if errors.As(err, &depErr) {
-			if opts.JSON {
-				printGetJSON(getJSONResult{
-					OK:       false,
-					ExitCode: depErr.ExitCode,
-					Error:    depErr.Message,
-				})
-			}
 			logError("deps.validation_failed", "exit_code", depErr.ExitCode, "detail", depErr.Message)
-			return depErr.ExitCode
-		}
-		if opts.JSON {
-			printGetJSON(getJSONResult{
-				OK:       false,
-				ExitCode: exitDownloadFailed,
-				Error:    fmt.Sprintf("依赖检测失败: %v", err),
-			})
+			return getJSONResult{
+				OK:        false,
+				ExitCode:  depErr.ExitCode,
+				Error:     depErr.Message,
+				ErrorCode: errorCodeForExit(depErr.ExitCode),
+			}
 		}
 		logError("deps.detect_failed", "error", err)
-		return exitDownloadFailed
+		return getJSONResult{
+			OK:        false,
+			ExitCode:  exitDownloadFailed,
+			Error:     fmt.Sprintf("依赖检测失败: %v", err),
+			ErrorCode: errorCodeDownloadFailed,
+		}
 	}
 
 	p, ok := platformForURL(u)
@@ -529,7 +1760,7 @@ func runGet(opts getOptions) int {
 		p = videoPlatform{}
 	}
 
-	authSources := buildAuthSources()
+	authSources := buildAuthSources(opts.CookiesBrowser, opts.CookiesProfile, opts.Keyring)
 	cookieFile := ""
 	if strings.TrimSpace(p.ID) != "" {
 		if v, err := cookiesCacheFilePath(p); err != nil {
@@ -550,114 +1781,171 @@ func runGet(opts getOptions) int {
 	}
 	logInfo("auth.fallback_policy_enabled", "strategy", "cache_then_browser")
 
-	captureOutput := true
 	cfg := ytDlpConfig{
 		OutputTemplate:   outputTemplate,
-		CaptureMovedPath: captureOutput,
+		CaptureMovedPath: true,
 		Quiet:            opts.JSON,
 		ProgressOnly:     opts.AssetIDOnly && !opts.JSON,
-	}
+		Timeout:          resolveDownloadTimeout(opts.Timeout),
+		Proxy:            opts.Proxy,
+		LimitRate:        opts.LimitRate,
+		MaxFilesize:      opts.MaxFilesize,
+		SleepInterval:    opts.SleepInterval,
+		Section:          opts.Section,
+		SponsorBlock:     opts.SponsorBlock,
+		SponsorCats:      opts.SponsorCats,
+		EmbedChapters:    opts.EmbedChapters,
+		NoThumbnail:      opts.NoThumbnail,
+		NoMetadata:       opts.NoMetadata,
+		WaitForVideo:     opts.WaitForVideo,
+		LiveFromStart:    opts.LiveFromStart,
+		RestrictNames:    opts.RestrictNames,
+		Failure:          &ytDlpFailure{},
+		Destinations:     &[]string{},
+	}
+	if cfg.Timeout > 0 {
+		logInfo("get.timeout_enabled", "timeout", cfg.Timeout.String())
+	}
+	result := getJSONResult{
+		URL:          opts.TargetURL,
+		Platform:     strings.TrimSpace(p.ID),
+		OutputDir:    outputDir,
+		NameTemplate: outputTemplate,
+	}
+	if cfg.WaitForVideo == "" && !cfg.LiveFromStart {
+		if refused, ok := refuseLiveStream(found, opts.TargetURL, p, cookieFile, opts.Proxy, cfg.Timeout); !ok {
+			logWarn("yt_dlp.failure_hint", "hint", refused.Hint)
+			result.ExitCode = exitDownloadFailed
+			result.Error = tr("get.download_failed")
+			result.ErrorCode = refused.ErrorCode
+			result.Hint = refused.Hint
+			return result
+		}
+	}
+	startedAt := time.Now()
 	code, movedPaths := runWithAuthFallback(opts.TargetURL, found, p, authSources, cookieFile, cfg)
 	if code != exitOK {
-		if opts.JSON {
-			printGetJSON(getJSONResult{
-				OK:           false,
-				ExitCode:     code,
-				Error:        "下载失败",
-				URL:          opts.TargetURL,
-				Platform:     strings.TrimSpace(p.ID),
-				OutputDir:    outputDir,
-				NameTemplate: outputTemplate,
-			})
+		result.ExitCode = code
+		result.Error = tr("get.download_failed")
+		result.ErrorCode = errorCodeForExit(code)
+		// The last classified run only explains the result if it produced this exit code;
+		// e.g. an empty auth source list fails without running yt-dlp again.
+		if cfg.Failure.ExitCode == code && cfg.Failure.ErrorCode != "" {
+			result.ErrorCode = cfg.Failure.ErrorCode
+			result.Hint = cfg.Failure.Hint
+		}
+		if partial := findPartialDownload(*cfg.Destinations, startedAt); partial != "" {
+			result.PartialPath = partial
+			result.Error = tr("get.partial_kept", partial)
+			logWarn("get.partial_kept", "path", partial, "hint", "rerun_to_resume")
 		}
-		return code
+		return result
 	}
 
-	if !captureOutput {
-		return exitOK
+	outputPaths := capturedPaths(movedPaths)
+	outputPath := ""
+	if len(outputPaths) > 0 {
+		outputPath = outputPaths[0]
 	}
-
-	outputPath := firstCapturedPath(movedPaths)
 	if outputPath == "" {
-		msg := "下载成功，但未能解析输出文件路径"
+		msg := tr("get.output_path_missing")
+		if partial := findPartialDownload(*cfg.Destinations, startedAt); partial != "" {
+			// yt-dlp exited cleanly but left a partial behind: treat as an
+			// interrupted download and don't index it.
+			result.PartialPath = partial
+			msg = tr("get.partial_download", partial)
+			logError("get.partial_download", "path", partial)
+			result.ExitCode = exitDownloadFailed
+			result.Error = msg
+			result.ErrorCode = errorCodePartialDownload
+			return result
+		}
 		if !opts.AssetIDOnly && !opts.JSON {
 			logWarn("get.output_path_missing", "action", "skip_asset_index")
-			return exitOK
-		}
-		if opts.JSON {
-			printGetJSON(getJSONResult{
-				OK:           false,
-				ExitCode:     exitDownloadFailed,
-				Error:        msg,
-				URL:          opts.TargetURL,
-				Platform:     strings.TrimSpace(p.ID),
-				OutputDir:    outputDir,
-				NameTemplate: outputTemplate,
-			})
+			result.OK = true
+			return result
 		}
 		logError("get.output_path_missing", "error", msg)
-		return exitDownloadFailed
+		result.ExitCode = exitDownloadFailed
+		result.Error = msg
+		result.ErrorCode = errorCodeOutputMissing
+		return result
 	}
+	result.OutputPath = outputPath
 
-	assetID, err := computeAssetID(outputPath)
+	// A playlist URL leaves one file per item; hash them together.
+	ids, err := computeAssetIDs(outputPaths, assetHashWorkers)
+	assetID := ids[outputPath]
 	if err != nil {
-		msg := fmt.Sprintf("生成 asset_id 失败: %v", err)
-		if opts.JSON {
-			printGetJSON(getJSONResult{
-				OK:           false,
-				ExitCode:     exitDownloadFailed,
-				Error:        msg,
-				URL:          opts.TargetURL,
-				Platform:     strings.TrimSpace(p.ID),
-				OutputPath:   outputPath,
-				OutputDir:    outputDir,
-				NameTemplate: outputTemplate,
-			})
-		}
 		logError("asset_id.compute_failed", "path", outputPath, "error", err)
-		return exitDownloadFailed
+		result.ExitCode = exitDownloadFailed
+		result.Error = fmt.Sprintf("生成 asset_id 失败: %v", err)
+		result.ErrorCode = errorCodeAssetIDFailed
+		return result
+	}
+
+	contentSHA := ""
+	if opts.Verify {
+		sum, err := computeContentSHA256(outputPath)
+		if err != nil {
+			logError("get.verify_failed", "path", outputPath, "error", err)
+			result.ExitCode = exitVerifyFailed
+			result.Error = fmt.Sprintf("计算 content_sha256 失败: %v", err)
+			result.ErrorCode = errorCodeVerifyFailed
+			return result
+		}
+		contentSHA = sum
+		logInfo("get.content_hashed", "asset_id", assetID, "content_sha256", sum)
 	}
 
-	if opts.AssetIDOnly {
+	if err := appendAssetRecord(assetRecord{
+		AssetID:       assetID,
+		URL:           opts.TargetURL,
+		Platform:      strings.TrimSpace(p.ID),
+		Title:         filepath.Base(outputPath),
+		OutputPath:    outputPath,
+		CreatedAt:     time.Now().UTC().Format(time.RFC3339),
+		ContentSHA256: contentSHA,
+		Section:       opts.Section,
+	}); err != nil {
+		logWarn("asset_index.append_failed", "error", err, "asset_id", assetID)
+	}
+	for _, extra := range outputPaths[1:] {
 		if err := appendAssetRecord(assetRecord{
-			AssetID:    assetID,
+			AssetID:    ids[extra],
 			URL:        opts.TargetURL,
 			Platform:   strings.TrimSpace(p.ID),
-			Title:      filepath.Base(outputPath),
-			OutputPath: outputPath,
+			Title:      filepath.Base(extra),
+			OutputPath: extra,
 			CreatedAt:  time.Now().UTC().Format(time.RFC3339),
 		}); err != nil {
-			logWarn("asset_index.append_failed", "error", err, "asset_id", assetID)
+			logWarn("asset_index.append_failed", "error", err, "asset_id", ids[extra])
 		}
-		fmt.Println(assetID)
-		return exitOK
+		result.PlaylistItems = append(result.PlaylistItems, getPlaylistItem{AssetID: ids[extra], OutputPath: extra})
+	}
+	if len(result.PlaylistItems) > 0 {
+		logInfo("get.playlist_indexed", "items", len(outputPaths))
 	}
 
-	if err := appendAssetRecord(assetRecord{
-		AssetID:    assetID,
-		URL:        opts.TargetURL,
-		Platform:   strings.TrimSpace(p.ID),
-		Title:      filepath.Base(outputPath),
-		OutputPath: outputPath,
-		CreatedAt:  time.Now().UTC().Format(time.RFC3339),
-	}); err != nil {
-		logWarn("asset_index.append_failed", "error", err, "asset_id", assetID)
+	// Probe before the hook, which may move the file.
+	if opts.EmbedChapters {
+		if probe, err := probeMediaFile(found.FFprobe.Path, outputPath); err != nil {
+			logWarn("get.chapters_probe_failed", "path", outputPath, "error", err)
+		} else {
+			result.ChaptersEmbedded = len(probe.Chapters) > 0
+			logInfo("get.chapters_embedded", "asset_id", assetID, "chapters", len(probe.Chapters))
+		}
 	}
 
-	if opts.JSON {
-		printGetJSON(getJSONResult{
-			OK:           true,
-			ExitCode:     exitOK,
-			URL:          opts.TargetURL,
-			Platform:     strings.TrimSpace(p.ID),
-			OutputPath:   outputPath,
-			AssetID:      assetID,
-			OutputDir:    outputDir,
-			NameTemplate: outputTemplate,
-		})
+	if hook := resolveOnCompleteCommand(opts.OnComplete); hook != "" {
+		runOnCompleteHook(hook, outputPath, assetID, opts.TargetURL, resolveOnCompleteTimeout())
 	}
 
-	return exitOK
+	result.OK = true
+	result.AssetID = assetID
+	result.ContentSHA = contentSHA
+	result.Section = opts.Section
+	return result
 }
 
 func resolveGetOutput(outDir, nameTemplate string) (template string, resolvedOutDir string, err error) {
@@ -665,6 +1953,9 @@ func resolveGetOutput(outDir, nameTemplate string) (template string, resolvedOut
 	if tpl == "" {
 		tpl = defaultYtDlpOutputTemplate
 	}
+	if err := validateNameTemplate(tpl); err != nil {
+		return "", "", err
+	}
 
 	trimmedOutDir := strings.TrimSpace(outDir)
 	if trimmedOutDir == "" {
@@ -686,6 +1977,18 @@ func resolveGetOutput(outDir, nameTemplate string) (template string, resolvedOut
 	return filepath.Join(absDir, tpl), absDir, nil
 }
 
+// validateNameTemplate rejects templates with ".." path segments, which
+// would let the output land outside --out-dir (or the working directory).
+// yt-dlp already strips path separators from field values such as %(title)s.
+func validateNameTemplate(tpl string) error {
+	for _, seg := range strings.FieldsFunc(tpl, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if strings.TrimSpace(seg) == ".." {
+			return fmt.Errorf("`--name-template` 不能包含 \"..\" 路径段: %s", tpl)
+		}
+	}
+	return nil
+}
+
 func runLs(opts lsOptions) int {
 	records, err := readAssetRecords()
 	if err != nil {
@@ -708,7 +2011,7 @@ func runLs(opts lsOptions) int {
 	}
 
 	if opts.Format == "json" {
-		data, err := json.Marshal(lsJSONResult{
+		data, err := marshalJSONResult(lsJSONResult{
 			Total: total,
 			Count: len(filtered),
 			Limit: opts.Limit,
@@ -802,6 +2105,10 @@ func assetsIndexFilePath() (string, error) {
 	return filepath.Join(base, "assets-v1.jsonl"), nil
 }
 
+// assetIndexMu serializes index appends within one process (e.g. batch workers);
+// the file lock below covers concurrent mingest processes.
+var assetIndexMu sync.Mutex
+
 func appendAssetRecord(rec assetRecord) error {
 	indexPath, err := assetsIndexFilePath()
 	if err != nil {
@@ -819,17 +2126,37 @@ func appendAssetRecord(rec assetRecord) error {
 		normalized.Title = filepath.Base(normalized.OutputPath)
 	}
 
-	f, err := os.OpenFile(indexPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	b, err := json.Marshal(normalized)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
+	if !json.Valid(b) || bytes.ContainsAny(b, "\r\n") {
+		return fmt.Errorf("asset record is not a single-line JSON object")
+	}
 
-	b, err := json.Marshal(normalized)
+	assetIndexMu.Lock()
+	defer assetIndexMu.Unlock()
+
+	f, err := os.OpenFile(indexPath, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0o600)
 	if err != nil {
 		return err
 	}
-	if _, err := f.Write(append(b, '\n')); err != nil {
+	defer f.Close()
+
+	if err := lockFile(f); err != nil {
+		return fmt.Errorf("lock asset index: %w", err)
+	}
+	defer func() { _ = unlockFile(f) }()
+
+	line := append(b, '\n')
+	// If a previous writer crashed mid-line, start on a fresh line so this record stays parseable.
+	if st, err := f.Stat(); err == nil && st.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := f.ReadAt(last, st.Size()-1); err == nil && last[0] != '\n' {
+			line = append([]byte{'\n'}, line...)
+		}
+	}
+	if _, err := f.Write(line); err != nil {
 		return err
 	}
 	return nil
@@ -851,14 +2178,22 @@ func readAssetRecords() ([]assetRecord, error) {
 	defer f.Close()
 
 	out := make([]assetRecord, 0, 64)
+	malformed := 0
+	firstMalformed := 0
+	lineNo := 0
 	sc := bufio.NewScanner(f)
 	for sc.Scan() {
+		lineNo++
 		line := strings.TrimSpace(sc.Text())
 		if line == "" {
 			continue
 		}
 		var rec assetRecord
 		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			if malformed == 0 {
+				firstMalformed = lineNo
+			}
+			malformed++
 			continue
 		}
 		if strings.TrimSpace(rec.AssetID) == "" || strings.TrimSpace(rec.OutputPath) == "" {
@@ -872,24 +2207,66 @@ func readAssetRecords() ([]assetRecord, error) {
 	if err := sc.Err(); err != nil {
 		return nil, err
 	}
+	if malformed > 0 {
+		logWarn("asset_index.malformed_lines_skipped", "path", indexPath, "count", malformed, "first_line", firstMalformed)
+	}
 	return out, nil
 }
 
-func firstCapturedPath(paths []string) string {
+// findPartialDownload returns the most recently modified yt-dlp leftover
+// (.part / .ytdl / .part-FragN) of one of this download's destinations,
+// touched since the download started. Only the item's own files count, so
+// concurrent batch workers sharing an output dir can't claim each other's.
+// yt-dlp resumes these on the next run with the same output.
+func findPartialDownload(destinations []string, since time.Time) string {
+	best := ""
+	var bestMod time.Time
+	cutoff := since.Add(-2 * time.Second)
+	for _, dest := range destinations {
+		candidates := []string{dest + ".part", dest + ".ytdl"}
+		if frags, err := filepath.Glob(dest + ".part-Frag*"); err == nil {
+			candidates = append(candidates, frags...)
+		}
+		for _, path := range candidates {
+			info, err := os.Stat(path)
+			if err != nil || info.IsDir() || info.ModTime().Before(cutoff) {
+				continue
+			}
+			if best == "" || info.ModTime().After(bestMod) {
+				best = path
+				bestMod = info.ModTime()
+			}
+		}
+	}
+	if best != "" {
+		if abs, err := filepath.Abs(best); err == nil {
+			best = abs
+		}
+	}
+	return best
+}
+
+// capturedPaths returns the existing files among yt-dlp's captured paths, in
+// order and without duplicates; a playlist yields one per item.
+func capturedPaths(paths []string) []string {
+	var out []string
+	seen := make(map[string]bool, len(paths))
 	for _, p := range paths {
 		v := strings.Trim(strings.TrimSpace(p), "\"")
 		if v == "" {
 			continue
 		}
-		abs, err := filepath.Abs(v)
-		if err == nil && fileExists(abs) {
-			return abs
+		if abs, err := filepath.Abs(v); err == nil && fileExists(abs) {
+			v = abs
+		} else if !fileExists(v) {
+			continue
 		}
-		if fileExists(v) {
-			return v
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
 		}
 	}
-	return ""
+	return out
 }
 
 func computeAssetID(path string) (string, error) {
@@ -935,8 +2312,68 @@ func computeAssetID(path string) (string, error) {
 	return "ast_" + sum[:16], nil
 }
 
+// assetHashWorkers bounds concurrent reads when hashing a playlist or an
+// imported directory.
+const assetHashWorkers = 4
+
+// computeAssetIDs hashes several files with at most workers concurrent reads
+// and returns path -> asset_id. Each id comes from computeAssetID, so results
+// are identical to hashing the files one by one; the first error wins.
+func computeAssetIDs(paths []string, workers int) (map[string]string, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(paths) {
+		workers = len(paths)
+	}
+
+	ids := make([]string, len(paths))
+	errs := make([]error, len(paths))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				ids[i], errs[i] = computeAssetID(paths[i])
+			}
+		}()
+	}
+	for i := range paths {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	out := make(map[string]string, len(paths))
+	for i, p := range paths {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("%s: %w", p, errs[i])
+		}
+		out[p] = ids[i]
+	}
+	return out, nil
+}
+
+// computeContentSHA256 hashes the whole file. Unlike computeAssetID it reads
+// every byte, so it catches corruption anywhere in the file.
+func computeContentSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
 func printGetJSON(v getJSONResult) {
-	data, err := json.Marshal(v)
+	data, err := marshalJSONResult(v)
 	if err != nil {
 		logError("json.marshal_failed", "context", "get_result", "error", err)
 		return
@@ -1154,16 +2591,31 @@ func isRunnableFile(path string) bool {
 	return info.Mode()&0o111 != 0
 }
 
-func buildAuthSources() []authSource {
+// buildAuthSources returns the browser cookie sources to try in order. An
+// explicit browser (flag, then MINGEST_BROWSER) pins a single source; the
+// profile and keyring overrides apply to whichever browser is used.
+// MINGEST_BROWSER may carry a keyring as `chrome+gnomekeyring`; the flag wins.
+func buildAuthSources(browser, profile, keyring string) []authSource {
+	if v := strings.ToLower(strings.TrimSpace(browser)); v != "" {
+		logInfo("auth.browser_overridden", "browser", v, "profile", profile, "keyring", keyring)
+		return []authSource{{Kind: authKindBrowser, Value: v, Profile: profile, Keyring: keyring}}
+	}
 	if v := strings.TrimSpace(os.Getenv("MINGEST_BROWSER")); v != "" {
-		lower := strings.ToLower(v)
-		return []authSource{{Kind: authKindBrowser, Value: lower}}
+		lower, envKeyring := splitBrowserKeyring(v)
+		if envKeyring != "" && !contains(ytDlpKeyrings, envKeyring) {
+			logWarn("auth.keyring_invalid", "value", envKeyring, "env", "MINGEST_BROWSER", "supported", strings.Join(ytDlpKeyrings, "|"))
+			envKeyring = ""
+		}
+		if keyring == "" {
+			keyring = envKeyring
+		}
+		return []authSource{{Kind: authKindBrowser, Value: lower, Profile: profile, Keyring: keyring}}
 	}
 
 	browsers := autoBrowserOrder()
 	out := make([]authSource, 0, len(browsers))
 	for _, b := range browsers {
-		out = append(out, authSource{Kind: authKindBrowser, Value: b})
+		out = append(out, authSource{Kind: authKindBrowser, Value: b, Profile: profile, Keyring: keyring})
 	}
 	return out
 }
@@ -1279,9 +2731,21 @@ func fileExists(path string) bool {
 
 func runWithAuthFallback(targetURL string, d deps, platform videoPlatform, sources []authSource, cookieFile string, cfg ytDlpConfig) (int, []string) {
 	// 0) Fast path: try cached cookies first (no browser DB access).
-	if strings.TrimSpace(cookieFile) != "" {
+	// Skip it when every auth cookie in the cache has already expired; that attempt would only fail.
+	useCache := strings.TrimSpace(cookieFile) != ""
+	if useCache && len(sources) > 0 && fileExists(cookieFile) {
+		if expired, err := cookieFileAuthExpired(cookieFile, platform, time.Now()); err != nil {
+			logWarn("auth.cookie_cache_expiry_check_failed", "error", err, "path", cookieFile)
+		} else if expired {
+			logInfo("auth.cookie_cache_expired_skip", "path", cookieFile)
+			useCache = false
+		}
+	}
+	if useCache {
 		logInfo("auth.method_selected", "source", "cookie_cache")
-		code, paths := runYtDlp(d, buildYtDlpArgsWithCookiesFile(targetURL, d, cookieFile, cfg), platform, cfg)
+		args, done := buildYtDlpArgsWithCookiesFile(targetURL, d, platform, cookieFile, cfg)
+		code, paths, _ := runYtDlpWithCooldown(d, args, platform, cfg)
+		done()
 		// Always attempt to filter after yt-dlp touches the cookie jar.
 		if fileExists(cookieFile) {
 			if err := filterCookieFileForPlatform(cookieFile, platform); err != nil {
@@ -1328,7 +2792,7 @@ func runWithAuthFallback(targetURL string, d deps, platform videoPlatform, sourc
 		} else {
 			args = buildYtDlpArgsWithCookieCache(targetURL, d, src, cookieFile, cfg)
 		}
-		code, paths := runYtDlp(d, args, platform, cfg)
+		code, paths, failureClass := runYtDlpWithCooldown(d, args, platform, cfg)
 		// Best-effort: if the browser attempt produced an authenticated cookie jar, update cache.
 		if tmpCookieFile != "" && fileExists(tmpCookieFile) && strings.TrimSpace(cookieFile) != "" {
 			if err := filterCookieFileForPlatform(tmpCookieFile, platform); err != nil {
@@ -1353,10 +2817,32 @@ func runWithAuthFallback(targetURL string, d deps, platform videoPlatform, sourc
 			return code, paths
 		}
 		// Prefer Chrome, but on Windows Chrome cookie decryption frequently fails.
-		// When chrome fails, try CDP (Chrome gives us decrypted cookies) before falling back to Firefox.
-		if src.Kind == authKindBrowser && src.Value == "chrome" && shouldTryNextAuth(code) {
-			logWarn("auth.chrome_cookie_failed_try_cdp")
-			cdpCode, cdpPaths := tryDownloadWithChromeCDP(targetURL, d, platform, cookieFile, cfg)
+		// When chrome fails (or any Chromium browser hits App-Bound encryption), try CDP
+		// (Chrome gives us decrypted cookies) before falling back to Firefox.
+		appBound := failureClass == failureClassAppBound
+		if src.Kind == authKindBrowser && (src.Value == "chrome" || appBound) && shouldTryNextAuth(code) {
+			if appBound {
+				logWarn("auth.app_bound_encryption_try_cdp", "browser", src.Value)
+			} else {
+				logWarn("auth.chrome_cookie_failed_try_cdp")
+			}
+			cdpBrowser := "chrome"
+			if isCDPBrowser(src.Value) {
+				cdpBrowser = src.Value
+			}
+			cdpDownload := cfg.CDPDownload
+			if cdpDownload == nil {
+				cdpDownload = tryDownloadWithChromeCDP
+			}
+			cdpCode, cdpPaths := cdpDownload(targetURL, d, platform, cookieFile, cfg, cdpBrowser)
+			// The managed profile isn't logged in yet: for App-Bound failures, run the
+			// interactive login once instead of asking the user to re-run with `mingest auth`.
+			if cdpCode == exitAuthRequired && appBound && canPromptInteractiveAuth(platform, cfg) {
+				logInfo("auth.app_bound_interactive_login", "platform", platform.ID)
+				if runAuth(platform, authOptions{PlatformID: platform.ID, Browser: cdpBrowser, ConsentURL: targetURL}) == exitOK {
+					cdpCode, cdpPaths = cdpDownload(targetURL, d, platform, cookieFile, cfg, cdpBrowser)
+				}
+			}
 			if cdpCode == exitOK {
 				if strings.TrimSpace(cookieFile) != "" && fileExists(cookieFile) {
 					if err := filterCookieFileForPlatform(cookieFile, platform); err != nil {
@@ -1419,11 +2905,7 @@ func buildYtDlpArgs(targetURL string, d deps, src authSource, cfg ytDlpConfig) [
 
 	switch src.Kind {
 	case authKindBrowser:
-		browserArg := src.Value
-		if p := strings.TrimSpace(os.Getenv("MINGEST_BROWSER_PROFILE")); p != "" {
-			browserArg = browserArg + ":" + p
-		}
-		args = append(args, "--cookies-from-browser", browserArg)
+		args = append(args, "--cookies-from-browser", browserCookieArg(src))
 	default:
 		// no auth args
 	}
@@ -1432,16 +2914,62 @@ func buildYtDlpArgs(targetURL string, d deps, src authSource, cfg ytDlpConfig) [
 	return args
 }
 
+// browserCookieArg builds the `--cookies-from-browser` value from the source's profile
+// (`--cookies-profile`), falling back to MINGEST_BROWSER_PROFILE.
+// For Firefox the profile may carry a multi-account container as `Profile::Container`
+// (or `::Container` for the default profile), matching yt-dlp's BROWSER[+KEYRING]:PROFILE::CONTAINER.
+func browserCookieArg(src authSource) string {
+	browser := src.Value
+	raw := strings.TrimSpace(src.Profile)
+	if raw == "" {
+		raw = strings.TrimSpace(os.Getenv("MINGEST_BROWSER_PROFILE"))
+	}
+	if raw == "" {
+		return browserWithKeyring(src)
+	}
+
+	profile, container, hasContainer := strings.Cut(raw, "::")
+	profile = strings.TrimSpace(profile)
+	container = strings.TrimSpace(container)
+	if hasContainer {
+		switch {
+		case browser != "firefox":
+			logWarn("auth.browser_container_ignored", "browser", browser, "reason", "containers are firefox-only")
+			container = ""
+		case container == "" || strings.Contains(container, "::"):
+			logWarn("auth.browser_container_invalid", "value", raw, "expected", "Profile::Container or ::Container")
+			container = ""
+		}
+	}
+
+	arg := browserWithKeyring(src)
+	if profile != "" {
+		arg += ":" + profile
+	}
+	if container != "" {
+		arg += "::" + container
+	}
+	return arg
+}
+
+// browserWithKeyring returns `browser+keyring` when a keyring applies to the browser.
+func browserWithKeyring(src authSource) string {
+	if src.Keyring == "" {
+		return src.Value
+	}
+	if !browserUsesKeyring(src.Value) {
+		logWarn("auth.keyring_ignored", "browser", src.Value, "keyring", src.Keyring, "reason", "keyrings are chromium-only")
+		return src.Value
+	}
+	return src.Value + "+" + src.Keyring
+}
+
 func buildYtDlpArgsWithCookieCache(targetURL string, d deps, src authSource, cookieFile string, cfg ytDlpConfig) []string {
 	args := buildYtDlpBaseArgs(d, cfg)
 
 	switch src.Kind {
 	case authKindBrowser:
-		browserArg := src.Value
-		if p := strings.TrimSpace(os.Getenv("MINGEST_BROWSER_PROFILE")); p != "" {
-			browserArg = browserArg + ":" + p
-		}
-		args = append(args, "--cookies-from-browser", browserArg)
+		args = append(args, "--cookies-from-browser", browserCookieArg(src))
 	default:
 		// no auth args
 	}
@@ -1454,10 +2982,13 @@ func buildYtDlpArgsWithCookieCache(targetURL string, d deps, src authSource, coo
 	return args
 }
 
-func buildYtDlpArgsWithCookiesFile(targetURL string, d deps, cookieFile string, cfg ytDlpConfig) []string {
+// buildYtDlpArgsWithCookiesFile passes a platform-scoped copy of cookieFile to yt-dlp.
+// Call done() after yt-dlp exits to fold its cookie updates back into cookieFile.
+func buildYtDlpArgsWithCookiesFile(targetURL string, d deps, platform videoPlatform, cookieFile string, cfg ytDlpConfig) ([]string, func()) {
+	jar, done := scopedCookieJarForPlatform(cookieFile, platform)
 	args := buildYtDlpBaseArgs(d, cfg)
-	args = append(args, "--cookies", cookieFile, targetURL)
-	return args
+	args = append(args, "--cookies", jar, targetURL)
+	return args, done
 }
 
 func buildYtDlpBaseArgs(d deps, cfg ytDlpConfig) []string {
@@ -1477,10 +3008,16 @@ func buildYtDlpBaseArgs(d deps, cfg ytDlpConfig) []string {
 		args = append(args, "--encoding", "utf-8")
 	}
 
+	args = append(args, "--output", outputTemplate)
+	// Both post-processors run ffmpeg on the finished file; some extractors
+	// fail only at the thumbnail step, so each can be turned off.
+	if !cfg.NoThumbnail {
+		args = append(args, "--embed-thumbnail")
+	}
+	if !cfg.NoMetadata {
+		args = append(args, "--add-metadata")
+	}
 	args = append(args,
-		"--output", outputTemplate,
-		"--embed-thumbnail",
-		"--add-metadata",
 		"-f", "bestvideo[vcodec^=avc1]+bestaudio[ext=m4a]/best[ext=mp4]/best",
 		"--merge-output-format", "mp4",
 	)
@@ -1494,41 +3031,183 @@ func buildYtDlpBaseArgs(d deps, cfg ytDlpConfig) []string {
 	if cfg.CaptureMovedPath {
 		args = append(args, "--print", "after_move:"+ytDlpPathMarker+"%(filepath)s")
 	}
+	if cfg.Proxy != "" {
+		args = append(args, "--proxy", cfg.Proxy)
+	}
+	if cfg.LimitRate != "" {
+		args = append(args, "--limit-rate", cfg.LimitRate)
+	}
+	if cfg.MaxFilesize != "" {
+		args = append(args, "--max-filesize", cfg.MaxFilesize)
+	}
+	if cfg.WaitForVideo != "" {
+		args = append(args, "--wait-for-video", cfg.WaitForVideo)
+	}
+	if cfg.LiveFromStart {
+		args = append(args, "--live-from-start")
+	}
+	if cfg.RestrictNames {
+		args = append(args, "--restrict-filenames")
+	}
+	if cfg.SleepInterval > 0 {
+		args = append(args, "--sleep-interval", strconv.FormatFloat(cfg.SleepInterval, 'f', -1, 64))
+	}
+	if cfg.Section != "" {
+		args = append(args, "--download-sections", cfg.Section)
+	}
+	// --add-metadata already embeds the source's chapters; ask for them
+	// explicitly on request or for SponsorBlock marks (stored as chapters) so
+	// they survive --no-metadata.
+	if cfg.EmbedChapters || cfg.SponsorBlock == "mark" {
+		args = append(args, "--embed-chapters")
+	}
+	switch cfg.SponsorBlock {
+	case "mark":
+		// Chapters are embedded via --embed-chapters above; the fixed title lets prep find them.
+		args = append(args,
+			"--sponsorblock-mark", cfg.SponsorCats,
+			"--sponsorblock-chapter-title", sponsorBlockChapterPrefix+" %(category)s",
+		)
+	case "remove":
+		args = append(args, "--sponsorblock-remove", cfg.SponsorCats)
+	}
 	return args
 }
 
+// Stable error_code values for `get --json`; wrapper tools branch on these
+// instead of the localized error text.
+const (
+	errorCodeInvalidArgument  = "INVALID_ARGUMENT"
+	errorCodeAuthRequired     = "AUTH_REQUIRED"
+	errorCodeAgeRestricted    = "AGE_RESTRICTED"
+	errorCodeCookieProblem    = "COOKIE_PROBLEM"
+	errorCodeCookieDBLocked   = "COOKIE_DB_LOCKED"
+	errorCodeCookieDecrypt    = "COOKIE_DECRYPT_FAILED"
+	errorCodeCookieKeyring    = "COOKIE_KEYRING_UNAVAILABLE"
+	errorCodeCookiePermission = "COOKIE_PERMISSION_DENIED"
+	errorCodeCookieFile       = "COOKIE_FILE_INVALID"
+	errorCodeAppBound         = "APP_BOUND_ENCRYPTION"
+	errorCodeRuntimeMissing   = "RUNTIME_MISSING"
+	errorCodeFFmpegMissing    = "FFMPEG_MISSING"
+	errorCodeFFprobeMissing   = "FFPROBE_MISSING"
+	errorCodeYtDlpMissing     = "YTDLP_MISSING"
+	errorCodeThumbnailEmbed   = "THUMBNAIL_EMBED_FAILED"
+	errorCodeFileTooLarge     = "FILE_TOO_LARGE"
+	errorCodeLiveStream       = "LIVE_STREAM"
+	errorCodeLiveNotStarted   = "LIVE_NOT_STARTED"
+	errorCodeRateLimited      = "RATE_LIMITED"
+	errorCodeTimedOut         = "TIMED_OUT"
+	errorCodePartialDownload  = "PARTIAL_DOWNLOAD"
+	errorCodeOutputMissing    = "OUTPUT_PATH_MISSING"
+	errorCodeAssetIDFailed    = "ASSET_ID_FAILED"
+	errorCodeVerifyFailed     = "VERIFY_FAILED"
+	errorCodeDownloadFailed   = "DOWNLOAD_FAILED"
+)
+
+// errorCodeForExit is the coarse error_code for failures that were not
+// classified from yt-dlp output.
+func errorCodeForExit(code int) string {
+	switch code {
+	case exitUsage:
+		return errorCodeInvalidArgument
+	case exitAuthRequired:
+		return errorCodeAuthRequired
+	case exitCookieProblem:
+		return errorCodeCookieProblem
+	case exitRuntimeMissing:
+		return errorCodeRuntimeMissing
+	case exitFFmpegMissing:
+		return errorCodeFFmpegMissing
+	case exitYtDlpMissing:
+		return errorCodeYtDlpMissing
+	case exitVerifyFailed:
+		return errorCodeVerifyFailed
+	default:
+		return errorCodeDownloadFailed
+	}
+}
+
+// Failure classes reported by runYtDlpClassified for callers that branch on them.
+const (
+	failureClassTimedOut    = "timed_out"
+	failureClassAppBound    = "app_bound_encryption"
+	failureClassRateLimited = "rate_limited"
+)
+
+const (
+	defaultRateLimitCooldown = 30 * time.Second
+	// rateLimitRetries is how many times one auth source is retried after a
+	// 429; other sources are not tried, since the limit is per client/IP.
+	rateLimitRetries = 2
+)
+
+// rateLimitCooldown is the base wait after a 429: MINGEST_RATE_LIMIT_COOLDOWN
+// when set, else the platform's RateLimitCooldown, else 30s.
+func rateLimitCooldown(platform videoPlatform) time.Duration {
+	if raw := strings.TrimSpace(os.Getenv("MINGEST_RATE_LIMIT_COOLDOWN")); raw != "" {
+		d, err := parseTimeoutValue(raw)
+		if err == nil {
+			return d
+		}
+		logWarn("yt_dlp.rate_limit_cooldown_env_invalid", "env", "MINGEST_RATE_LIMIT_COOLDOWN", "value", raw, "error", err)
+	}
+	if platform.RateLimitCooldown > 0 {
+		return platform.RateLimitCooldown
+	}
+	return defaultRateLimitCooldown
+}
+
+// runYtDlpWithCooldown runs yt-dlp and, while it is rate limited (HTTP 429),
+// waits an increasing cooldown and retries the same args up to rateLimitRetries times.
+func runYtDlpWithCooldown(d deps, args []string, platform videoPlatform, cfg ytDlpConfig) (int, []string, string) {
+	code, paths, failureClass := cfg.runner().Run(d, args, platform, cfg)
+	for attempt := 1; failureClass == failureClassRateLimited && attempt <= rateLimitRetries; attempt++ {
+		wait := rateLimitCooldown(platform) * time.Duration(attempt)
+		logWarn("yt_dlp.rate_limited_wait", "platform", platform.ID, "wait", wait.String(), "attempt", attempt, "max_attempts", rateLimitRetries)
+		time.Sleep(wait)
+		code, paths, failureClass = cfg.runner().Run(d, args, platform, cfg)
+	}
+	return code, paths, failureClass
+}
+
 func runYtDlp(d deps, args []string, platform videoPlatform, cfg ytDlpConfig) (int, []string) {
+	code, paths, _ := cfg.runner().Run(d, args, platform, cfg)
+	return code, paths
+}
+
+func runYtDlpClassified(d deps, args []string, platform videoPlatform, cfg ytDlpConfig) (int, []string, string) {
 	stdoutR, stdoutW, err := os.Pipe()
 	if err != nil {
 		logError("yt_dlp.stdout_pipe_create_failed", "error", err)
-		return exitDownloadFailed, nil
+		return exitDownloadFailed, nil, ""
 	}
 	stderrR, stderrW, err := os.Pipe()
 	if err != nil {
 		_ = stdoutR.Close()
 		_ = stdoutW.Close()
 		logError("yt_dlp.stderr_pipe_create_failed", "error", err)
-		return exitDownloadFailed, nil
+		return exitDownloadFailed, nil, ""
 	}
 
 	procArgs := append([]string{d.YtDlp.Path}, args...)
+	logDebug("yt_dlp.command", "argv", redactYtDlpArgs(procArgs))
 	env := withPrependedPath(os.Environ(), filepath.Dir(d.JSRuntime.Path))
 	// Make yt-dlp output deterministic on Windows consoles and when piped.
 	env = withEnvVar(env, "PYTHONUTF8", "1")
 	env = withEnvVar(env, "PYTHONIOENCODING", "utf-8")
-	proc, err := os.StartProcess(
-		d.YtDlp.Path,
-		procArgs,
-		&os.ProcAttr{
-			Env: env,
-			Dir: ".",
-			Files: []*os.File{
-				os.Stdin,
-				stdoutW,
-				stderrW,
-			},
+	attr := &os.ProcAttr{
+		Env: env,
+		Dir: ".",
+		Files: []*os.File{
+			os.Stdin,
+			stdoutW,
+			stderrW,
 		},
-	)
+		// Own process group so a timeout or Ctrl-C (via installSignalCleanup)
+		// can stop yt-dlp together with the ffmpeg helpers it spawned.
+		Sys: newProcessGroupAttr(),
+	}
+	proc, err := os.StartProcess(d.YtDlp.Path, procArgs, attr)
 	_ = stdoutW.Close()
 	_ = stderrW.Close()
 
@@ -1536,8 +3215,9 @@ func runYtDlp(d deps, args []string, platform videoPlatform, cfg ytDlpConfig) (i
 		_ = stdoutR.Close()
 		_ = stderrR.Close()
 		logError("yt_dlp.start_failed", "error", err)
-		return exitDownloadFailed, nil
+		return exitDownloadFailed, nil, ""
 	}
+	defer registerCleanup(func() { _ = killProcessTree(proc) })()
 
 	var stdoutBuf bytes.Buffer
 	var stderrBuf bytes.Buffer
@@ -1545,9 +3225,9 @@ func runYtDlp(d deps, args []string, platform videoPlatform, cfg ytDlpConfig) (i
 	wg.Add(2)
 
 	stdoutTarget := io.Writer(os.Stdout)
-	stderrTarget := io.Writer(os.Stderr)
+	stderrTarget := statusWriter()
 	progress := newProgressRenderer(stderrTarget)
-	if cfg.Quiet {
+	if cfg.Quiet || quietMode {
 		stdoutTarget = io.Discard
 		stderrTarget = io.Discard
 		progress = nil
@@ -1565,30 +3245,127 @@ func runYtDlp(d deps, args []string, platform videoPlatform, cfg ytDlpConfig) (i
 		Progress: progress,
 	}, &wg)
 
+	var timedOut atomic.Bool
+	if cfg.Timeout > 0 {
+		timer := time.AfterFunc(cfg.Timeout, func() {
+			timedOut.Store(true)
+			if err := killProcessTree(proc); err != nil {
+				logWarn("yt_dlp.kill_failed", "pid", proc.Pid, "error", err)
+			}
+		})
+		defer timer.Stop()
+	}
+
 	state, waitErr := proc.Wait()
-	wg.Wait()
+	if timedOut.Load() {
+		// Orphaned helpers may still hold the pipe write ends; don't let them block us.
+		drained := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(drained)
+		}()
+		select {
+		case <-drained:
+		case <-time.After(5 * time.Second):
+			_ = stdoutR.Close()
+			_ = stderrR.Close()
+			<-drained
+		}
+	} else {
+		wg.Wait()
+	}
 	if progress != nil {
 		progress.finish()
 	}
 	combined := stdoutBuf.String() + "\n" + stderrBuf.String()
+	if cfg.Destinations != nil {
+		*cfg.Destinations = append(*cfg.Destinations, extractDownloadDestinations(stdoutBuf.String())...)
+	}
 
+	if timedOut.Load() {
+		logError("yt_dlp.timed_out", "classification", "TIMED_OUT", "timeout", cfg.Timeout.String())
+		hint := tr("hint.timed_out")
+		logWarn("yt_dlp.failure_hint", "hint", hint)
+		if cfg.Failure != nil {
+			*cfg.Failure = ytDlpFailure{ExitCode: exitDownloadFailed, ErrorCode: errorCodeTimedOut, Hint: hint}
+		}
+		return exitDownloadFailed, nil, failureClassTimedOut
+	}
 	if waitErr != nil {
 		logError("yt_dlp.wait_failed", "error", waitErr)
-		return exitDownloadFailed, nil
+		return exitDownloadFailed, nil, ""
+	}
+	// yt-dlp skips a file over --max-filesize and still exits 0; nothing was saved.
+	if state.Success() && cfg.MaxFilesize != "" && isMaxFilesizeExceeded(combined) {
+		hint := tr("hint.file_too_large", cfg.MaxFilesize)
+		logError("yt_dlp.file_too_large", "max_filesize", cfg.MaxFilesize)
+		logWarn("yt_dlp.failure_hint", "hint", hint)
+		if cfg.Failure != nil {
+			*cfg.Failure = ytDlpFailure{ExitCode: exitDownloadFailed, ErrorCode: errorCodeFileTooLarge, Hint: hint}
+		}
+		logDebug("yt_dlp.finished", "exit_code", 0, "classified_exit_code", exitDownloadFailed, "error_code", errorCodeFileTooLarge)
+		return exitDownloadFailed, nil, ""
 	}
 	if state.Success() {
-		return exitOK, extractMovedPaths(stdoutBuf.String(), cfg.CaptureMovedPath)
+		logDebug("yt_dlp.finished", "exit_code", 0)
+		return exitOK, extractMovedPaths(stdoutBuf.String(), cfg.CaptureMovedPath), ""
 	}
 
-	code, hint := classifyFailure(combined, platform)
+	code, errorCode, hint := classifyFailure(combined, platform)
 	if hint != "" {
 		logWarn("yt_dlp.failure_hint", "hint", hint)
 	}
+	if cfg.Failure != nil {
+		*cfg.Failure = ytDlpFailure{ExitCode: code, ErrorCode: errorCode, Hint: hint}
+	}
 	if code == exitDownloadFailed {
 		logError("yt_dlp.exit_code_unexpected", "exit_code", state.ExitCode())
 	}
 
-	return code, nil
+	failureClass := ""
+	if isAppBoundCookieError(combined) {
+		failureClass = failureClassAppBound
+	} else if errorCode == errorCodeRateLimited {
+		failureClass = failureClassRateLimited
+	}
+	logDebug("yt_dlp.finished", "exit_code", state.ExitCode(), "classified_exit_code", code, "error_code", errorCode, "failure_class", failureClass)
+	return code, nil, failureClass
+}
+
+// redactYtDlpArgs copies a yt-dlp argv for logging with the cookie jar path
+// and any proxy password hidden.
+func redactYtDlpArgs(args []string) []string {
+	out := append([]string(nil), args...)
+	for i := 0; i+1 < len(out); i++ {
+		switch out[i] {
+		case "--cookies":
+			i++
+			out[i] = "<redacted>"
+		case "--proxy":
+			i++
+			if u, err := url.Parse(out[i]); err == nil && u.User != nil {
+				out[i] = u.Redacted()
+			}
+		}
+	}
+	return out
+}
+
+// extractDownloadDestinations returns the paths from yt-dlp's
+// "[download] Destination: <path>" lines, in order.
+func extractDownloadDestinations(stdout string) []string {
+	const prefix = "[download] Destination:"
+	var out []string
+	for _, line := range strings.Split(stdout, "\n") {
+		v := strings.TrimSpace(line)
+		if !strings.HasPrefix(v, prefix) {
+			continue
+		}
+		if p := strings.TrimSpace(strings.TrimPrefix(v, prefix)); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
 }
 
 func extractMovedPaths(stdout string, enabled bool) []string {
@@ -1799,7 +3576,85 @@ func withEnvVar(env []string, key, value string) []string {
 	return out
 }
 
-func classifyFailure(output string, platform videoPlatform) (int, string) {
+func isAppBoundCookieError(output string) bool {
+	lower := strings.ToLower(output)
+	return strings.Contains(lower, "app-bound") && strings.Contains(lower, "cookie") && strings.Contains(lower, "encrypt")
+}
+
+// isRateLimited matches an HTTP 429 from the extractor or the media host.
+func isRateLimited(output string) bool {
+	lower := strings.ToLower(output)
+	return strings.Contains(lower, "http error 429") || strings.Contains(lower, "too many requests")
+}
+
+// isLiveNotStarted matches yt-dlp's messages for scheduled streams and
+// premieres ("This live event will begin in 3 hours", "Premieres in 10 minutes").
+func isLiveNotStarted(output string) bool {
+	lower := strings.ToLower(output)
+	return strings.Contains(lower, "this live event will begin in") ||
+		strings.Contains(lower, "premieres in") ||
+		strings.Contains(lower, "live event will begin")
+}
+
+// ytDlpLiveInfo is the subset of --dump-single-json used to spot live streams.
+type ytDlpLiveInfo struct {
+	IsLive     bool   `json:"is_live"`
+	LiveStatus string `json:"live_status"`
+}
+
+// refuseLiveStream pre-fetches the URL's metadata and refuses streams that
+// are still live (the file would keep growing, so asset_id means nothing) or
+// not started yet. ok is true when the download may go ahead; a failed
+// pre-fetch never blocks it, since the real download reports its own error.
+// The pre-fetch shares the download's --timeout.
+func refuseLiveStream(d deps, targetURL string, p videoPlatform, cookieFile, proxy string, timeout time.Duration) (ytDlpFailure, bool) {
+	jar := ""
+	if cookieFile != "" && fileExists(cookieFile) {
+		jar = cookieFile
+	}
+	var extra []string
+	if proxy != "" {
+		extra = append(extra, "--proxy", proxy)
+	}
+	raw, err := fetchYtDlpInfoJSON(d, targetURL, jar, timeout, extra...)
+	if jar != "" && fileExists(jar) {
+		if ferr := filterCookieFileForPlatform(jar, p); ferr != nil {
+			logWarn("auth.cookie_filter_failed", "error", ferr, "path", jar)
+		}
+	}
+	if err != nil {
+		if isLiveNotStarted(err.Error()) {
+			logError("get.live_not_started", "url", targetURL)
+			return ytDlpFailure{ErrorCode: errorCodeLiveNotStarted, Hint: tr("hint.live_not_started")}, false
+		}
+		logDebug("get.live_check_skipped", "url", targetURL, "error", err)
+		return ytDlpFailure{}, true
+	}
+	var info ytDlpLiveInfo
+	if err := json.Unmarshal([]byte(raw), &info); err != nil {
+		logDebug("get.live_check_skipped", "url", targetURL, "error", err)
+		return ytDlpFailure{}, true
+	}
+	switch {
+	case info.LiveStatus == "is_upcoming":
+		logError("get.live_not_started", "url", targetURL)
+		return ytDlpFailure{ErrorCode: errorCodeLiveNotStarted, Hint: tr("hint.live_not_started")}, false
+	case info.IsLive || info.LiveStatus == "is_live":
+		logError("get.live_stream_refused", "url", targetURL)
+		return ytDlpFailure{ErrorCode: errorCodeLiveStream, Hint: tr("hint.live_stream")}, false
+	}
+	return ytDlpFailure{}, true
+}
+
+// isMaxFilesizeExceeded matches yt-dlp's "File is larger than max-filesize
+// (N bytes > M bytes). Aborting." message.
+func isMaxFilesizeExceeded(output string) bool {
+	return strings.Contains(strings.ToLower(output), "larger than max-filesize")
+}
+
+// classifyFailure maps yt-dlp output to an exit code, a stable error_code for
+// --json consumers, and a localized hint.
+func classifyFailure(output string, platform videoPlatform) (int, string, string) {
 	lower := strings.ToLower(output)
 
 	authCmd := "mingest auth <platform>"
@@ -1811,44 +3666,57 @@ func classifyFailure(output string, platform videoPlatform) (int, string) {
 		name = strings.TrimSpace(platform.ID)
 	}
 
+	// Retrying with other cookies can't make the file smaller.
+	if isMaxFilesizeExceeded(output) {
+		return exitDownloadFailed, errorCodeFileTooLarge, tr("hint.file_too_large", "--max-filesize")
+	}
+
+	if isRateLimited(output) {
+		return exitDownloadFailed, errorCodeRateLimited, tr("hint.rate_limited")
+	}
+
+	if isLiveNotStarted(output) {
+		return exitDownloadFailed, errorCodeLiveNotStarted, tr("hint.live_not_started")
+	}
+
 	if strings.Contains(lower, "could not copy") && strings.Contains(lower, "cookie database") {
-		return exitCookieProblem, fmt.Sprintf("浏览器 cookies 数据库无法读取（常见原因: 浏览器仍在占用 cookies 数据库）。请先彻底退出浏览器（含后台进程）后重试；或改用 Firefox；或执行 `%s`（使用 CDP 从浏览器进程内导出 cookies，避免读取数据库）。", authCmd)
+		return exitCookieProblem, errorCodeCookieDBLocked, tr("hint.cookie_db_locked", authCmd)
 	}
 
 	if strings.Contains(lower, "failed to decrypt with dpapi") {
-		return exitCookieProblem, fmt.Sprintf("浏览器 cookies 解密失败。请改用 Firefox，或执行 `%s`。", authCmd)
+		return exitCookieProblem, errorCodeCookieDecrypt, tr("hint.cookie_decrypt", authCmd)
 	}
 
 	// Chrome's App-Bound Cookie Encryption on Windows intentionally makes third-party decryption harder.
 	// When enabled, tools that read/decrypt the cookie DB may fail even with admin rights.
-	if strings.Contains(lower, "app-bound") && strings.Contains(lower, "cookie") && strings.Contains(lower, "encrypt") {
-		return exitCookieProblem, fmt.Sprintf("检测到 Chrome App-Bound Cookie Encryption 相关错误。此模式下第三方工具可能无法直接解密 Chrome cookies。建议改用 `%s`（CDP 方式）或改用 Firefox/Edge 的账户登录信息。", authCmd)
+	if isAppBoundCookieError(output) {
+		return exitCookieProblem, errorCodeAppBound, tr("hint.app_bound", authCmd)
 	}
 
 	if strings.Contains(lower, "permission denied") && strings.Contains(lower, "cookies") {
-		return exitCookieProblem, "读取浏览器 cookies 被拒绝。请检查浏览器进程占用与文件权限。"
+		return exitCookieProblem, errorCodeCookiePermission, tr("hint.cookie_permission")
 	}
 
 	if strings.Contains(lower, "cannot decrypt v11 cookies: no key found") {
-		return exitCookieProblem, fmt.Sprintf("浏览器 cookies 解密失败（keyring 不可用）。如果你是 SSH 会话，请在本机桌面终端运行，或改用 Firefox，或执行 `%s`。", authCmd)
+		return exitCookieProblem, errorCodeCookieKeyring, tr("hint.cookie_keyring", authCmd)
 	}
 
 	if strings.Contains(lower, "sign in to confirm you're not a bot") ||
 		strings.Contains(lower, "sign in to confirm you’re not a bot") {
-		target := "目标网站"
+		target := tr("hint.target_site")
 		if name != "" {
 			target = name
 		}
-		return exitAuthRequired, fmt.Sprintf("需要登录 %s。请先在浏览器登录后重试，或执行 `%s`。", target, authCmd)
+		return exitAuthRequired, errorCodeAuthRequired, tr("hint.auth_required", target, authCmd)
 	}
 
 	if strings.Contains(lower, "sign in to confirm your age") ||
 		(strings.Contains(lower, "this video may be inappropriate for some users") && strings.Contains(lower, "sign in")) {
-		target := "目标网站"
+		target := tr("hint.target_site")
 		if name != "" {
 			target = name
 		}
-		return exitAuthRequired, fmt.Sprintf("需要登录 %s 并完成额外确认。请在浏览器中登录并打开该视频完成确认后重试；或执行 `%s` 使用工具专用账户登录信息。", target, authCmd)
+		return exitAuthRequired, errorCodeAgeRestricted, tr("hint.age_restricted", target, authCmd)
 	}
 
 	// Generic "cookies suggested" auth-required detection for other extractors (e.g. bilibili).
@@ -1860,29 +3728,35 @@ func classifyFailure(output string, platform videoPlatform) (int, string) {
 			strings.Contains(lower, "members only") ||
 			strings.Contains(lower, "members-only") ||
 			strings.Contains(lower, "authentication") {
-			target := "目标网站"
+			target := tr("hint.target_site")
 			if name != "" {
 				target = name
 			}
-			return exitAuthRequired, fmt.Sprintf("需要登录 %s（或账号具备相应权限）。请先在浏览器中登录后重试；或执行 `%s`。", target, authCmd)
+			return exitAuthRequired, errorCodeAuthRequired, tr("hint.auth_permission", target, authCmd)
 		}
 	}
 
 	if strings.Contains(lower, "cookies file") && strings.Contains(lower, "netscape") {
-		return exitCookieProblem, "cookies 文件格式异常。"
+		return exitCookieProblem, errorCodeCookieFile, tr("hint.cookie_file_invalid")
 	}
 
 	if strings.Contains(lower, "no supported javascript runtime could be found") {
-		return exitRuntimeMissing, "JS runtime 不可用。请确认 deno 或 node 可执行，并可被该程序访问。"
+		return exitRuntimeMissing, errorCodeRuntimeMissing, tr("hint.runtime_missing")
 	}
 
 	if strings.Contains(lower, "ffmpeg not found") {
-		return exitFFmpegMissing, "ffmpeg 不可用。请将 ffmpeg/ffprobe 放在同一目录（工作目录或程序同目录），或加入 PATH，或改用 *_bundled。"
+		return exitFFmpegMissing, errorCodeFFmpegMissing, tr("hint.ffmpeg_missing")
 	}
 
 	if strings.Contains(lower, "ffprobe not found") {
-		return exitFFmpegMissing, "ffprobe 不可用。请将 ffmpeg/ffprobe 放在同一目录（工作目录或程序同目录），或加入 PATH，或改用 *_bundled。"
+		return exitFFmpegMissing, errorCodeFFprobeMissing, tr("hint.ffprobe_missing")
+	}
+
+	// The media itself downloaded; only the --embed-thumbnail post-processor failed.
+	if strings.Contains(lower, "embedthumbnail") ||
+		(strings.Contains(lower, "postprocessing") && strings.Contains(lower, "thumbnail")) {
+		return exitDownloadFailed, errorCodeThumbnailEmbed, tr("hint.thumbnail_embed_failed")
 	}
 
-	return exitDownloadFailed, "下载失败。可先执行 `yt-dlp -U` 更新，再检查 cookies 是否过期。"
+	return exitDownloadFailed, errorCodeDownloadFailed, tr("hint.download_failed")
 }
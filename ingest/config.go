@@ -0,0 +1,208 @@
+// media-ingest (mingest) - Media Ingestion CLI tool
+// Copyright (C) 2026  Harrison Wang <https://mingest.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ingest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// appConfig holds per-command flag defaults loaded from config.json.
+//
+// Example:
+//
+//	{
+//	  "semantic": {"target": "shorts", "provider": "openrouter", "model": "openai/gpt-4.1-mini"},
+//	  "export":   {"to": "capcut", "zip": true},
+//	  "env":      {"MINGEST_BROWSER": "firefox"}
+//	}
+//
+// Keys under a command are flag names without the leading `--`. Precedence is
+// CLI flag > environment variable > config file > built-in default. A switch set
+// to true in the file is turned off for one run with `--no-<key>`. The global
+// `--library` is not a per-command flag; set it via env MINGEST_LIBRARY.
+type appConfig struct {
+	Path     string
+	Commands map[string]map[string]interface{}
+	Env      map[string]string
+}
+
+// configFlagEnv lists, per command, every flag whose value can also come from
+// an environment variable. When that variable is set, the file value is not
+// injected so the env var keeps precedence over the file. A new env-backed
+// flag must be added here too.
+var configFlagEnv = map[string]map[string]string{
+	"get": {
+		"timeout":         "MINGEST_DOWNLOAD_TIMEOUT",
+		"proxy":           "MINGEST_PROXY",
+		"on-complete":     "MINGEST_ON_COMPLETE",
+		"cookies-browser": "MINGEST_BROWSER",
+		"cookies-profile": "MINGEST_BROWSER_PROFILE",
+	},
+	"prep": {
+		"bundle-dir":     "MINGEST_BUNDLE_ROOT",
+		"keep-temp":      "MINGEST_KEEP_TEMP",
+		"whisper-device": "MINGEST_WHISPER_DEVICE",
+	},
+	"semantic": {
+		"model":       "MINGEST_LLM_MODEL",
+		"bundle-dir":  "MINGEST_BUNDLE_ROOT",
+		"proxy":       "MINGEST_PROXY",
+		"llm-timeout": "MINGEST_LLM_TIMEOUT",
+	},
+	"export": {
+		"bundle-dir": "MINGEST_BUNDLE_ROOT",
+	},
+	"doctor": {
+		"bundle-dir": "MINGEST_BUNDLE_ROOT",
+	},
+}
+
+func appConfigFilePath() (string, error) {
+	if p := strings.TrimSpace(os.Getenv("MINGEST_CONFIG")); p != "" {
+		return p, nil
+	}
+	base, err := appStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "config.json"), nil
+}
+
+func loadAppConfig() (appConfig, error) {
+	path, err := appConfigFilePath()
+	if err != nil {
+		return appConfig{}, err
+	}
+	cfg := appConfig{Path: path}
+	if !fileExists(path) {
+		return cfg, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return cfg, fmt.Errorf("解析配置文件失败: %w", err)
+	}
+
+	cfg.Commands = make(map[string]map[string]interface{}, len(raw))
+	for key, value := range raw {
+		name := strings.ToLower(strings.TrimSpace(key))
+		if name == "env" {
+			if err := json.Unmarshal(value, &cfg.Env); err != nil {
+				return cfg, fmt.Errorf("配置项 env 格式无效: %w", err)
+			}
+			continue
+		}
+		var section map[string]interface{}
+		if err := json.Unmarshal(value, &section); err != nil {
+			return cfg, fmt.Errorf("配置项 %s 格式无效: %w", key, err)
+		}
+		cfg.Commands[name] = section
+	}
+	return cfg, nil
+}
+
+// applyEnv exports config env entries that are not already set in the process.
+func (c appConfig) applyEnv() {
+	for k, v := range c.Env {
+		key := strings.TrimSpace(k)
+		if key == "" {
+			continue
+		}
+		if _, ok := os.LookupEnv(key); ok {
+			continue
+		}
+		_ = os.Setenv(key, v)
+	}
+}
+
+// withDefaults prepends config-derived flags for command to args. Parsers take
+// the last occurrence of a flag, so explicit CLI flags still win; switches have
+// no off form in the parsers, so a `--no-<key>` in args drops a config true and
+// is consumed here.
+func (c appConfig) withDefaults(command string, args []string) []string {
+	section := c.Commands[command]
+	if len(section) == 0 {
+		return args
+	}
+
+	keys := make([]string, 0, len(section))
+	for k := range section {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	negated := make(map[string]bool)
+	for _, k := range keys {
+		if v, ok := section[k].(bool); ok && v {
+			negated["--no-"+strings.TrimPrefix(strings.TrimSpace(k), "--")] = false
+		}
+	}
+	rest := make([]string, 0, len(args))
+	for _, arg := range args {
+		if _, ok := negated[strings.TrimSpace(arg)]; ok {
+			negated[strings.TrimSpace(arg)] = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+
+	out := make([]string, 0, len(rest)+len(keys)*2)
+	for _, k := range keys {
+		name := strings.TrimPrefix(strings.TrimSpace(k), "--")
+		flag := "--" + name
+		if name == "library" {
+			// applyLibraryFlag has already consumed --library; parsers reject it.
+			logWarn("config.value_ignored", "path", c.Path, "command", command, "key", k, "hint", "use env.MINGEST_LIBRARY")
+			continue
+		}
+		if envKey, ok := configFlagEnv[command][name]; ok && strings.TrimSpace(os.Getenv(envKey)) != "" {
+			continue
+		}
+		switch v := section[k].(type) {
+		case bool:
+			if v && !negated["--no-"+name] {
+				out = append(out, flag)
+			}
+		case string:
+			out = append(out, flag+"="+v)
+		case float64:
+			out = append(out, flag+"="+strconv.FormatFloat(v, 'f', -1, 64))
+		case []interface{}:
+			parts := make([]string, 0, len(v))
+			for _, item := range v {
+				parts = append(parts, fmt.Sprint(item))
+			}
+			out = append(out, flag+"="+strings.Join(parts, ","))
+		default:
+			logWarn("config.value_ignored", "path", c.Path, "command", command, "key", k)
+		}
+	}
+	if len(out) > 0 {
+		logDebug("config.defaults_applied", "path", c.Path, "command", command, "args", strings.Join(out, " "))
+	}
+	return append(out, rest...)
+}
@@ -0,0 +1,107 @@
+// media-ingest (mingest) - Media Ingestion CLI tool
+// Copyright (C) 2026  Harrison Wang <https://mingest.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ingest
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigDefaultsYieldToEnv(t *testing.T) {
+	for command, flags := range configFlagEnv {
+		for flag, env := range flags {
+			t.Run(command+"/"+flag, func(t *testing.T) {
+				cfg := appConfig{Commands: map[string]map[string]interface{}{
+					command: {flag: "from-config"},
+				}}
+
+				t.Setenv(env, "")
+				if got := cfg.withDefaults(command, nil); len(got) != 1 || got[0] != "--"+flag+"=from-config" {
+					t.Fatalf("env unset: withDefaults = %q, want config value injected", got)
+				}
+
+				t.Setenv(env, "from-env")
+				if got := cfg.withDefaults(command, nil); len(got) != 0 {
+					t.Fatalf("%s set: withDefaults = %q, want config value skipped", env, got)
+				}
+			})
+		}
+	}
+}
+
+func TestConfigEnvWinsOverConfigValue(t *testing.T) {
+	cfg := appConfig{Commands: map[string]map[string]interface{}{
+		"get":  {"timeout": "30s"},
+		"prep": {"bundle-dir": "/from/config"},
+	}}
+	t.Setenv("MINGEST_DOWNLOAD_TIMEOUT", "5m")
+	t.Setenv("MINGEST_BUNDLE_ROOT", "/from/env")
+
+	getOpts, err := parseGetOptions(cfg.withDefaults("get", []string{"https://www.youtube.com/watch?v=dQw4w9WgXcQ"}))
+	if err != nil {
+		t.Fatalf("parseGetOptions: %v", err)
+	}
+	if got := resolveDownloadTimeout(getOpts.Timeout); got != 5*time.Minute {
+		t.Errorf("download timeout = %s, want 5m from MINGEST_DOWNLOAD_TIMEOUT", got)
+	}
+
+	prepOpts, err := parsePrepOptions(cfg.withDefaults("prep", []string{"asset.mp4", "--goal=shorts"}))
+	if err != nil {
+		t.Fatalf("parsePrepOptions: %v", err)
+	}
+	if got := mingestBundleRoot("/media/asset.mp4", prepOpts.BundleDir); got != "/from/env" {
+		t.Errorf("bundle root = %s, want /from/env from MINGEST_BUNDLE_ROOT", got)
+	}
+
+	// An explicit flag still beats both.
+	getOpts, err = parseGetOptions(cfg.withDefaults("get", []string{"https://www.youtube.com/watch?v=dQw4w9WgXcQ", "--timeout=10s"}))
+	if err != nil {
+		t.Fatalf("parseGetOptions: %v", err)
+	}
+	if got := resolveDownloadTimeout(getOpts.Timeout); got != 10*time.Second {
+		t.Errorf("download timeout = %s, want 10s from --timeout", got)
+	}
+}
+
+func TestConfigLibraryKeyNotInjected(t *testing.T) {
+	cfg := appConfig{Commands: map[string]map[string]interface{}{
+		"ls": {"library": "/tmp/lib"},
+	}}
+	got := cfg.withDefaults("ls", nil)
+	if _, err := parseLsOptions(got); err != nil {
+		t.Fatalf("parseLsOptions(%q): %v", got, err)
+	}
+	for _, arg := range got {
+		if strings.HasPrefix(arg, "--library") {
+			t.Fatalf("withDefaults = %q, want no --library flag", got)
+		}
+	}
+}
+
+func TestConfigBoolDefaultNegatedFromCLI(t *testing.T) {
+	cfg := appConfig{Commands: map[string]map[string]interface{}{
+		"export": {"zip": true},
+	}}
+	if got := cfg.withDefaults("export", []string{"asset"}); len(got) != 2 || got[0] != "--zip" {
+		t.Fatalf("withDefaults = %q, want --zip injected", got)
+	}
+	got := cfg.withDefaults("export", []string{"asset", "--no-zip"})
+	if len(got) != 1 || got[0] != "asset" {
+		t.Fatalf("withDefaults = %q, want --zip dropped and --no-zip consumed", got)
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -38,6 +39,50 @@ func filterCookieFileForPlatform(path string, p videoPlatform) error {
 	return filterNetscapeCookieFile(path, p.AllowsCookieDomain)
 }
 
+// scopedCookieJarForPlatform returns a temp copy of cookieFile that only holds the
+// platform's cookie domains, so yt-dlp never sees cookies for unrelated sites.
+// Names are not filtered: AuthCookieNames only detects a login, while the
+// session also needs the platform's other cookies (e.g. HSID, SIDCC, buvid3).
+// yt-dlp writes the jar back on exit; done() merges those updates into cookieFile
+// and removes the copy. Unknown platforms (and missing jars) get cookieFile as-is.
+func scopedCookieJarForPlatform(cookieFile string, p videoPlatform) (string, func()) {
+	noop := func() {}
+	if strings.TrimSpace(p.ID) == "" || len(p.CookieDomainSuffixes) == 0 || !fileExists(cookieFile) {
+		return cookieFile, noop
+	}
+
+	tmpPath, cleanup, err := createTempCookieJarFile(filepath.Dir(cookieFile))
+	if err == nil {
+		err = copyFileAtomic(cookieFile, tmpPath)
+	}
+	if err == nil {
+		err = filterNetscapeCookieFile(tmpPath, p.AllowsCookieDomain)
+	}
+	if err != nil {
+		if cleanup != nil {
+			cleanup()
+		}
+		// The cache itself is kept filtered, so falling back to it only loses the pre-filter.
+		logWarn("auth.cookie_scope_failed", "error", err, "path", cookieFile)
+		return cookieFile, noop
+	}
+
+	done := func() {
+		defer cleanup()
+		if !fileExists(tmpPath) {
+			return
+		}
+		if err := filterNetscapeCookieFile(tmpPath, p.AllowsCookieDomain); err != nil {
+			logWarn("auth.cookie_filter_failed", "error", err, "path", tmpPath)
+			return
+		}
+		if err := copyFileAtomic(tmpPath, cookieFile); err != nil {
+			logWarn("auth.cookie_cache_update_failed", "error", err, "path", cookieFile)
+		}
+	}
+	return tmpPath, done
+}
+
 func filterNetscapeCookieFile(path string, allowDomain func(string) bool) error {
 	in, err := os.Open(path)
 	if err != nil {
@@ -163,7 +208,11 @@ func createTempCookieJarFile(dir string) (string, func(), error) {
 	}
 
 	_ = os.Chmod(path, 0o600)
-	cleanup := func() { _ = os.Remove(path) }
+	unregister := registerCleanup(func() { _ = os.Remove(path) })
+	cleanup := func() {
+		unregister()
+		_ = os.Remove(path)
+	}
 	return path, cleanup, nil
 }
 
@@ -222,6 +271,64 @@ func cookieFileLooksLikeAuthenticated(path string, p videoPlatform) (bool, error
 	return false, nil
 }
 
+// cookieFileAuthExpired reports whether every auth cookie in the jar has an expiry in the past.
+// Session cookies (expiry 0/empty) are assumed valid; a jar without auth cookies is not "expired".
+func cookieFileAuthExpired(path string, p videoPlatform, now time.Time) (bool, error) {
+	if strings.TrimSpace(path) == "" || len(p.AuthCookieNames) == 0 {
+		return false, nil
+	}
+
+	in, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer in.Close()
+
+	sc := bufio.NewScanner(in)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	const httpOnlyPrefix = "#HttpOnly_"
+	found := false
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		work := line
+		if strings.HasPrefix(work, httpOnlyPrefix) {
+			work = strings.TrimPrefix(work, httpOnlyPrefix)
+		} else if strings.HasPrefix(work, "#") {
+			continue
+		}
+
+		parts := strings.Split(work, "\t")
+		if len(parts) < 7 {
+			continue
+		}
+		if !p.AllowsCookieDomain(parts[0]) || strings.TrimSpace(parts[6]) == "" {
+			continue
+		}
+		if !contains(p.AuthCookieNames, parts[5]) {
+			continue
+		}
+
+		found = true
+		expires, err := strconv.ParseInt(strings.TrimSpace(parts[4]), 10, 64)
+		if err != nil || expires <= 0 {
+			// Session cookie (or unparsable expiry): assume it is still valid.
+			return false, nil
+		}
+		if time.Unix(expires, 0).After(now) {
+			return false, nil
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return false, err
+	}
+	return found, nil
+}
+
 func copyFileAtomic(srcPath, dstPath string) error {
 	b, err := os.ReadFile(srcPath)
 	if err != nil {
@@ -253,3 +360,67 @@ func copyFileAtomic(srcPath, dstPath string) error {
 	_ = os.Chmod(dstPath, 0o600)
 	return nil
 }
+
+// netscapeCookie is one parsed line of a Netscape cookie jar.
+type netscapeCookie struct {
+	Domain            string
+	IncludeSubdomains bool
+	Path              string
+	Secure            bool
+	HTTPOnly          bool
+	Expires           int64
+	Name              string
+	Value             string
+}
+
+func readNetscapeCookieFile(path string) ([]netscapeCookie, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	sc := bufio.NewScanner(in)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	const httpOnlyPrefix = "#HttpOnly_"
+	var out []netscapeCookie
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		work := line
+		httpOnly := false
+		if strings.HasPrefix(work, httpOnlyPrefix) {
+			work = strings.TrimPrefix(work, httpOnlyPrefix)
+			httpOnly = true
+		} else if strings.HasPrefix(work, "#") {
+			continue
+		}
+
+		parts := strings.Split(work, "\t")
+		if len(parts) < 7 {
+			continue
+		}
+		expires, err := strconv.ParseInt(strings.TrimSpace(parts[4]), 10, 64)
+		if err != nil || expires < 0 {
+			expires = 0
+		}
+		out = append(out, netscapeCookie{
+			Domain:            parts[0],
+			IncludeSubdomains: strings.EqualFold(parts[1], "TRUE"),
+			Path:              parts[2],
+			Secure:            strings.EqualFold(parts[3], "TRUE"),
+			HTTPOnly:          httpOnly,
+			Expires:           expires,
+			Name:              parts[5],
+			Value:             parts[6],
+		})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
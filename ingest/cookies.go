@@ -0,0 +1,361 @@
+// media-ingest (mingest) - Media Ingestion CLI tool
+// Copyright (C) 2026  Harrison Wang <https://mingest.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ingest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type cookiesOptions struct {
+	Action     string
+	PlatformID string
+	ShowValues bool
+	OutPath    string
+	Browser    string
+	JSON       bool
+}
+
+type cookieInspectEntry struct {
+	Name      string `json:"name"`
+	Domain    string `json:"domain"`
+	Path      string `json:"path"`
+	Secure    bool   `json:"secure"`
+	HTTPOnly  bool   `json:"http_only"`
+	Session   bool   `json:"session"`
+	ExpiresAt string `json:"expires_at,omitempty"`
+	Expired   bool   `json:"expired"`
+	Auth      bool   `json:"auth"`
+	Value     string `json:"value"`
+}
+
+type cookiesInspectJSONResult struct {
+	OK          bool                 `json:"ok"`
+	ExitCode    int                  `json:"exit_code"`
+	Error       string               `json:"error,omitempty"`
+	Platform    string               `json:"platform,omitempty"`
+	Path        string               `json:"path,omitempty"`
+	CookieCount int                  `json:"cookie_count"`
+	AuthCount   int                  `json:"auth_count"`
+	Cookies     []cookieInspectEntry `json:"cookies,omitempty"`
+}
+
+type cookiesExportJSONResult struct {
+	OK          bool   `json:"ok"`
+	ExitCode    int    `json:"exit_code"`
+	Error       string `json:"error,omitempty"`
+	Platform    string `json:"platform,omitempty"`
+	Browser     string `json:"browser,omitempty"`
+	OutputPath  string `json:"output_path,omitempty"`
+	CookieCount int    `json:"cookie_count"`
+	AuthCount   int    `json:"auth_count"`
+	Warning     string `json:"warning,omitempty"`
+}
+
+const cookiesExportWarning = "导出的 cookie 文件等同于账户登录凭据：仅通过安全渠道复制到可信机器，不要提交到仓库或粘贴到日志，用完请删除"
+
+func parseCookiesOptions(args []string) (cookiesOptions, error) {
+	opts := cookiesOptions{}
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		switch {
+		case arg == "--show-values":
+			opts.ShowValues = true
+		case arg == "--json":
+			opts.JSON = true
+		case arg == "--out":
+			if i+1 >= len(args) {
+				return cookiesOptions{}, fmt.Errorf("`--out` 缺少参数")
+			}
+			i++
+			opts.OutPath = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--out="):
+			opts.OutPath = strings.TrimSpace(strings.TrimPrefix(arg, "--out="))
+		case arg == "--browser":
+			if i+1 >= len(args) {
+				return cookiesOptions{}, fmt.Errorf("`--browser` 缺少参数")
+			}
+			i++
+			opts.Browser = strings.ToLower(strings.TrimSpace(args[i]))
+		case strings.HasPrefix(arg, "--browser="):
+			opts.Browser = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--browser=")))
+		case strings.HasPrefix(arg, "-"):
+			return cookiesOptions{}, fmt.Errorf("不支持的参数: %s", arg)
+		default:
+			positional = append(positional, arg)
+		}
+	}
+	if len(positional) == 0 {
+		return cookiesOptions{}, fmt.Errorf("缺少子命令。用法: mingest cookies <inspect|export> <platform>")
+	}
+	opts.Action = strings.ToLower(positional[0])
+	switch opts.Action {
+	case "inspect":
+		if opts.OutPath != "" || opts.Browser != "" {
+			return cookiesOptions{}, fmt.Errorf("`--out` / `--browser` 仅用于 `mingest cookies export`")
+		}
+	case "export":
+		if opts.ShowValues {
+			return cookiesOptions{}, fmt.Errorf("`--show-values` 仅用于 `mingest cookies inspect`")
+		}
+		if opts.OutPath == "" {
+			return cookiesOptions{}, fmt.Errorf("缺少 `--out`。用法: mingest cookies export <platform> --out <jar.txt> [--browser <chrome|chromium|edge|brave>] [--json]")
+		}
+		if opts.Browser != "" && !isCDPBrowser(opts.Browser) {
+			return cookiesOptions{}, fmt.Errorf("`--browser` 仅支持 chrome|chromium|edge|brave")
+		}
+	default:
+		return cookiesOptions{}, fmt.Errorf("`mingest cookies` 仅支持 inspect|export")
+	}
+	if len(positional) < 2 {
+		return cookiesOptions{}, fmt.Errorf("缺少 platform。用法: mingest cookies %s <platform>", opts.Action)
+	}
+	if len(positional) > 2 {
+		return cookiesOptions{}, fmt.Errorf("`mingest cookies %s` 仅支持一个 platform", opts.Action)
+	}
+	opts.PlatformID = positional[1]
+	return opts, nil
+}
+
+func runCookies(opts cookiesOptions) int {
+	p, ok := platformByID(opts.PlatformID)
+	if !ok {
+		logError("cookies.unsupported_platform", "platform", opts.PlatformID)
+		usage()
+		return exitUsage
+	}
+	if opts.Action == "export" {
+		return runCookiesExport(p, opts)
+	}
+	return runCookiesInspect(p, opts)
+}
+
+func runCookiesInspect(p videoPlatform, opts cookiesOptions) int {
+	result := executeCookiesInspect(p, opts)
+	if opts.JSON {
+		printCookiesInspectJSON(result)
+		return result.ExitCode
+	}
+	if !result.OK {
+		logError("cookies.inspect_failed", "exit_code", result.ExitCode, "detail", result.Error)
+		return result.ExitCode
+	}
+	fmt.Printf("platform: %s\n", result.Platform)
+	fmt.Printf("path: %s\n", result.Path)
+	fmt.Printf("cookie_count: %d\n", result.CookieCount)
+	fmt.Printf("auth_count: %d\n", result.AuthCount)
+	for _, c := range result.Cookies {
+		mark := " "
+		if c.Auth {
+			mark = "*"
+		}
+		secure := "-"
+		if c.Secure {
+			secure = "secure"
+		}
+		expires := "session"
+		if !c.Session {
+			expires = c.ExpiresAt
+			if c.Expired {
+				expires += " (expired)"
+			}
+		}
+		fmt.Printf("%s %-28s %-24s %-6s %-32s %s\n", mark, c.Name, c.Domain, secure, expires, c.Value)
+	}
+	if result.AuthCount > 0 {
+		fmt.Println("* = 登录态 cookie")
+	}
+	return exitOK
+}
+
+func executeCookiesInspect(p videoPlatform, opts cookiesOptions) cookiesInspectJSONResult {
+	fail := func(code int, msg string) cookiesInspectJSONResult {
+		return cookiesInspectJSONResult{OK: false, ExitCode: code, Error: msg, Platform: p.ID}
+	}
+
+	path, err := cookiesCacheFilePath(p)
+	if err != nil {
+		return fail(exitCookieProblem, fmt.Sprintf("无法定位 cookie 缓存: %v", err))
+	}
+	cookies, err := readNetscapeCookieFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fail(exitAuthRequired, fmt.Sprintf("未找到 %s 的 cookie 缓存: %s（请先运行 mingest auth %s）", p.ID, path, p.ID))
+		}
+		return fail(exitCookieProblem, fmt.Sprintf("读取 cookie 缓存失败: %v", err))
+	}
+
+	now := time.Now()
+	result := cookiesInspectJSONResult{OK: true, ExitCode: exitOK, Platform: p.ID, Path: path}
+	for _, c := range cookies {
+		entry := cookieInspectEntry{
+			Name:     c.Name,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			Secure:   c.Secure,
+			HTTPOnly: c.HTTPOnly,
+			Session:  c.Expires <= 0,
+			Auth:     contains(p.AuthCookieNames, c.Name),
+			Value:    redactCookieValue(c.Value),
+		}
+		if opts.ShowValues {
+			entry.Value = c.Value
+		}
+		if !entry.Session {
+			expires := time.Unix(c.Expires, 0)
+			entry.ExpiresAt = expires.Local().Format("2006-01-02 15:04:05")
+			entry.Expired = !expires.After(now)
+		}
+		if entry.Auth {
+			result.AuthCount++
+		}
+		result.Cookies = append(result.Cookies, entry)
+	}
+	result.CookieCount = len(result.Cookies)
+	return result
+}
+
+// redactCookieValue keeps only the length so two dumps can still be compared.
+func redactCookieValue(v string) string {
+	if v == "" {
+		return ""
+	}
+	return fmt.Sprintf("<redacted:%d>", len(v))
+}
+
+func printCookiesInspectJSON(v cookiesInspectJSONResult) {
+	data, err := marshalJSONResult(v)
+	if err != nil {
+		logError("json.marshal_failed", "context", "cookies_inspect_result", "error", err)
+		return
+	}
+	fmt.Println(string(data))
+}
+
+func runCookiesExport(p videoPlatform, opts cookiesOptions) int {
+	result := executeCookiesExport(p, opts)
+	if result.OK {
+		logWarn("cookies.export_sensitive", "path", result.OutputPath, "detail", result.Warning)
+	}
+	if opts.JSON {
+		printCookiesExportJSON(result)
+		return result.ExitCode
+	}
+	if !result.OK {
+		logError("cookies.export_failed", "exit_code", result.ExitCode, "detail", result.Error)
+		return result.ExitCode
+	}
+	fmt.Printf("platform: %s\n", result.Platform)
+	fmt.Printf("browser: %s\n", result.Browser)
+	fmt.Printf("output_path: %s\n", result.OutputPath)
+	fmt.Printf("cookie_count: %d\n", result.CookieCount)
+	fmt.Printf("auth_count: %d\n", result.AuthCount)
+	return exitOK
+}
+
+// executeCookiesExport pulls cookies out of the managed CDP profile (the same one
+// `mingest auth` logs into) and writes a platform-scoped Netscape jar to opts.OutPath.
+// The internal cache is left untouched.
+func executeCookiesExport(p videoPlatform, opts cookiesOptions) cookiesExportJSONResult {
+	browser := resolveCDPBrowser(opts.Browser)
+	fail := func(code int, msg string) cookiesExportJSONResult {
+		return cookiesExportJSONResult{OK: false, ExitCode: code, Error: msg, Platform: p.ID, Browser: browser}
+	}
+
+	outPath, err := filepath.Abs(opts.OutPath)
+	if err != nil {
+		return fail(exitUsage, fmt.Sprintf("无效的 --out: %v", err))
+	}
+	if cachePath, err := cookiesCacheFilePath(p); err == nil && filepath.Clean(cachePath) == outPath {
+		return fail(exitUsage, "--out 不能指向内部 cookie 缓存")
+	}
+
+	chromePath, err := findCDPBrowserExecutable(browser)
+	if err != nil {
+		return fail(exitCookieProblem, fmt.Sprintf("未找到浏览器 %s: %v", browser, err))
+	}
+	profileDir, err := cdpProfileDir(browser)
+	if err != nil {
+		return fail(exitCookieProblem, fmt.Sprintf("无法定位工具专用 profile: %v", err))
+	}
+	if err := os.MkdirAll(profileDir, 0o700); err != nil {
+		return fail(exitCookieProblem, fmt.Sprintf("创建工具专用 profile 失败: %v", err))
+	}
+
+	cookies := refreshCookiesViaCDP(chromePath, profileDir, p)
+	if cookies == nil {
+		// Same fallback as `mingest auth --refresh`, but only when someone can log in.
+		if !stdinIsTerminal() {
+			return fail(exitAuthRequired, fmt.Sprintf("工具专用 profile 未登录 %s，请先在本机运行 mingest auth %s --browser %s", p.ID, p.ID, browser))
+		}
+		logInfo("auth.user_login_prompt", "platform", p.ID)
+		cookies, err = chromeAuthViaCDP(chromePath, profileDir, p)
+		if err != nil {
+			if errors.Is(err, errCDPTimeout) {
+				return fail(exitCookieProblem, fmt.Sprintf("CDP 超时（可调 MINGEST_CDP_TIMEOUT）: %v", err))
+			}
+			return fail(exitAuthRequired, fmt.Sprintf("登录失败: %v", err))
+		}
+	}
+
+	result := cookiesExportJSONResult{OK: true, ExitCode: exitOK, Platform: p.ID, Browser: browser, OutputPath: outPath, Warning: cookiesExportWarning}
+	for _, c := range cookies {
+		if strings.TrimSpace(c.Domain) == "" || !p.AllowsCookieDomain(c.Domain) {
+			continue
+		}
+		result.CookieCount++
+		if c.Value != "" && contains(p.AuthCookieNames, c.Name) {
+			result.AuthCount++
+		}
+	}
+
+	dir := filepath.Dir(outPath)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fail(exitCookieProblem, fmt.Sprintf("创建输出目录失败: %v", err))
+	}
+	tmp, err := os.CreateTemp(dir, "mingest-cookies-export-*.tmp")
+	if err != nil {
+		return fail(exitCookieProblem, fmt.Sprintf("写入 cookie 文件失败: %v", err))
+	}
+	tmpPath := tmp.Name()
+	_ = tmp.Close()
+	defer func() { _ = os.Remove(tmpPath) }()
+	// Best effort: keep the jar private before it gets its final name. Windows ignores chmod.
+	_ = os.Chmod(tmpPath, 0o600)
+	if err := writeNetscapeCookieFile(tmpPath, cookies, p.AllowsCookieDomain); err != nil {
+		return fail(exitCookieProblem, fmt.Sprintf("写入 cookie 文件失败: %v", err))
+	}
+	if err := replaceFile(tmpPath, outPath); err != nil {
+		return fail(exitCookieProblem, fmt.Sprintf("写入 cookie 文件失败: %v", err))
+	}
+	_ = os.Chmod(outPath, 0o600)
+	return result
+}
+
+func printCookiesExportJSON(v cookiesExportJSONResult) {
+	data, err := marshalJSONResult(v)
+	if err != nil {
+		logError("json.marshal_failed", "context", "cookies_export_result", "error", err)
+		return
+	}
+	fmt.Println(string(data))
+}
@@ -17,7 +17,6 @@
 package ingest
 
 import (
-	"encoding/json"
 	"fmt"
 	"math"
 	"path/filepath"
@@ -28,17 +27,51 @@ import (
 )
 
 type doctorOptions struct {
-	AssetRef string
-	Target   string
-	Strict   bool
-	JSON     bool
+	AssetRef  string
+	Target    string
+	Strict    bool
+	Explain   bool
+	MinScore  int
+	BundleDir string
+	Bundle    string
+	JSON      bool
 }
 
 type doctorCheck struct {
-	ID      string                 `json:"id"`
-	Level   string                 `json:"level"` // pass|warn|fail
-	Message string                 `json:"message"`
-	Details map[string]interface{} `json:"details,omitempty"`
+	ID          string                 `json:"id"`
+	Level       string                 `json:"level"` // pass|warn|fail|skip
+	Message     string                 `json:"message"`
+	Details     map[string]interface{} `json:"details,omitempty"`
+	Remediation string                 `json:"remediation,omitempty"`
+}
+
+// doctorRemediations holds actionable hints per check ID, shown with --explain.
+var doctorRemediations = map[string]string{
+	"clip_count":               "重新执行 `mingest prep <asset> --goal <goal> --max-clips <n>` 调整片段数，或用 `mingest semantic --top-k <n> --apply` 重新挑选",
+	"timeline_range":           "片段时间超出素材时长或顺序异常：重新执行 `mingest prep` 生成计划，手动编辑过 prep-plan.json 时请检查 start_sec/end_sec",
+	"clip_duration":            "用 `mingest prep --clip-seconds <sec>` 调整单片段时长，使其落在目标平台的建议区间内",
+	"clip_overlap":             "片段重叠过多：减少 `--max-clips`，或用 `mingest semantic --visual-diversity` 提高去重强度后 `--apply`",
+	"subtitle_source":          "当前仅有模板字幕：登录后重试 `mingest prep`（获取平台字幕），或配置 MINGEST_WHISPER_PATH 启用本地转写",
+	"subtitle_coverage":        "字幕覆盖不足：确认字幕语言（`--lang`）正确，或改用 Whisper 转写后重新 prep",
+	"boundary_cut":             "片段起止点切断了字幕句子：运行 `mingest semantic --apply` 让片段对齐字幕边界，或手动对齐到字幕边界",
+	"semantic_duplicate":       "多个片段内容高度相似：用 `mingest semantic --apply` 重新挑选，或在评审决策中剔除重复片段",
+	"clip_speech_density":      "片段内有效语音过少（长时间静音/纯画面）：在评审决策中剔除该片段，或用 `mingest semantic --apply` 重新挑选",
+	"language_match":           "字幕语言与音轨语言不一致：用 `mingest prep --lang <音轨语言>` 重新选择字幕轨",
+	"uniform_sampling_pattern": "片段为等间隔采样：运行 `mingest semantic <asset> --apply` 以基于内容挑选片段",
+	"sponsor_overlap":          "片段与 SponsorBlock 赞助段重叠：运行 `mingest semantic <asset> --apply`（会排除赞助段内的候选），或在评审决策中剔除该片段",
+	"aspect_crop":              "目标画幅会裁掉大部分画面：在评审包中确认主体位于画面中心，或用 `mingest semantic <asset> --reframe blur-pad` 保留完整画面",
+}
+
+// applyDoctorRemediations attaches hints to every non-pass check.
+func applyDoctorRemediations(checks []doctorCheck) {
+	for i := range checks {
+		if checks[i].Level == "pass" {
+			continue
+		}
+		if hint, ok := doctorRemediations[checks[i].ID]; ok {
+			checks[i].Remediation = hint
+		}
+	}
 }
 
 type doctorSummary struct {
@@ -46,8 +79,16 @@ type doctorSummary struct {
 	Pass  int `json:"pass"`
 	Warn  int `json:"warn"`
 	Fail  int `json:"fail"`
+	Skip  int `json:"skip,omitempty"`
+	Score int `json:"score"` // 0-100 health score
 }
 
+// Health score deductions: a single fail drops the score below a typical 80 gate.
+const (
+	doctorScoreFailPenalty = 30
+	doctorScoreWarnPenalty = 8
+)
+
 type doctorJSONResult struct {
 	OK       bool          `json:"ok"`
 	ExitCode int           `json:"exit_code"`
@@ -56,6 +97,7 @@ type doctorJSONResult struct {
 	AssetRef string        `json:"asset_ref,omitempty"`
 	Target   string        `json:"target,omitempty"`
 	Strict   bool          `json:"strict,omitempty"`
+	MinScore int           `json:"min_score,omitempty"`
 	PrepPlan string        `json:"prep_plan,omitempty"`
 	Summary  doctorSummary `json:"summary,omitempty"`
 	Checks   []doctorCheck `json:"checks,omitempty"`
@@ -68,6 +110,7 @@ type doctorThreshold struct {
 	MinSubtitleCoverage   float64
 	MaxNearDuplicateScore float64
 	MaxBoundaryCutRate    float64
+	MinCharsPerSec        float64
 }
 
 func parseDoctorOptions(args []string) (doctorOptions, error) {
@@ -82,6 +125,26 @@ func parseDoctorOptions(args []string) (doctorOptions, error) {
 			opts.JSON = true
 		case arg == "--strict":
 			opts.Strict = true
+		case arg == "--explain":
+			opts.Explain = true
+		case arg == "--min-score":
+			if i+1 >= len(args) {
+				return doctorOptions{}, fmt.Errorf("`--min-score` 缺少参数")
+			}
+			i++
+			v := strings.TrimSpace(args[i])
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return doctorOptions{}, fmt.Errorf("`--min-score` 必须是整数: %s", v)
+			}
+			opts.MinScore = n
+		case strings.HasPrefix(arg, "--min-score="):
+			v := strings.TrimSpace(strings.TrimPrefix(arg, "--min-score="))
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return doctorOptions{}, fmt.Errorf("`--min-score` 必须是整数: %s", v)
+			}
+			opts.MinScore = n
 		case arg == "--target":
 			if i+1 >= len(args) {
 				return doctorOptions{}, fmt.Errorf("`--target` 缺少参数")
@@ -90,6 +153,22 @@ func parseDoctorOptions(args []string) (doctorOptions, error) {
 			opts.Target = strings.ToLower(strings.TrimSpace(args[i]))
 		case strings.HasPrefix(arg, "--target="):
 			opts.Target = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--target=")))
+		case arg == "--bundle-dir":
+			if i+1 >= len(args) {
+				return doctorOptions{}, fmt.Errorf("`--bundle-dir` 缺少参数")
+			}
+			i++
+			opts.BundleDir = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--bundle-dir="):
+			opts.BundleDir = strings.TrimSpace(strings.TrimPrefix(arg, "--bundle-dir="))
+		case arg == "--bundle":
+			if i+1 >= len(args) {
+				return doctorOptions{}, fmt.Errorf("`--bundle` 缺少参数")
+			}
+			i++
+			opts.Bundle = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--bundle="):
+			opts.Bundle = strings.TrimSpace(strings.TrimPrefix(arg, "--bundle="))
 		case strings.HasPrefix(arg, "-"):
 			return doctorOptions{}, fmt.Errorf("不支持的参数: %s", arg)
 		default:
@@ -101,13 +180,16 @@ func parseDoctorOptions(args []string) (doctorOptions, error) {
 	}
 
 	if strings.TrimSpace(opts.AssetRef) == "" {
-		return doctorOptions{}, fmt.Errorf("缺少 asset_ref。用法: mingest doctor <asset_ref> [--target <youtube|bilibili|shorts>] [--strict] [--json]")
+		return doctorOptions{}, fmt.Errorf("缺少 asset_ref。用法: mingest doctor <asset_ref> [--target <youtube|bilibili|shorts|douyin|xiaohongshu>] [--strict] [--json]")
 	}
 
 	switch opts.Target {
-	case "youtube", "bilibili", "shorts":
+	case "youtube", "bilibili", "shorts", "douyin", "xiaohongshu":
 	default:
-		return doctorOptions{}, fmt.Errorf("`--target` 仅支持 youtube|bilibili|shorts")
+		return doctorOptions{}, fmt.Errorf("`--target` 仅支持 youtube|bilibili|shorts|douyin|xiaohongshu")
+	}
+	if opts.MinScore < 0 || opts.MinScore > 100 {
+		return doctorOptions{}, fmt.Errorf("`--min-score` 需在 0-100")
 	}
 
 	return opts, nil
@@ -126,7 +208,7 @@ func runDoctor(opts doctorOptions) int {
 		asset.AssetID = assetID
 	}
 
-	_, prepPlanPath, err := latestPrepBundle(asset)
+	_, prepPlanPath, err := resolvePrepBundle(asset, opts.BundleDir, opts.Bundle)
 	if err != nil {
 		return doctorExitWithErr(opts.JSON, exitDownloadFailed, err.Error())
 	}
@@ -135,10 +217,19 @@ func runDoctor(opts doctorOptions) int {
 	if err != nil {
 		return doctorExitWithErr(opts.JSON, exitDownloadFailed, fmt.Sprintf("读取 prep-plan.json 失败: %v", err))
 	}
+	if err := validatePrepPlan(plan); err != nil {
+		return doctorExitWithErr(opts.JSON, exitDoctorFailed, err.Error())
+	}
 
 	checks := runDoctorChecks(opts, plan)
+	if opts.Explain {
+		applyDoctorRemediations(checks)
+	}
 	summary := summarizeDoctorChecks(checks)
 	ok := summary.Fail == 0
+	if opts.MinScore > 0 && summary.Score < opts.MinScore {
+		ok = false
+	}
 	exitCode := exitOK
 	if !ok {
 		exitCode = exitDoctorFailed
@@ -152,6 +243,7 @@ func runDoctor(opts doctorOptions) int {
 			AssetRef: strings.TrimSpace(opts.AssetRef),
 			Target:   opts.Target,
 			Strict:   opts.Strict,
+			MinScore: opts.MinScore,
 			PrepPlan: prepPlanPath,
 			Summary:  summary,
 			Checks:   checks,
@@ -170,8 +262,16 @@ func runDoctor(opts doctorOptions) int {
 	fmt.Printf("strict: %v\n", opts.Strict)
 	fmt.Printf("prep_plan: %s\n", prepPlanPath)
 	fmt.Printf("doctor: %s (pass=%d warn=%d fail=%d)\n", status, summary.Pass, summary.Warn, summary.Fail)
+	if opts.MinScore > 0 {
+		fmt.Printf("score: %d (min %d)\n", summary.Score, opts.MinScore)
+	} else {
+		fmt.Printf("score: %d\n", summary.Score)
+	}
 	for _, c := range checks {
 		fmt.Printf("[%s] %s: %s\n", strings.ToUpper(c.Level), c.ID, c.Message)
+		if c.Remediation != "" {
+			fmt.Printf("    建议: %s\n", c.Remediation)
+		}
 	}
 
 	return exitCode
@@ -194,6 +294,7 @@ func runDoctorChecks(opts doctorOptions, plan prepPlan) []doctorCheck {
 
 	cues, subtitlePath, hasRealSubtitle := loadDoctorSubtitle(plan)
 	checks = append(checks, doctorCheckSubtitleSource(hasRealSubtitle, subtitlePath))
+	checks = append(checks, doctorCheckLanguageMatch(plan))
 
 	if len(cues) == 0 {
 		checks = append(checks, doctorCheck{
@@ -203,11 +304,25 @@ func runDoctorChecks(opts doctorOptions, plan prepPlan) []doctorCheck {
 		})
 	} else {
 		checks = append(checks, doctorCheckSubtitleCoverage(clips, cues, threshold))
+		checks = append(checks, doctorCheckSpeechDensity(clips, cues, hasRealSubtitle, threshold))
 		checks = append(checks, doctorCheckBoundaryCuts(clips, cues, threshold))
 		checks = append(checks, doctorCheckNearDuplicate(clips, cues, threshold))
 	}
 
 	checks = append(checks, doctorCheckUniformPattern(clips))
+	if len(plan.Probe.SponsorSegments) > 0 {
+		checks = append(checks, doctorCheckSponsorOverlap(clips, plan.Probe.SponsorSegments))
+	}
+	if plan.Options.Aspect != "" && !plan.Probe.AudioOnly {
+		checks = append(checks, doctorCheckAspectCrop(clips, plan.Options.Aspect, plan.Probe))
+	}
+	if plan.Probe.AudioOnly {
+		checks = append(checks, doctorCheck{
+			ID:      "media_video",
+			Level:   "skip",
+			Message: "纯音频素材，跳过画面相关检查",
+		})
+	}
 	return checks
 }
 
@@ -219,20 +334,48 @@ func doctorThresholdFor(target string, strict bool) doctorThreshold {
 		MinSubtitleCoverage:   0.50,
 		MaxNearDuplicateScore: 0.85,
 		MaxBoundaryCutRate:    0.55,
+		MinCharsPerSec:        subtitleSparseCharsPerSec,
 	}
-	if target == "shorts" {
+	switch target {
+	case "shorts":
 		t.ClipMinSec = 10
 		t.ClipMaxSec = 65
 		t.MaxOverlapRatio = 0.18
 		t.MinSubtitleCoverage = 0.55
 		t.MaxNearDuplicateScore = 0.80
 		t.MaxBoundaryCutRate = 0.45
+		t.MinCharsPerSec = subtitleLowCharsPerSec
+	case "douyin":
+		// 抖音多为静音刷视频，字幕覆盖要求更高，节奏也更快。
+		t.ClipMinSec = 10
+		t.ClipMaxSec = 75
+		t.MaxOverlapRatio = 0.18
+		t.MinSubtitleCoverage = 0.60
+		t.MaxNearDuplicateScore = 0.80
+		t.MaxBoundaryCutRate = 0.45
+		t.MinCharsPerSec = 1.5
+	case "xiaohongshu":
+		// 小红书偏讲解/种草，允许更长的片段和稍慢的语速。
+		t.ClipMinSec = 15
+		t.ClipMaxSec = 110
+		t.MaxOverlapRatio = 0.20
+		t.MinSubtitleCoverage = 0.60
+		t.MaxNearDuplicateScore = 0.82
+		t.MaxBoundaryCutRate = 0.50
+		t.MinCharsPerSec = 1.2
 	}
 	if strict {
-		if target == "shorts" {
+		switch target {
+		case "shorts":
 			t.ClipMinSec = 15
 			t.ClipMaxSec = 45
-		} else {
+		case "douyin":
+			t.ClipMinSec = 15
+			t.ClipMaxSec = 60
+		case "xiaohongshu":
+			t.ClipMinSec = 30
+			t.ClipMaxSec = 90
+		default:
 			t.ClipMinSec = 15
 			t.ClipMaxSec = 90
 		}
@@ -240,6 +383,7 @@ func doctorThresholdFor(target string, strict bool) doctorThreshold {
 		t.MinSubtitleCoverage = math.Min(0.80, t.MinSubtitleCoverage+0.10)
 		t.MaxNearDuplicateScore = math.Max(0.72, t.MaxNearDuplicateScore-0.06)
 		t.MaxBoundaryCutRate = math.Max(0.30, t.MaxBoundaryCutRate-0.10)
+		t.MinCharsPerSec += 0.5
 	}
 	return t
 }
@@ -418,6 +562,48 @@ func doctorCheckSubtitleSource(hasRealSubtitle bool, subtitlePath string) doctor
 	}
 }
 
+func doctorCheckLanguageMatch(plan prepPlan) doctorCheck {
+	subtitleLang := ""
+	if plan.Subtitle != nil {
+		subtitleLang = plan.Subtitle.SelectedLanguage
+	}
+	subBase := baseLanguage(subtitleLang)
+
+	audioBases := make([]string, 0, len(plan.Probe.AudioLanguages))
+	for _, l := range plan.Probe.AudioLanguages {
+		if b := baseLanguage(l); b != "" {
+			audioBases = append(audioBases, b)
+		}
+	}
+
+	if subBase == "" || len(audioBases) == 0 {
+		return doctorCheck{
+			ID:      "language_match",
+			Level:   "skip",
+			Message: "字幕或音轨语言未知，跳过语言一致性检查",
+		}
+	}
+
+	details := map[string]interface{}{
+		"subtitle_language": subtitleLang,
+		"audio_languages":   plan.Probe.AudioLanguages,
+	}
+	if contains(audioBases, subBase) {
+		return doctorCheck{
+			ID:      "language_match",
+			Level:   "pass",
+			Message: "字幕语言与音轨语言一致",
+			Details: details,
+		}
+	}
+	return doctorCheck{
+		ID:      "language_match",
+		Level:   "warn",
+		Message: fmt.Sprintf("字幕语言（%s）与音轨语言（%s）不一致，可能选错了字幕轨", subtitleLang, strings.Join(plan.Probe.AudioLanguages, ",")),
+		Details: details,
+	}
+}
+
 func doctorCheckSubtitleCoverage(clips []prepClip, cues []subtitleCue, threshold doctorThreshold) doctorCheck {
 	if len(clips) == 0 {
 		return doctorCheck{
@@ -548,6 +734,74 @@ func doctorCheckNearDuplicate(clips []prepClip, cues []subtitleCue, threshold do
 	}
 }
 
+// doctorCheckSponsorOverlap flags clips that run into SponsorBlock segments
+// embedded by `get --sponsorblock mark`.
+func doctorCheckSponsorOverlap(clips []prepClip, segments []sponsorSegment) doctorCheck {
+	var hits []int
+	for _, c := range clips {
+		if sponsorOverlapSec(c.StartSec, c.EndSec, segments) > 0 {
+			hits = append(hits, c.Index)
+		}
+	}
+	if len(hits) == 0 {
+		return doctorCheck{
+			ID:      "sponsor_overlap",
+			Level:   "pass",
+			Message: fmt.Sprintf("片段均避开了 %d 个 SponsorBlock 段", len(segments)),
+		}
+	}
+	return doctorCheck{
+		ID:      "sponsor_overlap",
+		Level:   "warn",
+		Message: fmt.Sprintf("%d 个片段与 SponsorBlock 段重叠", len(hits)),
+		Details: map[string]interface{}{
+			"clip_indexes":     hits,
+			"sponsor_segments": len(segments),
+		},
+	}
+}
+
+// doctorAspectCropMinKeep is the smallest share of the source frame a center
+// crop may keep before doctor warns that on-screen content is likely lost.
+const doctorAspectCropMinKeep = 0.75
+
+// doctorCheckAspectCrop flags plans whose intended aspect needs a heavy
+// center crop. Without frame analysis it cannot tell where the subject is,
+// so every selected clip is listed for review.
+func doctorCheckAspectCrop(clips []prepClip, aspect string, probe mediaProbe) doctorCheck {
+	keep := aspectCropKeep(aspect, probe.Width, probe.Height)
+	if keep >= doctorAspectCropMinKeep {
+		return doctorCheck{
+			ID:      "aspect_crop",
+			Level:   "pass",
+			Message: fmt.Sprintf("目标画幅 %s 居中裁切保留 %.0f%% 画面", aspect, keep*100),
+		}
+	}
+	indexes := make([]int, 0, len(clips))
+	for _, c := range clips {
+		indexes = append(indexes, c.Index)
+	}
+	return doctorCheck{
+		ID:      "aspect_crop",
+		Level:   "warn",
+		Message: fmt.Sprintf("目标画幅 %s 居中裁切仅保留 %.0f%% 画面（源 %dx%d），画面边缘内容可能被裁掉", aspect, keep*100, probe.Width, probe.Height),
+		Details: map[string]interface{}{
+			"aspect":       aspect,
+			"keep_ratio":   roundMillis(keep),
+			"clip_indexes": indexes,
+		},
+	}
+}
+
+// sponsorOverlapSec returns how many seconds of [start, end) fall inside sponsor segments.
+func sponsorOverlapSec(start, end float64, segments []sponsorSegment) float64 {
+	total := 0.0
+	for _, s := range segments {
+		total += doctorIntersectionLen(start, end, s.StartSec, s.EndSec)
+	}
+	return total
+}
+
 func doctorCheckUniformPattern(clips []prepClip) doctorCheck {
 	if len(clips) < 3 {
 		return doctorCheck{
@@ -602,6 +856,20 @@ func doctorCheckUniformPattern(clips []prepClip) doctorCheck {
 }
 
 func loadDoctorSubtitle(plan prepPlan) ([]subtitleCue, string, bool) {
+	// A single-file plan carries its subtitles inline; read them in memory
+	// rather than from the (removed, possibly stale) Outputs paths.
+	if e := plan.Embedded; e != nil && (e.Subtitle != "" || e.SubtitleTemplate != "") {
+		subtitlePath, content, hasReal := strings.TrimSpace(plan.Outputs.SubtitlePath), e.Subtitle, true
+		if content == "" {
+			subtitlePath, content, hasReal = strings.TrimSpace(plan.Outputs.SubtitleTemplate), e.SubtitleTemplate, false
+		}
+		cues, err := parseSubtitleCuesFrom(strings.NewReader(content))
+		if err != nil {
+			return nil, subtitlePath, hasReal
+		}
+		return cues, subtitlePath, hasReal
+	}
+
 	subtitlePath := strings.TrimSpace(plan.Outputs.SubtitlePath)
 	hasReal := subtitlePath != "" && fileExists(subtitlePath)
 	if !hasReal {
@@ -620,6 +888,62 @@ func loadDoctorSubtitle(plan prepPlan) ([]subtitleCue, string, bool) {
 	return cues, subtitlePath, hasReal
 }
 
+// doctorClipCharsPerSec is the clip's subtitle characters per second of clip
+// time (silence included), measured as prep's subtitle scoring does.
+func doctorClipCharsPerSec(c prepClip, cues []subtitleCue) float64 {
+	clipDur := c.EndSec - c.StartSec
+	if clipDur <= 0 {
+		return 0
+	}
+	return measureSubtitleCues(cues, c.StartSec, c.EndSec).Chars / clipDur
+}
+
+func doctorCheckSpeechDensity(clips []prepClip, cues []subtitleCue, hasRealSubtitle bool, threshold doctorThreshold) doctorCheck {
+	if !hasRealSubtitle {
+		return doctorCheck{
+			ID:      "clip_speech_density",
+			Level:   "skip",
+			Message: "只有字幕模板，跳过语音密度检查",
+		}
+	}
+	if len(clips) == 0 {
+		// clip_count already fails for this.
+		return doctorCheck{
+			ID:      "clip_speech_density",
+			Level:   "skip",
+			Message: "无片段可检查",
+		}
+	}
+	densities := make([]float64, 0, len(clips))
+	low := make([]string, 0, len(clips))
+	for i, c := range clips {
+		cps := doctorClipCharsPerSec(c, cues)
+		densities = append(densities, cps)
+		if cps < threshold.MinCharsPerSec {
+			low = append(low, doctorClipLabel(c, i))
+		}
+	}
+	avg := doctorMean(densities)
+	minCPS := doctorMin(densities)
+	level := "pass"
+	msg := fmt.Sprintf("片段语音密度正常（avg=%.1f,min=%.1f 字符/秒）", avg, minCPS)
+	if len(low) > 0 {
+		level = "warn"
+		msg = fmt.Sprintf("有 %d 段语音密度低于 %.1f 字符/秒，可能是静音或纯画面片段", len(low), threshold.MinCharsPerSec)
+	}
+	return doctorCheck{
+		ID:      "clip_speech_density",
+		Level:   level,
+		Message: msg,
+		Details: map[string]interface{}{
+			"avg_chars_per_sec": roundMillis(avg),
+			"min_chars_per_sec": roundMillis(minCPS),
+			"low_density_clips": low,
+			"threshold":         threshold.MinCharsPerSec,
+		},
+	}
+}
+
 func doctorClipSubtitleCoverage(c prepClip, cues []subtitleCue) float64 {
 	clipDur := c.DurationSec
 	if clipDur <= 0 && c.EndSec > c.StartSec {
@@ -689,17 +1013,26 @@ func doctorCutBoundary(t float64, cues []subtitleCue, isStart bool) bool {
 	return false
 }
 
-func doctorClipText(c prepClip, cues []subtitleCue) string {
-	parts := make([]string, 0, 8)
+// doctorClipCues returns the non-empty cues that overlap the clip, untrimmed.
+func doctorClipCues(c prepClip, cues []subtitleCue) []subtitleCue {
+	out := make([]subtitleCue, 0, 8)
 	for _, cue := range cues {
 		if doctorIntersectionLen(c.StartSec, c.EndSec, cue.StartSec, cue.EndSec) <= 0 {
 			continue
 		}
-		t := strings.TrimSpace(cue.Text)
-		if t != "" {
-			parts = append(parts, t)
+		if strings.TrimSpace(cue.Text) != "" {
+			out = append(out, cue)
 		}
 	}
+	return out
+}
+
+func doctorClipText(c prepClip, cues []subtitleCue) string {
+	clipCues := doctorClipCues(c, cues)
+	parts := make([]string, 0, len(clipCues))
+	for _, cue := range clipCues {
+		parts = append(parts, strings.TrimSpace(cue.Text))
+	}
 	return strings.Join(parts, " ")
 }
 
@@ -798,8 +1131,14 @@ func summarizeDoctorChecks(checks []doctorCheck) doctorSummary {
 			s.Warn++
 		case "fail":
 			s.Fail++
+		case "skip":
+			s.Skip++
 		}
 	}
+	s.Score = 100 - s.Fail*doctorScoreFailPenalty - s.Warn*doctorScoreWarnPenalty
+	if s.Score < 0 {
+		s.Score = 0
+	}
 	return s
 }
 
@@ -817,7 +1156,7 @@ func doctorExitWithErr(asJSON bool, exitCode int, msg string) int {
 }
 
 func printDoctorJSON(v doctorJSONResult) {
-	data, err := json.Marshal(v)
+	data, err := marshalJSONResult(v)
 	if err != nil {
 		logError("json.marshal_failed", "context", "doctor_result", "error", err)
 		return
@@ -0,0 +1,204 @@
+// media-ingest (mingest) - Media Ingestion CLI tool
+// Copyright (C) 2026  Harrison Wang <https://mingest.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const doctorEnvVersionTimeout = 15 * time.Second
+
+type doctorEnvOptions struct {
+	JSON bool
+}
+
+type doctorEnvTool struct {
+	Name    string `json:"name"`
+	Path    string `json:"path,omitempty"`
+	Version string `json:"version,omitempty"`
+	Found   bool   `json:"found"`
+	OK      bool   `json:"ok"`
+	Error   string `json:"error,omitempty"`
+}
+
+type doctorEnvJSONResult struct {
+	OK            bool            `json:"ok"`
+	ExitCode      int             `json:"exit_code"`
+	Error         string          `json:"error,omitempty"`
+	Tools         []doctorEnvTool `json:"tools"`
+	FFmpegSameDir bool            `json:"ffmpeg_ffprobe_same_dir"`
+}
+
+func parseDoctorEnvOptions(args []string) (doctorEnvOptions, error) {
+	opts := doctorEnvOptions{}
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		switch {
+		case arg == "--json":
+			opts.JSON = true
+		default:
+			return doctorEnvOptions{}, fmt.Errorf("不支持的参数: %s", arg)
+		}
+	}
+	return opts, nil
+}
+
+// runDoctorEnv reports where each external tool resolves to and whether it
+// actually runs. Lookup follows detectDeps (working directory, executable
+// directory, then PATH), and detectDeps itself decides the overall verdict so
+// this command never disagrees with what get/prep will do.
+func runDoctorEnv(opts doctorEnvOptions) int {
+	result := doctorEnvJSONResult{OK: true, ExitCode: exitOK}
+
+	exeDir, _ := executableDir()
+	wd, _ := os.Getwd()
+
+	jsNames := []string{"deno", "node"}
+	if requested := strings.ToLower(strings.TrimSpace(os.Getenv("MINGEST_JS_RUNTIME"))); requested == "deno" || requested == "node" {
+		jsNames = []string{requested}
+	}
+	jsName := jsNames[0]
+	for _, name := range jsNames {
+		if _, ok := findBinary(name, wd, exeDir); ok {
+			jsName = name
+			break
+		}
+	}
+
+	probes := []struct {
+		name string
+		args []string
+	}{
+		{"yt-dlp", []string{"--version"}},
+		{"ffmpeg", []string{"-version"}},
+		{"ffprobe", []string{"-version"}},
+		{jsName, []string{"--version"}},
+	}
+	paths := map[string]string{}
+	for _, p := range probes {
+		t := doctorEnvTool{Name: p.name}
+		if path, ok := findBinary(p.name, wd, exeDir); ok {
+			t.Found = true
+			t.Path = path
+			paths[p.name] = path
+			v, err := probeToolVersion(path, p.args...)
+			if err != nil {
+				t.Error = err.Error()
+			} else {
+				t.OK = true
+				t.Version = v
+			}
+		} else {
+			t.Error = "未找到"
+		}
+		if !t.OK {
+			logWarn("doctor_env.tool_failed", "tool", t.Name, "path", t.Path, "error", t.Error)
+		}
+		result.Tools = append(result.Tools, t)
+	}
+	if paths["ffmpeg"] != "" && paths["ffprobe"] != "" {
+		result.FFmpegSameDir = filepath.Dir(paths["ffmpeg"]) == filepath.Dir(paths["ffprobe"])
+	}
+
+	if _, err := detectDeps(); err != nil {
+		result.OK = false
+		result.ExitCode = exitRuntimeMissing
+		if depErr, ok := err.(dependencyError); ok {
+			result.ExitCode = depErr.ExitCode
+		}
+		result.Error = err.Error()
+	} else {
+		for _, t := range result.Tools {
+			if !t.OK {
+				result.OK = false
+				result.ExitCode = exitDoctorFailed
+				result.Error = fmt.Sprintf("%s 无法运行: %s", t.Name, t.Error)
+				break
+			}
+		}
+	}
+	if result.OK {
+		logInfo("doctor_env.ok")
+	} else {
+		logError("doctor_env.failed", "exit_code", result.ExitCode, "error", result.Error)
+	}
+
+	if opts.JSON {
+		printDoctorEnvJSON(result)
+		return result.ExitCode
+	}
+
+	for _, t := range result.Tools {
+		switch {
+		case t.OK:
+			fmt.Printf("%s: %s (%s)\n", t.Name, t.Version, t.Path)
+		case t.Found:
+			fmt.Printf("%s: error: %s (%s)\n", t.Name, t.Error, t.Path)
+		default:
+			fmt.Printf("%s: %s\n", t.Name, t.Error)
+		}
+	}
+	fmt.Printf("ffmpeg_ffprobe_same_dir: %t\n", result.FFmpegSameDir)
+	if result.Error != "" {
+		fmt.Printf("error: %s\n", result.Error)
+	}
+	fmt.Printf("ok: %t\n", result.OK)
+	return result.ExitCode
+}
+
+// probeToolVersion runs a tool's version flag and returns the first non-empty
+// output line, e.g. "ffmpeg version 7.1 Copyright ...".
+func probeToolVersion(path string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), doctorEnvVersionTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, args...).CombinedOutput()
+	if ctx.Err() != nil {
+		return "", fmt.Errorf("超时（%s）", doctorEnvVersionTimeout)
+	}
+	first := ""
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			first = line
+			break
+		}
+	}
+	if err != nil {
+		if first != "" {
+			return "", fmt.Errorf("%v: %s", err, first)
+		}
+		return "", err
+	}
+	if first == "" {
+		return "", fmt.Errorf("无版本输出")
+	}
+	return first, nil
+}
+
+func printDoctorEnvJSON(v doctorEnvJSONResult) {
+	data, err := marshalJSONResult(v)
+	if err != nil {
+		logError("json.marshal_failed", "context", "doctor_env_result", "error", err)
+		return
+	}
+	fmt.Println(string(data))
+}
@@ -17,10 +17,13 @@
 package ingest
 
 import (
+	"archive/tar"
 	"archive/zip"
 	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -31,14 +34,26 @@ import (
 )
 
 type exportOptions struct {
-	AssetRef string
-	To       string
-	With     []string
-	OutDir   string
-	Zip      bool
-	JSON     bool
+	AssetRef  string
+	To        string
+	With      []string
+	OutDir    string
+	BundleDir string
+	Bundle    string
+	Zip       bool
+	// ArchiveFormat is zip or tgz; setting it implies an archive even without --zip.
+	ArchiveFormat string
+	// TimelineLayout places EDL/FCPXML clips back to back (sequential) or at
+	// their source times (source).
+	TimelineLayout string
+	JSON           bool
 }
 
+const (
+	exportTimelineSequential = "sequential"
+	exportTimelineSource     = "source"
+)
+
 type exportJSONResult struct {
 	OK          bool              `json:"ok"`
 	ExitCode    int               `json:"exit_code"`
@@ -47,15 +62,89 @@ type exportJSONResult struct {
 	AssetPath   string            `json:"asset_path,omitempty"`
 	To          string            `json:"to,omitempty"`
 	With        []string          `json:"with,omitempty"`
+	PrepBundle  string            `json:"prep_bundle,omitempty"`
 	PrepPlan    string            `json:"prep_plan,omitempty"`
 	OutDir      string            `json:"out_dir,omitempty"`
 	Exported    map[string]string `json:"exported,omitempty"`
 	ZipPath     string            `json:"zip_path,omitempty"`
+	ArchivePath string            `json:"archive_path,omitempty"`
 	SubtitleSrc string            `json:"subtitle_source,omitempty"`
+	Warnings    []string          `json:"warnings,omitempty"`
+}
+
+// exportManifest is written as manifest.json into every export directory so a
+// shared bundle (or its zip) describes itself. File paths are relative to the
+// manifest and use forward slashes.
+type exportManifest struct {
+	Version     string            `json:"version"`
+	CreatedAt   string            `json:"created_at"`
+	Tool        string            `json:"tool"`
+	AssetID     string            `json:"asset_id"`
+	Title       string            `json:"title,omitempty"`
+	SourceURL   string            `json:"source_url,omitempty"`
+	Platform    string            `json:"platform,omitempty"`
+	Target      string            `json:"target"`
+	Formats     []string          `json:"formats"`
+	FPS         float64           `json:"fps,omitempty"`
+	DurationSec float64           `json:"duration_sec,omitempty"`
+	ClipCount   int               `json:"clip_count"`
+	Layout      string            `json:"timeline_layout"`
+	Files       map[string]string `json:"files"`
+}
+
+// exportTimelineSchema versions the `--with json` interchange. Unlike
+// prep-plan.json it is a public contract: fields may be added within a
+// version but are never renamed or removed.
+const exportTimelineSchema = "mingest-timeline-v1"
+
+type exportTimeline struct {
+	Schema    string                  `json:"schema"`
+	CreatedAt string                  `json:"created_at"`
+	Tool      string                  `json:"tool"`
+	Asset     exportTimelineAsset     `json:"asset"`
+	Media     exportTimelineMedia     `json:"media"`
+	Subtitle  *exportTimelineSubtitle `json:"subtitle,omitempty"`
+	Clips     []exportTimelineClip    `json:"clips"`
+}
+
+type exportTimelineAsset struct {
+	AssetID   string `json:"asset_id"`
+	Title     string `json:"title,omitempty"`
+	SourceURL string `json:"source_url,omitempty"`
+	Platform  string `json:"platform,omitempty"`
+	Path      string `json:"path"`
+}
+
+type exportTimelineMedia struct {
+	DurationSec float64 `json:"duration_sec"`
+	FPS         float64 `json:"fps,omitempty"`
+	Width       int     `json:"width,omitempty"`
+	Height      int     `json:"height,omitempty"`
+	VideoCodec  string  `json:"video_codec,omitempty"`
+	AudioTracks int     `json:"audio_tracks"`
+	AudioOnly   bool    `json:"audio_only"`
+}
+
+// exportTimelineSubtitle.Path is relative to the timeline file when the SRT
+// is exported alongside it, otherwise the absolute path in the prep bundle.
+type exportTimelineSubtitle struct {
+	Path     string `json:"path"`
+	Source   string `json:"source,omitempty"`
+	Language string `json:"language,omitempty"`
+}
+
+type exportTimelineClip struct {
+	Index       int     `json:"index"`
+	Label       string  `json:"label"`
+	StartSec    float64 `json:"start_sec"`
+	EndSec      float64 `json:"end_sec"`
+	DurationSec float64 `json:"duration_sec"`
+	Reason      string  `json:"reason,omitempty"`
+	Rank        int     `json:"rank,omitempty"`
 }
 
 func parseExportOptions(args []string) (exportOptions, error) {
-	opts := exportOptions{}
+	opts := exportOptions{TimelineLayout: exportTimelineSequential}
 
 	withProvided := false
 
@@ -66,6 +155,22 @@ func parseExportOptions(args []string) (exportOptions, error) {
 			opts.JSON = true
 		case arg == "--zip":
 			opts.Zip = true
+		case arg == "--archive-format":
+			if i+1 >= len(args) {
+				return exportOptions{}, fmt.Errorf("`--archive-format` 缺少参数")
+			}
+			i++
+			opts.ArchiveFormat = strings.ToLower(strings.TrimSpace(args[i]))
+		case strings.HasPrefix(arg, "--archive-format="):
+			opts.ArchiveFormat = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--archive-format=")))
+		case arg == "--timeline-layout":
+			if i+1 >= len(args) {
+				return exportOptions{}, fmt.Errorf("`--timeline-layout` 缺少参数")
+			}
+			i++
+			opts.TimelineLayout = strings.ToLower(strings.TrimSpace(args[i]))
+		case strings.HasPrefix(arg, "--timeline-layout="):
+			opts.TimelineLayout = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--timeline-layout=")))
 		case arg == "--to":
 			if i+1 >= len(args) {
 				return exportOptions{}, fmt.Errorf("`--to` 缺少参数")
@@ -82,6 +187,22 @@ func parseExportOptions(args []string) (exportOptions, error) {
 			opts.OutDir = strings.TrimSpace(args[i])
 		case strings.HasPrefix(arg, "--out-dir="):
 			opts.OutDir = strings.TrimSpace(strings.TrimPrefix(arg, "--out-dir="))
+		case arg == "--bundle-dir":
+			if i+1 >= len(args) {
+				return exportOptions{}, fmt.Errorf("`--bundle-dir` 缺少参数")
+			}
+			i++
+			opts.BundleDir = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--bundle-dir="):
+			opts.BundleDir = strings.TrimSpace(strings.TrimPrefix(arg, "--bundle-dir="))
+		case arg == "--bundle":
+			if i+1 >= len(args) {
+				return exportOptions{}, fmt.Errorf("`--bundle` 缺少参数")
+			}
+			i++
+			opts.Bundle = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--bundle="):
+			opts.Bundle = strings.TrimSpace(strings.TrimPrefix(arg, "--bundle="))
 		case arg == "--with":
 			if i+1 >= len(args) {
 				return exportOptions{}, fmt.Errorf("`--with` 缺少参数")
@@ -111,7 +232,25 @@ func parseExportOptions(args []string) (exportOptions, error) {
 	}
 
 	if strings.TrimSpace(opts.AssetRef) == "" {
-		return exportOptions{}, fmt.Errorf("缺少 asset_ref。用法: mingest export <asset_ref> --to <premiere|resolve|capcut>")
+		return exportOptions{}, fmt.Errorf("缺少 asset_ref。用法: mingest export <asset_ref> --to <premiere|resolve|capcut|youtube-chapters|audacity>")
+	}
+	switch opts.ArchiveFormat {
+	case "":
+		if opts.Zip {
+			opts.ArchiveFormat = "zip"
+		}
+	case "zip", "tgz":
+		opts.Zip = true
+	case "tar.gz":
+		opts.ArchiveFormat = "tgz"
+		opts.Zip = true
+	default:
+		return exportOptions{}, fmt.Errorf("`--archive-format` 仅支持 zip|tgz")
+	}
+	switch opts.TimelineLayout {
+	case exportTimelineSequential, exportTimelineSource:
+	default:
+		return exportOptions{}, fmt.Errorf("`--timeline-layout` 仅支持 sequential|source")
 	}
 	normalizedTarget, err := normalizeExportTarget(opts.To)
 	if err != nil {
@@ -147,9 +286,9 @@ func parseExportFormats(raw string) ([]string, error) {
 			continue
 		}
 		switch v {
-		case "srt", "edl", "csv", "fcpxml":
+		case "srt", "vtt", "edl", "csv", "fcpxml", "chapters", "labels", "json":
 		default:
-			return nil, fmt.Errorf("`--with` 仅支持 srt|edl|csv|fcpxml（收到: %s）", v)
+			return nil, fmt.Errorf("`--with` 仅支持 srt|vtt|edl|csv|fcpxml|chapters|labels|json（收到: %s）", v)
 		}
 		if _, ok := seen[v]; ok {
 			continue
@@ -162,12 +301,12 @@ func parseExportFormats(raw string) ([]string, error) {
 
 func normalizeExportTarget(raw string) (string, error) {
 	switch strings.ToLower(strings.TrimSpace(raw)) {
-	case "premiere", "resolve", "capcut":
+	case "premiere", "resolve", "capcut", "youtube-chapters", "audacity":
 		return strings.ToLower(strings.TrimSpace(raw)), nil
 	case "jianying", "剪映":
 		return "capcut", nil
 	default:
-		return "", fmt.Errorf("`--to` 仅支持 premiere|resolve|capcut（jianying 也可作为 capcut 别名）")
+		return "", fmt.Errorf("`--to` 仅支持 premiere|resolve|capcut|youtube-chapters|audacity（jianying 也可作为 capcut 别名）")
 	}
 }
 
@@ -175,22 +314,37 @@ func defaultExportFormatsForTarget(target string) []string {
 	switch target {
 	case "capcut":
 		return []string{"srt", "csv"}
+	case "youtube-chapters":
+		return []string{"chapters"}
+	case "audacity":
+		return []string{"labels"}
 	default:
 		return []string{"fcpxml", "srt"}
 	}
 }
 
 func validateExportFormatsForTarget(target string, formats []string) error {
-	allowed := map[string]struct{}{}
+	// The neutral JSON timeline is tool-agnostic, so every target may add it.
+	allowed := map[string]struct{}{"json": {}}
 	switch target {
 	case "capcut":
 		allowed["srt"] = struct{}{}
 		allowed["csv"] = struct{}{}
+	case "youtube-chapters":
+		allowed["chapters"] = struct{}{}
+	case "audacity":
+		// Recent Audacity versions also import SRT/WebVTT as label tracks.
+		allowed["labels"] = struct{}{}
+		allowed["srt"] = struct{}{}
+		allowed["vtt"] = struct{}{}
 	default:
+		// Premiere and Resolve import WebVTT captions; CapCut only takes SRT.
 		allowed["srt"] = struct{}{}
+		allowed["vtt"] = struct{}{}
 		allowed["csv"] = struct{}{}
 		allowed["edl"] = struct{}{}
 		allowed["fcpxml"] = struct{}{}
+		allowed["chapters"] = struct{}{}
 	}
 
 	for _, f := range formats {
@@ -201,72 +355,119 @@ func validateExportFormatsForTarget(target string, formats []string) error {
 	return nil
 }
 
-func runExport(opts exportOptions) int {
+func executeExport(opts exportOptions) exportJSONResult {
+	fail := func(code int, msg string) exportJSONResult {
+		return exportJSONResult{OK: false, ExitCode: code, Error: msg}
+	}
+
 	asset, err := resolvePrepAsset(opts.AssetRef)
 	if err != nil {
-		return exportExitWithErr(opts.JSON, exitDownloadFailed, err.Error())
+		return fail(exitDownloadFailed, err.Error())
 	}
 	if strings.TrimSpace(asset.AssetID) == "" {
 		assetID, err := computeAssetID(asset.OutputPath)
 		if err != nil {
-			return exportExitWithErr(opts.JSON, exitDownloadFailed, fmt.Sprintf("生成 asset_id 失败: %v", err))
+			return fail(exitDownloadFailed, fmt.Sprintf("生成 asset_id 失败: %v", err))
 		}
 		asset.AssetID = assetID
 	}
 
-	prepDir, prepPlanPath, err := latestPrepBundle(asset)
+	prepDir, prepPlanPath, err := resolvePrepBundle(asset, opts.BundleDir, opts.Bundle)
 	if err != nil {
-		return exportExitWithErr(opts.JSON, exitDownloadFailed, err.Error())
+		return fail(exitDownloadFailed, err.Error())
 	}
 
 	plan, err := readPrepPlan(prepPlanPath)
 	if err != nil {
-		return exportExitWithErr(opts.JSON, exitDownloadFailed, fmt.Sprintf("读取 prep-plan.json 失败: %v", err))
+		return fail(exitDownloadFailed, fmt.Sprintf("读取 prep-plan.json 失败: %v", err))
+	}
+	if err := validatePrepPlan(plan); err != nil {
+		return fail(exitDownloadFailed, err.Error())
+	}
+	plan, cleanupEmbedded, err := extractPrepEmbedded(plan)
+	if err != nil {
+		return fail(exitDownloadFailed, fmt.Sprintf("读取内嵌 prep 内容失败: %v", err))
 	}
+	defer cleanupEmbedded()
 
 	outDir := strings.TrimSpace(opts.OutDir)
 	if outDir == "" {
-		outDir = filepath.Join(filepath.Dir(asset.OutputPath), ".mingest", "export", asset.AssetID, time.Now().UTC().Format("20060102T150405Z"))
+		outDir = filepath.Join(mingestArtifactRoot(asset.OutputPath, asset.AssetID, opts.BundleDir, "export"), time.Now().UTC().Format("20060102T150405Z"))
 	}
 	if err := os.MkdirAll(outDir, 0o755); err != nil {
-		return exportExitWithErr(opts.JSON, exitDownloadFailed, fmt.Sprintf("创建导出目录失败: %v", err))
+		return fail(exitDownloadFailed, fmt.Sprintf("创建导出目录失败: %v", err))
 	}
 
 	exported := make(map[string]string, len(opts.With))
+	var warnings []string
 	for _, f := range opts.With {
 		switch f {
 		case "srt":
 			target := filepath.Join(outDir, asset.AssetID+".srt")
 			src, err := pickSubtitleSource(plan)
 			if err != nil {
-				return exportExitWithErr(opts.JSON, exitDownloadFailed, err.Error())
+				return fail(exitDownloadFailed, err.Error())
 			}
 			if err := copyFileAtomic(src, target); err != nil {
-				return exportExitWithErr(opts.JSON, exitDownloadFailed, fmt.Sprintf("导出 srt 失败: %v", err))
+				return fail(exitDownloadFailed, fmt.Sprintf("导出 srt 失败: %v", err))
 			}
 			exported["srt"] = target
+		case "vtt":
+			target := filepath.Join(outDir, asset.AssetID+".vtt")
+			src, err := pickSubtitleSource(plan)
+			if err != nil {
+				return fail(exitDownloadFailed, err.Error())
+			}
+			shorts := plan.Options.Goal == "shorts" || plan.Options.SubtitleStyle == "shorts"
+			if err := writeExportVTT(target, src, shorts); err != nil {
+				return fail(exitDownloadFailed, fmt.Sprintf("导出 vtt 失败: %v", err))
+			}
+			exported["vtt"] = target
 		case "csv":
 			target := filepath.Join(outDir, asset.AssetID+"-markers.csv")
 			if src := strings.TrimSpace(plan.Outputs.MarkersCSV); src != "" && fileExists(src) {
 				if err := copyFileAtomic(src, target); err != nil {
-					return exportExitWithErr(opts.JSON, exitDownloadFailed, fmt.Sprintf("导出 csv 失败: %v", err))
+					return fail(exitDownloadFailed, fmt.Sprintf("导出 csv 失败: %v", err))
 				}
 			} else if err := writePrepMarkers(target, plan.Clips); err != nil {
-				return exportExitWithErr(opts.JSON, exitDownloadFailed, fmt.Sprintf("导出 csv 失败: %v", err))
+				return fail(exitDownloadFailed, fmt.Sprintf("导出 csv 失败: %v", err))
 			}
 			exported["csv"] = target
 		case "edl":
 			target := filepath.Join(outDir, asset.AssetID+".edl")
-			if err := writeExportEDL(target, asset.AssetID, plan.Clips, plan.Probe.FPS); err != nil {
-				return exportExitWithErr(opts.JSON, exitDownloadFailed, fmt.Sprintf("导出 edl 失败: %v", err))
+			if err := writeExportEDL(target, asset.AssetID, plan.Clips, plan.Probe.FPS, plan.Probe.AudioOnly, opts.TimelineLayout); err != nil {
+				return fail(exitDownloadFailed, fmt.Sprintf("导出 edl 失败: %v", err))
 			}
 			exported["edl"] = target
 		case "fcpxml":
 			target := filepath.Join(outDir, asset.AssetID+".fcpxml")
-			if err := writeExportFCPXML(target, asset, plan, opts.To); err != nil {
-				return exportExitWithErr(opts.JSON, exitDownloadFailed, fmt.Sprintf("导出 fcpxml 失败: %v", err))
+			if err := writeExportFCPXML(target, asset, plan, opts.To, opts.TimelineLayout); err != nil {
+				return fail(exitDownloadFailed, fmt.Sprintf("导出 fcpxml 失败: %v", err))
 			}
 			exported["fcpxml"] = target
+		case "chapters":
+			target := filepath.Join(outDir, asset.AssetID+"-chapters.txt")
+			chapterWarnings, err := writeExportYouTubeChapters(target, plan.Clips, plan.Probe.DurationSec)
+			if err != nil {
+				return fail(exitDownloadFailed, fmt.Sprintf("导出 chapters 失败: %v", err))
+			}
+			for _, w := range chapterWarnings {
+				logWarn("export.chapters_rule_violated", "asset_id", asset.AssetID, "detail", w)
+			}
+			warnings = append(warnings, chapterWarnings...)
+			exported["chapters"] = target
+		case "json":
+			target := filepath.Join(outDir, asset.AssetID+"-timeline.json")
+			if err := writeExportTimelineJSON(target, asset, plan, opts.With); err != nil {
+				return fail(exitDownloadFailed, fmt.Sprintf("导出 json 失败: %v", err))
+			}
+			exported["json"] = target
+		case "labels":
+			target := filepath.Join(outDir, asset.AssetID+"-labels.txt")
+			if err := writeExportAudacityLabels(target, plan.Clips); err != nil {
+				return fail(exitDownloadFailed, fmt.Sprintf("导出 labels 失败: %v", err))
+			}
+			exported["labels"] = target
 		}
 	}
 
@@ -277,55 +478,115 @@ func runExport(opts exportOptions) int {
 		}
 	}
 
+	manifestPath := filepath.Join(outDir, "manifest.json")
+	if err := writeExportManifest(manifestPath, asset, plan, opts, exported); err != nil {
+		return fail(exitDownloadFailed, fmt.Sprintf("写入 manifest.json 失败: %v", err))
+	}
+	exported["manifest"] = manifestPath
+
 	zipPath := ""
-	if opts.Zip {
+	archivePath := ""
+	switch {
+	case opts.Zip && opts.ArchiveFormat == "tgz":
+		archivePath = outDir + ".tar.gz"
+		if err := tarGzDir(outDir, archivePath); err != nil {
+			return fail(exitDownloadFailed, fmt.Sprintf("打包 tar.gz 失败: %v", err))
+		}
+	case opts.Zip:
 		zipPath = outDir + ".zip"
 		if err := zipDir(outDir, zipPath); err != nil {
-			return exportExitWithErr(opts.JSON, exitDownloadFailed, fmt.Sprintf("打包 zip 失败: %v", err))
+			return fail(exitDownloadFailed, fmt.Sprintf("打包 zip 失败: %v", err))
 		}
+		archivePath = zipPath
 	}
 
+	result := exportJSONResult{
+		OK:          true,
+		ExitCode:    exitOK,
+		AssetID:     asset.AssetID,
+		AssetPath:   asset.OutputPath,
+		To:          opts.To,
+		With:        opts.With,
+		PrepBundle:  prepDir,
+		PrepPlan:    prepPlanPath,
+		OutDir:      outDir,
+		Exported:    exported,
+		ZipPath:     zipPath,
+		ArchivePath: archivePath,
+		Warnings:    warnings,
+	}
+	if plan.Subtitle != nil {
+		result.SubtitleSrc = strings.TrimSpace(plan.Subtitle.SelectedSource)
+	}
+	return result
+}
+
+func runExport(opts exportOptions) int {
+	result := executeExport(opts)
+	if !result.OK {
+		return exportExitWithErr(opts.JSON, result.ExitCode, result.Error)
+	}
 	if opts.JSON {
-		result := exportJSONResult{
-			OK:        true,
-			ExitCode:  exitOK,
-			AssetID:   asset.AssetID,
-			AssetPath: asset.OutputPath,
-			To:        opts.To,
-			With:      opts.With,
-			PrepPlan:  prepPlanPath,
-			OutDir:    outDir,
-			Exported:  exported,
-			ZipPath:   zipPath,
-		}
-		if plan.Subtitle != nil {
-			result.SubtitleSrc = strings.TrimSpace(plan.Subtitle.SelectedSource)
-		}
 		printExportJSON(result)
 		return exitOK
 	}
 
-	fmt.Printf("asset_id: %s\n", asset.AssetID)
-	fmt.Printf("asset_path: %s\n", asset.OutputPath)
-	fmt.Printf("to: %s\n", opts.To)
-	fmt.Printf("prep_bundle: %s\n", prepDir)
-	fmt.Printf("prep_plan: %s\n", prepPlanPath)
-	fmt.Printf("out_dir: %s\n", outDir)
-	keys := make([]string, 0, len(exported))
-	for k := range exported {
+	fmt.Printf("asset_id: %s\n", result.AssetID)
+	fmt.Printf("asset_path: %s\n", result.AssetPath)
+	fmt.Printf("to: %s\n", result.To)
+	fmt.Printf("prep_bundle: %s\n", result.PrepBundle)
+	fmt.Printf("prep_plan: %s\n", result.PrepPlan)
+	fmt.Printf("out_dir: %s\n", result.OutDir)
+	keys := make([]string, 0, len(result.Exported))
+	for k := range result.Exported {
 		keys = append(keys, k)
 	}
 	sort.Strings(keys)
 	for _, k := range keys {
-		fmt.Printf("%s: %s\n", k, exported[k])
+		fmt.Printf("%s: %s\n", k, result.Exported[k])
+	}
+	if result.ZipPath != "" {
+		fmt.Printf("zip: %s\n", result.ZipPath)
+	} else if result.ArchivePath != "" {
+		fmt.Printf("archive: %s\n", result.ArchivePath)
 	}
-	if zipPath != "" {
-		fmt.Printf("zip: %s\n", zipPath)
+	for _, w := range result.Warnings {
+		fmt.Printf("warning: %s\n", w)
 	}
 	return exitOK
 }
 
-func latestPrepBundle(asset prepResolvedAsset) (dir string, prepPlanPath string, err error) {
+// resolvePrepBundle returns the prep bundle selected by bundle (a timestamp dir
+// name or a bundle path). Empty or unknown selections fall back to the latest.
+func resolvePrepBundle(asset prepResolvedAsset, bundleDir, bundle string) (dir string, prepPlanPath string, err error) {
+	bundle = strings.TrimSpace(bundle)
+	if bundle == "" {
+		return latestPrepBundle(asset, bundleDir)
+	}
+
+	candidates := []string{bundle}
+	if filepath.Base(bundle) == "prep-plan.json" {
+		candidates = []string{filepath.Dir(bundle)}
+	}
+	if !filepath.IsAbs(bundle) && !strings.ContainsAny(bundle, `/\`) {
+		for _, root := range prepBundleRoots(asset, bundleDir) {
+			candidates = append(candidates, filepath.Join(root, bundle))
+		}
+	}
+	for _, c := range candidates {
+		planPath := filepath.Join(c, "prep-plan.json")
+		if fileExists(planPath) {
+			logDebug("prep.bundle_selected", "asset_id", asset.AssetID, "bundle", c)
+			return c, planPath, nil
+		}
+	}
+
+	logWarn("prep.bundle_not_found", "asset_id", asset.AssetID, "bundle", bundle, "fallback", "latest")
+	return latestPrepBundle(asset, bundleDir)
+}
+
+// prepBundleRoots lists candidate prep/<asset_id> directories in priority order.
+func prepBundleRoots(asset prepResolvedAsset, bundleDir string) []string {
 	roots := make([]string, 0, 4)
 	seen := map[string]struct{}{}
 	addRoot := func(path string) {
@@ -340,6 +601,12 @@ func latestPrepBundle(asset prepResolvedAsset) (dir string, prepPlanPath string,
 		roots = append(roots, p)
 	}
 
+	// --bundle-dir first, then the central library, then MINGEST_BUNDLE_ROOT and the media directory.
+	addRoot(mingestArtifactRoot(asset.OutputPath, asset.AssetID, bundleDir, "prep"))
+	if lib := mingestLibraryDir(); lib != "" {
+		addRoot(filepath.Join(lib, asset.AssetID, "prep"))
+	}
+	addRoot(filepath.Join(mingestBundleRoot(asset.OutputPath, ""), "prep", asset.AssetID))
 	addRoot(filepath.Join(filepath.Dir(asset.OutputPath), ".mingest", "prep", asset.AssetID))
 
 	records, _ := readAssetRecords()
@@ -352,7 +619,11 @@ func latestPrepBundle(asset prepResolvedAsset) (dir string, prepPlanPath string,
 		}
 		addRoot(filepath.Join(filepath.Dir(strings.TrimSpace(r.OutputPath)), ".mingest", "prep", asset.AssetID))
 	}
+	return roots
+}
 
+func latestPrepBundle(asset prepResolvedAsset, bundleDir string) (dir string, prepPlanPath string, err error) {
+	roots := prepBundleRoots(asset, bundleDir)
 	for _, root := range roots {
 		entries, err := os.ReadDir(root)
 		if err != nil {
@@ -397,6 +668,50 @@ func readPrepPlan(path string) (prepPlan, error) {
 	return plan, nil
 }
 
+// validatePrepPlan rejects plans that cannot be used safely, typically after a
+// hand edit of prep-plan.json. Problems are collected so the user can fix them
+// in one pass.
+func validatePrepPlan(plan prepPlan) error {
+	var problems []string
+	switch v := strings.TrimSpace(plan.Version); v {
+	case "prep-v1":
+	case "":
+		problems = append(problems, "缺少 version")
+	default:
+		problems = append(problems, fmt.Sprintf("不支持的 version %q（需为 prep-v1）", v))
+	}
+	if strings.TrimSpace(plan.Asset.OutputPath) == "" {
+		problems = append(problems, "asset.output_path 为空")
+	}
+	if strings.TrimSpace(plan.Outputs.BundleDir) == "" {
+		problems = append(problems, "outputs.bundle_dir 为空")
+	}
+	if strings.TrimSpace(plan.Outputs.PlanPath) == "" {
+		problems = append(problems, "outputs.plan_path 为空")
+	}
+	if strings.TrimSpace(plan.Outputs.MarkersCSV) == "" {
+		problems = append(problems, "outputs.markers_csv 为空")
+	}
+	for i, c := range plan.Clips {
+		label := fmt.Sprintf("clips[%d]", i)
+		switch {
+		case math.IsNaN(c.StartSec) || math.IsInf(c.StartSec, 0) || math.IsNaN(c.EndSec) || math.IsInf(c.EndSec, 0):
+			problems = append(problems, label+" 时间不是有效数字")
+		case c.StartSec < 0:
+			problems = append(problems, fmt.Sprintf("%s start_sec 为负数（%.3f）", label, c.StartSec))
+		case c.EndSec <= c.StartSec:
+			problems = append(problems, fmt.Sprintf("%s end_sec（%.3f）必须大于 start_sec（%.3f）", label, c.EndSec, c.StartSec))
+		}
+		if c.DurationSec < 0 {
+			problems = append(problems, fmt.Sprintf("%s duration_sec 为负数（%.3f）", label, c.DurationSec))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("prep-plan.json 校验失败（请修正后重试，或重新执行 `mingest prep`）: %s", strings.Join(problems, "; "))
+}
+
 func pickSubtitleSource(plan prepPlan) (string, error) {
 	src := strings.TrimSpace(plan.Outputs.SubtitlePath)
 	if src != "" && fileExists(src) {
@@ -410,7 +725,7 @@ func pickSubtitleSource(plan prepPlan) (string, error) {
 	return "", fmt.Errorf("prep 结果中没有可导出的字幕文件（subtitle_path/subtitle_template 均不存在）")
 }
 
-func writeExportFCPXML(path string, asset prepResolvedAsset, plan prepPlan, target string) error {
+func writeExportFCPXML(path string, asset prepResolvedAsset, plan prepPlan, target, layout string) error {
 	width := plan.Probe.Width
 	height := plan.Probe.Height
 	if width <= 0 {
@@ -420,6 +735,22 @@ func writeExportFCPXML(path string, asset prepResolvedAsset, plan prepPlan, targ
 		height = 1080
 	}
 
+	hasVideo := 1
+	if plan.Probe.AudioOnly {
+		hasVideo = 0
+	}
+
+	// An intended aspect (prep/semantic --aspect) gets its own sequence
+	// format; clips fill it, i.e. a center crop the editor can reposition.
+	seqFormat := "r_format"
+	seqWidth, seqHeight, reframe := aspectFrameSize(plan.Options.Aspect)
+	if reframe && (plan.Probe.AudioOnly || seqWidth*height == seqHeight*width) {
+		reframe = false
+	}
+	if reframe {
+		seqFormat = "r_seq_format"
+	}
+
 	fps := plan.Probe.FPS
 	frameDuration := fcpxmlFrameDuration(fps)
 	assetDuration := plan.Probe.DurationSec
@@ -447,7 +778,11 @@ func writeExportFCPXML(path string, asset prepResolvedAsset, plan prepPlan, targ
 	projectLabel := fmt.Sprintf("mingest_%s_%s", target, asset.AssetID)
 	assetName := filepath.Base(asset.OutputPath)
 	srcURL := fileURLFromPath(asset.OutputPath)
-	seqDuration := sumClipDuration(clips)
+	clips, offsets := layoutExportClips(clips, layout)
+	seqDuration := 0.0
+	if n := len(clips); n > 0 {
+		seqDuration = offsets[n-1] + exportClipDuration(clips[n-1])
+	}
 	if seqDuration <= 0 {
 		seqDuration = assetDuration
 	}
@@ -457,45 +792,67 @@ func writeExportFCPXML(path string, asset prepResolvedAsset, plan prepPlan, targ
 	b.WriteString(`<!DOCTYPE fcpxml>` + "\n")
 	b.WriteString(`<fcpxml version="1.10">` + "\n")
 	b.WriteString(`  <resources>` + "\n")
-	b.WriteString(fmt.Sprintf(`    <format id="r_format" name="%s" frameDuration="%s" width="%d" height="%d" colorSpace="1-1-1 (Rec. 709)"/>`+"\n",
+	b.WriteString(fmt.Sprintf(`    <format id="r_format" name="%s" frameDuration="%s" width="%d" height="%d" colorSpace="%s"/>`+"\n",
 		xmlEscapeAttr(fmt.Sprintf("FFVideoFormat%dx%d", width, height)),
 		xmlEscapeAttr(frameDuration),
 		width,
 		height,
+		xmlEscapeAttr(fcpxmlColorSpace(plan.Probe)),
 	))
-	b.WriteString(fmt.Sprintf(`    <asset id="r_asset" name="%s" start="0s" duration="%s" hasVideo="1" hasAudio="1" format="r_format" src="%s"/>`+"\n",
+	if reframe {
+		b.WriteString(fmt.Sprintf(`    <format id="r_seq_format" frameDuration="%s" width="%d" height="%d" colorSpace="%s"/>`+"\n",
+			xmlEscapeAttr(frameDuration),
+			seqWidth,
+			seqHeight,
+			xmlEscapeAttr(fcpxmlColorSpace(plan.Probe)),
+		))
+	}
+	b.WriteString(fmt.Sprintf(`    <asset id="r_asset" name="%s" start="0s" duration="%s" hasVideo="%d" hasAudio="1" format="r_format" src="%s"/>`+"\n",
 		xmlEscapeAttr(assetName),
 		xmlEscapeAttr(fcpxmlSeconds(assetDuration)),
+		hasVideo,
 		xmlEscapeAttr(srcURL),
 	))
 	b.WriteString(`  </resources>` + "\n")
 	b.WriteString(`  <library>` + "\n")
 	b.WriteString(fmt.Sprintf(`    <event name="%s">`+"\n", xmlEscapeAttr("mingest")))
 	b.WriteString(fmt.Sprintf(`      <project name="%s">`+"\n", xmlEscapeAttr(projectLabel)))
-	b.WriteString(fmt.Sprintf(`        <sequence format="r_format" tcStart="0s" tcFormat="NDF" audioLayout="stereo" audioRate="48k" duration="%s">`+"\n", xmlEscapeAttr(fcpxmlSeconds(seqDuration))))
+	b.WriteString(fmt.Sprintf(`        <sequence format="%s" tcStart="0s" tcFormat="NDF" audioLayout="stereo" audioRate="48k" duration="%s">`+"\n", seqFormat, xmlEscapeAttr(fcpxmlSeconds(seqDuration))))
 	b.WriteString(`          <spine>` + "\n")
 
-	offset := 0.0
+	cursor := 0.0
 	for i, clip := range clips {
 		start := clip.StartSec
-		duration := clip.DurationSec
-		if duration <= 0 && clip.EndSec > clip.StartSec {
-			duration = clip.EndSec - clip.StartSec
-		}
+		duration := exportClipDuration(clip)
 		if duration <= 0 {
 			continue
 		}
+		offset := offsets[i]
+		if offset > cursor {
+			// The spine is a single storyline; empty time must be an explicit gap.
+			b.WriteString(fmt.Sprintf(`            <gap name="Gap" offset="%s" start="0s" duration="%s"/>`+"\n",
+				xmlEscapeAttr(fcpxmlSeconds(cursor)),
+				xmlEscapeAttr(fcpxmlSeconds(offset-cursor)),
+			))
+		}
 		label := strings.TrimSpace(clip.Label)
 		if label == "" {
 			label = fmt.Sprintf("clip-%02d", i+1)
 		}
-		b.WriteString(fmt.Sprintf(`            <asset-clip name="%s" ref="r_asset" offset="%s" start="%s" duration="%s"/>`+"\n",
+		clipOpen := fmt.Sprintf(`            <asset-clip name="%s" ref="r_asset" offset="%s" start="%s" duration="%s"`,
 			xmlEscapeAttr(label),
 			xmlEscapeAttr(fcpxmlSeconds(offset)),
 			xmlEscapeAttr(fcpxmlSeconds(start)),
 			xmlEscapeAttr(fcpxmlSeconds(duration)),
-		))
-		offset += duration
+		)
+		if reframe {
+			b.WriteString(clipOpen + ">\n")
+			b.WriteString(`              <adjust-conform type="fill"/>` + "\n")
+			b.WriteString(`            </asset-clip>` + "\n")
+		} else {
+			b.WriteString(clipOpen + "/>\n")
+		}
+		cursor = offset + duration
 	}
 
 	b.WriteString(`          </spine>` + "\n")
@@ -507,6 +864,28 @@ func writeExportFCPXML(path string, asset prepResolvedAsset, plan prepPlan, targ
 	return os.WriteFile(path, b.Bytes(), 0o644)
 }
 
+// fcpxmlColorSpace maps ffprobe color tags to FCPXML's colorSpace attribute
+// (H.273 primaries-transfer-matrix codes). Untagged media stays Rec. 709.
+func fcpxmlColorSpace(p mediaProbe) string {
+	switch p.ColorPrimaries {
+	case "bt2020":
+		switch p.ColorTransfer {
+		case "arib-std-b67":
+			return "9-18-9 (Rec. 2020 HLG)"
+		case "smpte2084":
+			return "9-16-9 (Rec. 2020 PQ)"
+		default:
+			return "9-1-9 (Rec. 2020)"
+		}
+	case "smpte170m":
+		return "6-1-6 (Rec. 601 NTSC)"
+	case "bt470bg":
+		return "5-1-6 (Rec. 601 PAL)"
+	default:
+		return "1-1-1 (Rec. 709)"
+	}
+}
+
 func writeCapCutGuide(path, assetID, srtPath, csvPath string) error {
 	var b bytes.Buffer
 	b.WriteString("# CapCut / 剪映 导入说明\n\n")
@@ -598,10 +977,273 @@ func xmlEscapeAttr(v string) string {
 	return replacer.Replace(v)
 }
 
-func writeExportEDL(path, assetID string, clips []prepClip, fps float64) error {
+func writeExportManifest(path string, asset prepResolvedAsset, plan prepPlan, opts exportOptions, exported map[string]string) error {
+	dir := filepath.Dir(path)
+	files := make(map[string]string, len(exported))
+	for k, p := range exported {
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			rel = filepath.Base(p)
+		}
+		files[k] = filepath.ToSlash(rel)
+	}
+	manifest := exportManifest{
+		Version:     "export-v1",
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+		Tool:        "mingest " + version,
+		AssetID:     asset.AssetID,
+		Title:       asset.Title,
+		SourceURL:   asset.URL,
+		Platform:    asset.Platform,
+		Target:      opts.To,
+		Formats:     opts.With,
+		FPS:         plan.Probe.FPS,
+		DurationSec: roundMillis(plan.Probe.DurationSec),
+		ClipCount:   len(plan.Clips),
+		Layout:      opts.TimelineLayout,
+		Files:       files,
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func writeExportTimelineJSON(path string, asset prepResolvedAsset, plan prepPlan, formats []string) error {
+	doc := exportTimeline{
+		Schema:    exportTimelineSchema,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		Tool:      "mingest " + version,
+		Asset: exportTimelineAsset{
+			AssetID:   asset.AssetID,
+			Title:     asset.Title,
+			SourceURL: asset.URL,
+			Platform:  asset.Platform,
+			Path:      asset.OutputPath,
+		},
+		Media: exportTimelineMedia{
+			DurationSec: roundMillis(plan.Probe.DurationSec),
+			FPS:         plan.Probe.FPS,
+			Width:       plan.Probe.Width,
+			Height:      plan.Probe.Height,
+			VideoCodec:  plan.Probe.VideoCodec,
+			AudioTracks: plan.Probe.AudioTracks,
+			AudioOnly:   plan.Probe.AudioOnly,
+		},
+		Clips: make([]exportTimelineClip, 0, len(plan.Clips)),
+	}
+	if src, err := pickSubtitleSource(plan); err == nil {
+		sub := &exportTimelineSubtitle{Path: src}
+		if contains(formats, "srt") {
+			sub.Path = asset.AssetID + ".srt"
+		}
+		if plan.Subtitle != nil {
+			sub.Source = strings.TrimSpace(plan.Subtitle.SelectedSource)
+			sub.Language = strings.TrimSpace(plan.Subtitle.SelectedLanguage)
+		}
+		doc.Subtitle = sub
+	}
+	for _, c := range plan.Clips {
+		doc.Clips = append(doc.Clips, exportTimelineClip{
+			Index:       c.Index,
+			Label:       c.Label,
+			StartSec:    c.StartSec,
+			EndSec:      c.EndSec,
+			DurationSec: c.DurationSec,
+			Reason:      c.Reason,
+			Rank:        c.Rank,
+		})
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// vttShortsCueSettings keeps captions in the lower third of a 9:16 frame,
+// clear of the platform UI at the very bottom.
+const vttShortsCueSettings = "line:72% position:50% align:center size:80%"
+
+// writeExportVTT converts an SRT subtitle into WebVTT. For shorts the file
+// carries a STYLE block and per-cue positioning; otherwise it is a plain
+// conversion so players apply their own defaults.
+func writeExportVTT(path, srcPath string, shorts bool) error {
+	cues, err := parseSubtitleCues(srcPath)
+	if err != nil {
+		return err
+	}
+	if len(cues) == 0 {
+		return fmt.Errorf("字幕文件无可用条目: %s", srcPath)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("WEBVTT\n\n")
+	if shorts {
+		b.WriteString("STYLE\n")
+		b.WriteString("::cue {\n")
+		b.WriteString("  color: #ffffff;\n")
+		b.WriteString("  background-color: rgba(0, 0, 0, 0.6);\n")
+		b.WriteString("  font-weight: bold;\n")
+		b.WriteString("  font-size: 110%;\n")
+		b.WriteString("}\n\n")
+	}
+	for i, c := range cues {
+		b.WriteString(fmt.Sprintf("%d\n", i+1))
+		b.WriteString(formatVTTTime(c.StartSec))
+		b.WriteString(" --> ")
+		b.WriteString(formatVTTTime(c.EndSec))
+		if shorts {
+			b.WriteString(" ")
+			b.WriteString(vttShortsCueSettings)
+		}
+		b.WriteString("\n")
+		// A blank line ends a cue in WebVTT, and "-->" may not appear in payload text.
+		for _, line := range strings.Split(c.Text, "\n") {
+			line = strings.TrimSpace(strings.ReplaceAll(line, "-->", "->"))
+			if line == "" {
+				continue
+			}
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+	return os.WriteFile(path, b.Bytes(), 0o644)
+}
+
+func formatVTTTime(sec float64) string {
+	return strings.Replace(formatSRTTime(sec), ",", ".", 1)
+}
+
+// YouTube only turns description timestamps into chapters when there are at
+// least youtubeMinChapters entries, the first at 00:00, each youtubeMinChapterSec long.
+const (
+	youtubeMinChapters   = 3
+	youtubeMinChapterSec = 10.0
+)
+
+// writeExportYouTubeChapters writes one "MM:SS Label" line per clip start in
+// source-time order, suitable for pasting into a video description. Rule
+// violations are returned as warnings; the file is still written.
+func writeExportYouTubeChapters(path string, clips []prepClip, durationSec float64) ([]string, error) {
+	sorted := append([]prepClip(nil), clips...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].StartSec < sorted[j].StartSec
+	})
+
+	var warnings []string
+	if len(sorted) < youtubeMinChapters {
+		warnings = append(warnings, fmt.Sprintf("章节数 %d 少于 YouTube 要求的 %d 个，描述中的时间戳不会生成章节", len(sorted), youtubeMinChapters))
+	}
+
+	useHours := durationSec >= 3600
+	if n := len(sorted); n > 0 && sorted[n-1].StartSec >= 3600 {
+		useHours = true
+	}
+
+	var b bytes.Buffer
+	for i, clip := range sorted {
+		start := clip.StartSec
+		if i == 0 && start > 0 {
+			// YouTube requires the first chapter at 00:00; stretch it back.
+			start = 0
+		}
+		end := durationSec
+		if i+1 < len(sorted) {
+			end = sorted[i+1].StartSec
+		}
+		if end > 0 && end-start < youtubeMinChapterSec {
+			warnings = append(warnings, fmt.Sprintf("章节 %d（%s）时长 %.1fs 不足 %.0fs", i+1, formatChapterTimestamp(start, useHours), end-start, youtubeMinChapterSec))
+		}
+
+		label := strings.Join(strings.Fields(clip.Label), " ")
+		if label == "" {
+			label = fmt.Sprintf("Clip %02d", i+1)
+		}
+		b.WriteString(formatChapterTimestamp(start, useHours))
+		b.WriteString(" ")
+		b.WriteString(label)
+		b.WriteString("\n")
+	}
+	return warnings, os.WriteFile(path, b.Bytes(), 0o644)
+}
+
+// writeExportAudacityLabels writes an Audacity label track: one
+// "start<TAB>end<TAB>label" line per clip, times in seconds.
+func writeExportAudacityLabels(path string, clips []prepClip) error {
+	var b bytes.Buffer
+	for i, clip := range clips {
+		label := strings.Join(strings.Fields(clip.Label), " ")
+		if label == "" {
+			label = fmt.Sprintf("Clip %02d", i+1)
+		}
+		b.WriteString(fmt.Sprintf("%.6f\t%.6f\t%s\n", clip.StartSec, clip.EndSec, label))
+	}
+	return os.WriteFile(path, b.Bytes(), 0o644)
+}
+
+func formatChapterTimestamp(sec float64, useHours bool) string {
+	if sec < 0 {
+		sec = 0
+	}
+	total := int(sec)
+	h := total / 3600
+	m := (total % 3600) / 60
+	s := total % 60
+	if useHours {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", m, s)
+}
+
+// exportClipDuration is the clip length, derived from start/end when the plan
+// left duration_sec empty.
+func exportClipDuration(clip prepClip) float64 {
+	if clip.DurationSec <= 0 && clip.EndSec > clip.StartSec {
+		return clip.EndSec - clip.StartSec
+	}
+	return clip.DurationSec
+}
+
+// layoutExportClips returns the clips in timeline order with each one's record
+// offset in seconds. sequential keeps plan order back to back (a rough cut);
+// source sorts by StartSec and places each clip at its source time, pushing a
+// clip that would overlap its predecessor to that clip's end.
+func layoutExportClips(clips []prepClip, layout string) ([]prepClip, []float64) {
+	if layout == exportTimelineSource {
+		clips = append([]prepClip(nil), clips...)
+		sort.SliceStable(clips, func(i, j int) bool {
+			return clips[i].StartSec < clips[j].StartSec
+		})
+	}
+	offsets := make([]float64, len(clips))
+	cursor := 0.0
+	for i, clip := range clips {
+		offset := cursor
+		if layout == exportTimelineSource && clip.StartSec > cursor {
+			offset = clip.StartSec
+		}
+		if layout == exportTimelineSource && clip.StartSec < cursor {
+			logWarn("export.timeline_overlap", "label", clip.Label, "start_sec", clip.StartSec, "placed_at", cursor)
+		}
+		offsets[i] = offset
+		if d := exportClipDuration(clip); d > 0 {
+			cursor = offset + d
+		}
+	}
+	return clips, offsets
+}
+
+func writeExportEDL(path, assetID string, clips []prepClip, fps float64, audioOnly bool, layout string) error {
 	if fps <= 0 {
 		fps = 30
 	}
+	track := "V"
+	if audioOnly {
+		track = "A"
+	}
 	if fps > 120 {
 		fps = 120
 	}
@@ -610,16 +1252,15 @@ func writeExportEDL(path, assetID string, clips []prepClip, fps float64) error {
 	b.WriteString(fmt.Sprintf("TITLE: mingest_%s\n", assetID))
 	b.WriteString("FCM: NON-DROP FRAME\n\n")
 
-	timelineSec := 0.0
+	clips, offsets := layoutExportClips(clips, layout)
 	for i, clip := range clips {
 		srcIn := secondsToTimecode(clip.StartSec, fps)
 		srcOut := secondsToTimecode(clip.EndSec, fps)
-		recIn := secondsToTimecode(timelineSec, fps)
-		timelineSec += clip.DurationSec
-		recOut := secondsToTimecode(timelineSec, fps)
+		recIn := secondsToTimecode(offsets[i], fps)
+		recOut := secondsToTimecode(offsets[i]+clip.DurationSec, fps)
 
 		eventNum := fmt.Sprintf("%03d", i+1)
-		b.WriteString(fmt.Sprintf("%s  AX       V     C        %s %s %s %s\n", eventNum, srcIn, srcOut, recIn, recOut))
+		b.WriteString(fmt.Sprintf("%s  AX       %s     C        %s %s %s %s\n", eventNum, track, srcIn, srcOut, recIn, recOut))
 		b.WriteString(fmt.Sprintf("* FROM CLIP NAME: %s\n", clip.Label))
 		if strings.TrimSpace(clip.Reason) != "" {
 			b.WriteString(fmt.Sprintf("* COMMENT: %s\n", clip.Reason))
@@ -650,6 +1291,64 @@ func secondsToTimecode(sec float64, fps float64) string {
 	return fmt.Sprintf("%02d:%02d:%02d:%02d", h, m, s, frames)
 }
 
+// tarGzDir mirrors zipDir but writes a gzip-compressed tarball, keeping file modes.
+func tarGzDir(srcDir, archivePath string) error {
+	f, err := os.Create(archivePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+
+	walkErr := filepath.WalkDir(srcDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = rel
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+		return nil
+	})
+	if walkErr != nil {
+		return walkErr
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
 func zipDir(srcDir, zipPath string) error {
 	f, err := os.Create(zipPath)
 	if err != nil {
@@ -716,7 +1415,7 @@ func exportExitWithErr(asJSON bool, exitCode int, msg string) int {
 }
 
 func printExportJSON(v exportJSONResult) {
-	data, err := json.Marshal(v)
+	data, err := marshalJSONResult(v)
 	if err != nil {
 		logError("json.marshal_failed", "context", "export_result", "error", err)
 		return
@@ -0,0 +1,172 @@
+// media-ingest (mingest) - Media Ingestion CLI tool
+// Copyright (C) 2026  Harrison Wang <https://mingest.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ingest
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// localizedMessage holds the variants of one user-facing string. Chinese is
+// the default; English is used when MINGEST_LANG or the system locale asks
+// for it.
+type localizedMessage struct {
+	ZH string
+	EN string
+}
+
+// messageCatalog is keyed by a stable message id. Not every string goes
+// through it yet: usage and failure hints do; most log detail is still zh.
+var messageCatalog = map[string]localizedMessage{
+	"hint.target_site": {
+		ZH: "目标网站",
+		EN: "the target site",
+	},
+	"hint.cookie_db_locked": {
+		ZH: "浏览器 cookies 数据库无法读取（常见原因: 浏览器仍在占用 cookies 数据库）。请先彻底退出浏览器（含后台进程）后重试；或改用 Firefox；或执行 `%s`（使用 CDP 从浏览器进程内导出 cookies，避免读取数据库）。",
+		EN: "Cannot read the browser cookie database (usually because the browser still has it open). Quit the browser completely (including background processes) and retry, switch to Firefox, or run `%s` (exports cookies from inside the browser via CDP instead of reading the database).",
+	},
+	"hint.cookie_decrypt": {
+		ZH: "浏览器 cookies 解密失败。请改用 Firefox，或执行 `%s`。",
+		EN: "Failed to decrypt browser cookies. Switch to Firefox, or run `%s`.",
+	},
+	"hint.app_bound": {
+		ZH: "检测到 Chrome App-Bound Cookie Encryption 相关错误。此模式下第三方工具可能无法直接解密 Chrome cookies。mingest 会自动改用 CDP 方式（工具专用 profile）；若仍失败，可执行 `%s` 或改用 Firefox 的账户登录信息。",
+		EN: "Chrome App-Bound Cookie Encryption detected. Third-party tools may be unable to decrypt Chrome cookies in this mode. mingest switches to CDP (a dedicated profile) automatically; if that still fails, run `%s` or use your Firefox login.",
+	},
+	"hint.cookie_permission": {
+		ZH: "读取浏览器 cookies 被拒绝。请检查浏览器进程占用与文件权限。",
+		EN: "Permission denied while reading browser cookies. Check whether the browser is holding the file and the file permissions.",
+	},
+	"hint.cookie_keyring": {
+		ZH: "浏览器 cookies 解密失败（keyring 不可用）。可用 `--keyring gnomekeyring|kwallet|basictext`（或 MINGEST_BROWSER=chrome+gnomekeyring）指定 keyring；如果你是 SSH 会话，请在本机桌面终端运行，或改用 Firefox，或执行 `%s`。",
+		EN: "Failed to decrypt browser cookies (keyring unavailable). Pick the keyring with `--keyring gnomekeyring|kwallet|basictext` (or MINGEST_BROWSER=chrome+gnomekeyring); if this is an SSH session, run it from a local desktop terminal, switch to Firefox, or run `%s`.",
+	},
+	"hint.auth_required": {
+		ZH: "需要登录 %s。请先在浏览器登录后重试，或执行 `%s`。",
+		EN: "Sign-in to %s is required. Sign in with your browser and retry, or run `%s`.",
+	},
+	"hint.age_restricted": {
+		ZH: "需要登录 %s 并完成额外确认。请在浏览器中登录并打开该视频完成确认后重试；或执行 `%s` 使用工具专用账户登录信息。",
+		EN: "Sign-in to %s plus an extra confirmation is required. Sign in with your browser, open the video to confirm, then retry; or run `%s` to use a dedicated login.",
+	},
+	"hint.auth_permission": {
+		ZH: "需要登录 %s（或账号具备相应权限）。请先在浏览器中登录后重试；或执行 `%s`。",
+		EN: "Sign-in to %s (or an account with access) is required. Sign in with your browser and retry, or run `%s`.",
+	},
+	"hint.cookie_file_invalid": {
+		ZH: "cookies 文件格式异常。",
+		EN: "The cookies file is malformed.",
+	},
+	"hint.runtime_missing": {
+		ZH: "JS runtime 不可用。请确认 deno 或 node 可执行，并可被该程序访问。",
+		EN: "No JS runtime available. Make sure deno or node is installed and reachable by mingest.",
+	},
+	"hint.ffmpeg_missing": {
+		ZH: "ffmpeg 不可用。请将 ffmpeg/ffprobe 放在同一目录（工作目录或程序同目录），或加入 PATH，或改用 *_bundled。",
+		EN: "ffmpeg is unavailable. Put ffmpeg/ffprobe next to each other (working directory or next to mingest), add them to PATH, or use a *_bundled build.",
+	},
+	"hint.ffprobe_missing": {
+		ZH: "ffprobe 不可用。请将 ffmpeg/ffprobe 放在同一目录（工作目录或程序同目录），或加入 PATH，或改用 *_bundled。",
+		EN: "ffprobe is unavailable. Put ffmpeg/ffprobe next to each other (working directory or next to mingest), add them to PATH, or use a *_bundled build.",
+	},
+	"hint.file_too_large": {
+		ZH: "文件超过大小上限（%s），未下载。可调大 --max-filesize，或用 --section 只下载需要的片段。",
+		EN: "The file exceeds the size limit (%s) and was not downloaded. Raise --max-filesize, or use --section to fetch only the part you need.",
+	},
+	"hint.rate_limited": {
+		ZH: "平台限流（HTTP 429），重试后仍失败。请稍后再试，或用 --sleep-interval / --limit-rate 降低请求频率；可用 MINGEST_RATE_LIMIT_COOLDOWN 调整等待时间。",
+		EN: "The platform is rate limiting (HTTP 429) and retries did not help. Try again later, or slow down with --sleep-interval / --limit-rate; tune the wait with MINGEST_RATE_LIMIT_COOLDOWN.",
+	},
+	"hint.live_stream": {
+		ZH: "这是正在进行的直播，文件会一直增长，默认不下载。可加 --live-from-start 从开头录制到直播结束，或等直播结束后重试。",
+		EN: "This is an ongoing live stream; the file would keep growing, so it is not downloaded by default. Add --live-from-start to record from the beginning until it ends, or retry after the stream ends.",
+	},
+	"hint.live_not_started": {
+		ZH: "直播/首映尚未开始。可加 --wait-for-video <秒数|最小-最大> 等待开播后再下载。",
+		EN: "The live stream or premiere has not started yet. Add --wait-for-video <sec|min-max> to wait for it and then download.",
+	},
+	"hint.thumbnail_embed_failed": {
+		ZH: "嵌入封面失败（该来源可能没有可用封面）。可加 --no-embed-thumbnail 重试。",
+		EN: "Embedding the thumbnail failed (the source may have no usable thumbnail). Retry with --no-embed-thumbnail.",
+	},
+	"hint.download_failed": {
+		ZH: "下载失败。可先执行 `yt-dlp -U` 更新，再检查 cookies 是否过期。",
+		EN: "Download failed. Try updating with `yt-dlp -U`, then check whether your cookies have expired.",
+	},
+	"hint.timed_out": {
+		ZH: "下载超时：可增大 --timeout 或 MINGEST_DOWNLOAD_TIMEOUT 后重试",
+		EN: "Download timed out: increase --timeout or MINGEST_DOWNLOAD_TIMEOUT and retry",
+	},
+	"get.download_failed": {
+		ZH: "下载失败",
+		EN: "download failed",
+	},
+	"get.partial_kept": {
+		ZH: "下载失败（已保留未完成的部分文件 %s，重新执行相同命令会自动续传）",
+		EN: "download failed (partial file kept at %s; rerun the same command to resume)",
+	},
+	"get.partial_download": {
+		ZH: "下载未完成（存在部分文件 %s，重新执行相同命令会自动续传）",
+		EN: "download incomplete (partial file at %s; rerun the same command to resume)",
+	},
+	"get.output_path_missing": {
+		ZH: "下载成功，但未能解析输出文件路径",
+		EN: "download succeeded but the output file path could not be determined",
+	},
+}
+
+// uiLang returns "zh" or "en". MINGEST_LANG wins; otherwise the first set
+// locale variable (LC_ALL, LC_MESSAGES, LANG) decides, and only an English
+// locale switches away from the Chinese default.
+func uiLang() string {
+	switch v := strings.ToLower(strings.TrimSpace(os.Getenv("MINGEST_LANG"))); {
+	case strings.HasPrefix(v, "en"):
+		return "en"
+	case strings.HasPrefix(v, "zh"):
+		return "zh"
+	}
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := strings.ToLower(strings.TrimSpace(os.Getenv(key)))
+		if v == "" {
+			continue
+		}
+		if strings.HasPrefix(v, "en") {
+			return "en"
+		}
+		return "zh"
+	}
+	return "zh"
+}
+
+// tr looks up id in the catalog for the current language and formats it with
+// args. Unknown ids are returned as-is so a missing entry is visible, not fatal.
+func tr(id string, args ...any) string {
+	m, ok := messageCatalog[id]
+	if !ok {
+		return id
+	}
+	text := m.ZH
+	if uiLang() == "en" && m.EN != "" {
+		text = m.EN
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
@@ -0,0 +1,264 @@
+// media-ingest (mingest) - Media Ingestion CLI tool
+// Copyright (C) 2026  Harrison Wang <https://mingest.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ingest
+
+import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// importMediaExts are the file extensions picked up when importing a directory.
+var importMediaExts = map[string]bool{
+	".mp4": true, ".m4v": true, ".mkv": true, ".webm": true, ".mov": true,
+	".avi": true, ".flv": true, ".ts": true,
+	".m4a": true, ".mp3": true, ".aac": true, ".opus": true, ".ogg": true,
+	".wav": true, ".flac": true,
+}
+
+type importOptions struct {
+	Path      string
+	URL       string
+	Recursive bool
+	JSON      bool
+}
+
+type importItem struct {
+	AssetID        string `json:"asset_id"`
+	OutputPath     string `json:"output_path"`
+	Title          string `json:"title"`
+	AlreadyIndexed bool   `json:"already_indexed,omitempty"`
+}
+
+type importJSONResult struct {
+	OK       bool         `json:"ok"`
+	ExitCode int          `json:"exit_code"`
+	Error    string       `json:"error,omitempty"`
+	Path     string       `json:"path,omitempty"`
+	URL      string       `json:"url,omitempty"`
+	Platform string       `json:"platform,omitempty"`
+	Imported int          `json:"imported"`
+	Skipped  int          `json:"skipped"`
+	Items    []importItem `json:"items,omitempty"`
+}
+
+func parseImportOptions(args []string) (importOptions, error) {
+	opts := importOptions{}
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		switch {
+		case arg == "--json":
+			opts.JSON = true
+		case arg == "--recursive", arg == "-r":
+			opts.Recursive = true
+		case arg == "--url":
+			if i+1 >= len(args) {
+				return importOptions{}, fmt.Errorf("`--url` 缺少参数")
+			}
+			i++
+			opts.URL = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--url="):
+			opts.URL = strings.TrimSpace(strings.TrimPrefix(arg, "--url="))
+		case strings.HasPrefix(arg, "-"):
+			return importOptions{}, fmt.Errorf("不支持的参数: %s", arg)
+		default:
+			if opts.Path != "" {
+				return importOptions{}, fmt.Errorf("`mingest import` 仅支持一个路径")
+			}
+			opts.Path = arg
+		}
+	}
+	if opts.Path == "" {
+		return importOptions{}, fmt.Errorf("缺少路径。用法: mingest import <path> [--url <url>] [--recursive] [--json]")
+	}
+	if opts.URL != "" {
+		u, err := url.Parse(opts.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return importOptions{}, fmt.Errorf("`--url` 需为 http(s) URL: %s", opts.URL)
+		}
+	}
+	return opts, nil
+}
+
+func runImport(opts importOptions) int {
+	result := executeImport(opts)
+	if opts.JSON {
+		printImportJSON(result)
+		return result.ExitCode
+	}
+	if !result.OK {
+		logError("import.failed", "exit_code", result.ExitCode, "detail", result.Error)
+		return result.ExitCode
+	}
+	for _, it := range result.Items {
+		state := "imported"
+		if it.AlreadyIndexed {
+			state = "skipped"
+		}
+		fmt.Printf("%s  %s  %s\n", it.AssetID, state, it.OutputPath)
+	}
+	fmt.Printf("imported: %d\n", result.Imported)
+	fmt.Printf("skipped: %d\n", result.Skipped)
+	return exitOK
+}
+
+// executeImport registers existing local media files in the asset index so
+// they show up in `ls` and resolve by asset_id like downloaded assets. Files
+// already indexed under the same asset_id and path (and URL, when given) are
+// skipped.
+func executeImport(opts importOptions) importJSONResult {
+	result := importJSONResult{ExitCode: exitOK, URL: opts.URL}
+	fail := func(code int, msg string) importJSONResult {
+		result.OK = false
+		result.ExitCode = code
+		result.Error = msg
+		return result
+	}
+
+	root, err := filepath.Abs(opts.Path)
+	if err != nil {
+		return fail(exitUsage, fmt.Sprintf("无效的路径: %v", err))
+	}
+	result.Path = root
+	st, err := os.Stat(root)
+	if err != nil {
+		return fail(exitUsage, fmt.Sprintf("路径不存在: %s", root))
+	}
+
+	var paths []string
+	if st.IsDir() {
+		if opts.URL != "" {
+			return fail(exitUsage, "`--url` 仅适用于导入单个文件")
+		}
+		paths, err = collectImportFiles(root, opts.Recursive)
+		if err != nil {
+			return fail(exitDownloadFailed, fmt.Sprintf("扫描目录失败: %v", err))
+		}
+		if len(paths) == 0 {
+			return fail(exitUsage, fmt.Sprintf("目录中没有可导入的媒体文件: %s", root))
+		}
+	} else {
+		paths = []string{root}
+	}
+
+	if opts.URL != "" {
+		// Validated in parseImportOptions.
+		u, _ := url.Parse(opts.URL)
+		if p, ok := platformForURL(u); ok {
+			result.Platform = p.ID
+		} else {
+			logWarn("import.platform_unknown", "url", opts.URL)
+		}
+	}
+
+	ids, err := computeAssetIDs(paths, assetHashWorkers)
+	if err != nil {
+		return fail(exitDownloadFailed, fmt.Sprintf("生成 asset_id 失败: %v", err))
+	}
+
+	records, err := readAssetRecords()
+	if err != nil {
+		return fail(exitDownloadFailed, fmt.Sprintf("读取素材索引失败: %v", err))
+	}
+	// With --url, re-importing a known file records the new source URL.
+	indexKey := func(assetID, path, rawURL string) string {
+		if opts.URL == "" {
+			rawURL = ""
+		}
+		return assetID + "\x00" + filepath.Clean(path) + "\x00" + rawURL
+	}
+	indexed := make(map[string]bool, len(records))
+	for _, r := range records {
+		indexed[indexKey(strings.TrimSpace(r.AssetID), strings.TrimSpace(r.OutputPath), strings.TrimSpace(r.URL))] = true
+	}
+
+	createdAt := time.Now().UTC().Format(time.RFC3339)
+	for _, p := range paths {
+		item := importItem{AssetID: ids[p], OutputPath: p, Title: filepath.Base(p)}
+		if indexed[indexKey(item.AssetID, p, opts.URL)] {
+			item.AlreadyIndexed = true
+			result.Skipped++
+			result.Items = append(result.Items, item)
+			continue
+		}
+		if err := appendAssetRecord(assetRecord{
+			AssetID:    item.AssetID,
+			URL:        opts.URL,
+			Platform:   result.Platform,
+			Title:      item.Title,
+			OutputPath: p,
+			CreatedAt:  createdAt,
+		}); err != nil {
+			return fail(exitDownloadFailed, fmt.Sprintf("写入素材索引失败: %v", err))
+		}
+		logInfo("import.asset_indexed", "asset_id", item.AssetID, "path", p)
+		result.Imported++
+		result.Items = append(result.Items, item)
+	}
+
+	result.OK = true
+	return result
+}
+
+// collectImportFiles lists media files directly under root, or in every
+// subdirectory when recursive is set. Hidden files and directories are skipped.
+func collectImportFiles(root string, recursive bool) ([]string, error) {
+	var out []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type().IsRegular() && importMediaExts[strings.ToLower(filepath.Ext(path))] {
+			out = append(out, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+func printImportJSON(v importJSONResult) {
+	data, err := marshalJSONResult(v)
+	if err != nil {
+		logError("json.marshal_failed", "context", "import_result", "error", err)
+		return
+	}
+	fmt.Println(string(data))
+}
@@ -0,0 +1,209 @@
+// media-ingest (mingest) - Media Ingestion CLI tool
+// Copyright (C) 2026  Harrison Wang <https://mingest.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ingest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// getInfoJSONResult is printed by `get --info --json`; Info carries yt-dlp's
+// full metadata document unchanged.
+type getInfoJSONResult struct {
+	OK                bool            `json:"ok"`
+	ExitCode          int             `json:"exit_code"`
+	Error             string          `json:"error,omitempty"`
+	ErrorCode         string          `json:"error_code,omitempty"`
+	Hint              string          `json:"hint,omitempty"`
+	URL               string          `json:"url,omitempty"`
+	Platform          string          `json:"platform,omitempty"`
+	Title             string          `json:"title,omitempty"`
+	DurationSec       float64         `json:"duration_sec,omitempty"`
+	Uploader          string          `json:"uploader,omitempty"`
+	FormatCount       int             `json:"format_count"`
+	Resolutions       []string        `json:"resolutions,omitempty"`
+	SubtitleLanguages []string        `json:"subtitle_languages,omitempty"`
+	AutoCaptionCount  int             `json:"auto_caption_count"`
+	UsedCookieCache   bool            `json:"used_cookie_cache"`
+	Info              json.RawMessage `json:"info,omitempty"`
+}
+
+type ytDlpInfoSummary struct {
+	Title    string  `json:"title"`
+	Duration float64 `json:"duration"`
+	Uploader string  `json:"uploader"`
+	Channel  string  `json:"channel"`
+	Formats  []struct {
+		Height int    `json:"height"`
+		VCodec string `json:"vcodec"`
+	} `json:"formats"`
+	Subtitles         map[string]interface{} `json:"subtitles"`
+	AutomaticCaptions map[string]interface{} `json:"automatic_captions"`
+}
+
+func runGetInfo(opts getOptions) int {
+	result := executeGetInfo(opts)
+	if opts.JSON {
+		printGetInfoJSON(result)
+		return result.ExitCode
+	}
+	if !result.OK {
+		return result.ExitCode
+	}
+
+	fmt.Printf("title: %s\n", result.Title)
+	if result.Uploader != "" {
+		fmt.Printf("uploader: %s\n", result.Uploader)
+	}
+	fmt.Printf("duration_sec: %.0f\n", result.DurationSec)
+	fmt.Printf("formats: %d\n", result.FormatCount)
+	if len(result.Resolutions) > 0 {
+		fmt.Printf("resolutions: %s\n", strings.Join(result.Resolutions, ","))
+	}
+	if len(result.SubtitleLanguages) > 0 {
+		fmt.Printf("subtitles: %s\n", strings.Join(result.SubtitleLanguages, ","))
+	} else {
+		fmt.Printf("subtitles: -\n")
+	}
+	fmt.Printf("auto_captions: %d\n", result.AutoCaptionCount)
+	return exitOK
+}
+
+// executeGetInfo fetches metadata only: nothing is downloaded and no asset
+// record is written. It uses the platform cookie cache when present (the same
+// fast path as get) but does not fall back to reading browsers.
+func executeGetInfo(opts getOptions) getInfoJSONResult {
+	result := getInfoJSONResult{URL: opts.TargetURL}
+	fail := func(exitCode int, errorCode, msg string) getInfoJSONResult {
+		logError("get.info_failed", "exit_code", exitCode, "error_code", errorCode, "detail", msg)
+		result.ExitCode = exitCode
+		result.ErrorCode = errorCode
+		result.Error = msg
+		return result
+	}
+
+	u, err := validateURL(opts.TargetURL)
+	if err != nil {
+		return fail(exitUsage, errorCodeInvalidArgument, fmt.Sprintf("输入的 URL 无效: %v", err))
+	}
+	found, err := detectDeps()
+	if err != nil {
+		var depErr dependencyError
+		if errors.As(err, &depErr) {
+			return fail(depErr.ExitCode, errorCodeForExit(depErr.ExitCode), depErr.Message)
+		}
+		return fail(exitDownloadFailed, errorCodeDownloadFailed, fmt.Sprintf("依赖检测失败: %v", err))
+	}
+
+	p, ok := platformForURL(u)
+	if !ok {
+		p = videoPlatform{}
+	}
+	result.Platform = strings.TrimSpace(p.ID)
+
+	cookieFile := ""
+	if result.Platform != "" {
+		if path, err := cookiesCacheFilePath(p); err == nil && fileExists(path) {
+			cookieFile = path
+			result.UsedCookieCache = true
+		}
+	}
+
+	var extra []string
+	if opts.Proxy != "" {
+		extra = append(extra, "--proxy", opts.Proxy)
+	}
+	raw, err := fetchYtDlpInfoJSON(found, opts.TargetURL, cookieFile, 0, extra...)
+	if cookieFile != "" && fileExists(cookieFile) {
+		// yt-dlp dumps its cookie jar back into the file; keep it scoped.
+		if ferr := filterCookieFileForPlatform(cookieFile, p); ferr != nil {
+			logWarn("auth.cookie_filter_failed", "error", ferr, "path", cookieFile)
+		}
+	}
+	if err != nil {
+		code, errorCode, hint := classifyFailure(err.Error(), p)
+		result.Hint = hint
+		return fail(code, errorCode, err.Error())
+	}
+
+	var summary ytDlpInfoSummary
+	if err := json.Unmarshal([]byte(raw), &summary); err != nil {
+		return fail(exitDownloadFailed, errorCodeDownloadFailed, fmt.Sprintf("解析元信息失败: %v", err))
+	}
+
+	result.OK = true
+	result.ExitCode = exitOK
+	result.Title = summary.Title
+	result.DurationSec = summary.Duration
+	result.Uploader = firstNonEmpty(summary.Uploader, summary.Channel)
+	result.FormatCount = len(summary.Formats)
+	result.Resolutions = infoResolutions(summary)
+	result.SubtitleLanguages = infoTrackLanguages(summary.Subtitles)
+	result.AutoCaptionCount = len(infoTrackLanguages(summary.AutomaticCaptions))
+	if opts.JSON {
+		result.Info = json.RawMessage(raw)
+	}
+	logInfo("get.info_fetched", "url", opts.TargetURL, "formats", result.FormatCount, "subtitles", len(result.SubtitleLanguages))
+	return result
+}
+
+// infoResolutions lists distinct video heights, highest first (e.g. 2160p).
+func infoResolutions(summary ytDlpInfoSummary) []string {
+	seen := make(map[int]struct{})
+	heights := make([]int, 0, len(summary.Formats))
+	for _, f := range summary.Formats {
+		if f.Height <= 0 || f.VCodec == "none" {
+			continue
+		}
+		if _, ok := seen[f.Height]; ok {
+			continue
+		}
+		seen[f.Height] = struct{}{}
+		heights = append(heights, f.Height)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(heights)))
+	out := make([]string, 0, len(heights))
+	for _, h := range heights {
+		out = append(out, fmt.Sprintf("%dp", h))
+	}
+	return out
+}
+
+func infoTrackLanguages(tracks map[string]interface{}) []string {
+	out := make([]string, 0, len(tracks))
+	for code := range tracks {
+		code = strings.TrimSpace(code)
+		if code == "" || strings.Contains(normalizeLangCode(code), "live_chat") {
+			continue
+		}
+		out = append(out, code)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func printGetInfoJSON(v getInfoJSONResult) {
+	data, err := marshalJSONResult(v)
+	if err != nil {
+		logError("json.marshal_failed", "context", "get_info_result", "error", err)
+		return
+	}
+	fmt.Println(string(data))
+}
@@ -17,14 +17,54 @@
 package ingest
 
 import (
+	"io"
 	"log/slog"
 	"os"
 	"strings"
 	"time"
 )
 
+// logLevelFlag is set by the global --quiet/--verbose flags and takes
+// precedence over MINGEST_LOG_LEVEL.
+var logLevelFlag string
+
+// quietMode also silences console chatter that is not a log line (yt-dlp
+// passthrough, progress bars). Command results and errors are still printed.
+var quietMode bool
+
+// applyVerbosityFlags strips --quiet and --verbose from args; the last one
+// given wins. Call before configureLogger.
+func applyVerbosityFlags(args []string) []string {
+	out := make([]string, 0, len(args))
+	for i, arg := range args {
+		if i >= 1 {
+			switch strings.TrimSpace(arg) {
+			case "--quiet":
+				logLevelFlag = "warn"
+				quietMode = true
+				continue
+			case "--verbose":
+				logLevelFlag = "debug"
+				quietMode = false
+				continue
+			}
+		}
+		out = append(out, arg)
+	}
+	return out
+}
+
+// statusWriter is where informational console output goes; it discards
+// everything under --quiet.
+func statusWriter() io.Writer {
+	if quietMode {
+		return io.Discard
+	}
+	return os.Stderr
+}
+
 func configureLogger() {
-	level := parseLogLevel(os.Getenv("MINGEST_LOG_LEVEL"))
+	level := parseLogLevel(firstNonEmpty(logLevelFlag, os.Getenv("MINGEST_LOG_LEVEL")))
 	options := &slog.HandlerOptions{
 		Level: level,
 		ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
@@ -0,0 +1,199 @@
+// media-ingest (mingest) - Media Ingestion CLI tool
+// Copyright (C) 2026  Harrison Wang <https://mingest.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ingest
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+type openOptions struct {
+	AssetRef  string
+	What      string
+	BundleDir string
+}
+
+func parseOpenOptions(args []string) (openOptions, error) {
+	opts := openOptions{What: "html"}
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		switch {
+		case arg == "--what":
+			if i+1 >= len(args) {
+				return openOptions{}, fmt.Errorf("`--what` 缺少参数")
+			}
+			i++
+			opts.What = strings.ToLower(strings.TrimSpace(args[i]))
+		case strings.HasPrefix(arg, "--what="):
+			opts.What = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--what=")))
+		case arg == "--bundle-dir":
+			if i+1 >= len(args) {
+				return openOptions{}, fmt.Errorf("`--bundle-dir` 缺少参数")
+			}
+			i++
+			opts.BundleDir = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--bundle-dir="):
+			opts.BundleDir = strings.TrimSpace(strings.TrimPrefix(arg, "--bundle-dir="))
+		case strings.HasPrefix(arg, "-"):
+			return openOptions{}, fmt.Errorf("不支持的参数: %s", arg)
+		default:
+			if opts.AssetRef != "" {
+				return openOptions{}, fmt.Errorf("`mingest open` 仅支持一个 asset_ref")
+			}
+			opts.AssetRef = arg
+		}
+	}
+	if strings.TrimSpace(opts.AssetRef) == "" {
+		return openOptions{}, fmt.Errorf("缺少 asset_ref。用法: mingest open <asset_ref> [--what <html|bundle|export>]")
+	}
+	switch opts.What {
+	case "html", "bundle", "export":
+	default:
+		return openOptions{}, fmt.Errorf("`--what` 仅支持 html|bundle|export")
+	}
+	return opts, nil
+}
+
+func runOpen(opts openOptions) int {
+	asset, err := resolvePrepAsset(opts.AssetRef)
+	if err != nil {
+		logError("open.failed", "exit_code", exitDownloadFailed, "detail", err.Error())
+		return exitDownloadFailed
+	}
+	if strings.TrimSpace(asset.AssetID) == "" {
+		assetID, err := computeAssetID(asset.OutputPath)
+		if err != nil {
+			logError("open.failed", "exit_code", exitDownloadFailed, "detail", fmt.Sprintf("生成 asset_id 失败: %v", err))
+			return exitDownloadFailed
+		}
+		asset.AssetID = assetID
+	}
+
+	target, err := resolveOpenTarget(asset, opts)
+	if err != nil {
+		logError("open.failed", "exit_code", exitDownloadFailed, "detail", err.Error())
+		return exitDownloadFailed
+	}
+
+	fmt.Printf("asset_id: %s\n", asset.AssetID)
+	fmt.Printf("path: %s\n", target)
+
+	name, args, ok := openerCommand(target)
+	if !ok {
+		// Headless hosts have nothing to open with; the printed path is enough.
+		logInfo("open.no_opener", "path", target)
+		fmt.Println("opened: false")
+		return exitOK
+	}
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		logWarn("open.opener_failed", "opener", name, "path", target, "error", err)
+		fmt.Println("opened: false")
+		return exitOK
+	}
+	fmt.Println("opened: true")
+	return exitOK
+}
+
+// resolveOpenTarget finds the newest artifact of the requested kind for asset.
+func resolveOpenTarget(asset prepResolvedAsset, opts openOptions) (string, error) {
+	switch opts.What {
+	case "bundle":
+		dir, _, err := latestPrepBundle(asset, opts.BundleDir)
+		return dir, err
+	case "export":
+		dir := latestArtifactDir(artifactRoots(asset, opts.BundleDir, "export"), "manifest.json")
+		if dir == "" {
+			return "", fmt.Errorf("未找到导出目录（请先执行 `mingest export %s`）", asset.AssetID)
+		}
+		return dir, nil
+	default:
+		dir := latestArtifactDir(artifactRoots(asset, opts.BundleDir, "semantic"), "review.html")
+		if dir == "" {
+			return "", fmt.Errorf("未找到 review.html（请先执行 `mingest semantic %s`）", asset.AssetID)
+		}
+		return filepath.Join(dir, "review.html"), nil
+	}
+}
+
+// artifactRoots lists an asset's kind directories in priority order: --bundle-dir,
+// the central library, then the bundle root beside the media.
+func artifactRoots(asset prepResolvedAsset, bundleDir, kind string) []string {
+	roots := []string{mingestArtifactRoot(asset.OutputPath, asset.AssetID, bundleDir, kind)}
+	for _, fallback := range []string{
+		mingestArtifactRoot(asset.OutputPath, asset.AssetID, "", kind),
+		filepath.Join(mingestBundleRoot(asset.OutputPath, ""), kind, asset.AssetID),
+	} {
+		if !contains(roots, fallback) {
+			roots = append(roots, fallback)
+		}
+	}
+	return roots
+}
+
+// latestArtifactDir returns the newest timestamped subdirectory that contains marker.
+func latestArtifactDir(roots []string, marker string) string {
+	for _, root := range roots {
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			continue
+		}
+		dirs := make([]string, 0, len(entries))
+		for _, e := range entries {
+			if e.IsDir() {
+				dirs = append(dirs, filepath.Join(root, e.Name()))
+			}
+		}
+		sort.Slice(dirs, func(i, j int) bool {
+			return filepath.Base(dirs[i]) > filepath.Base(dirs[j])
+		})
+		for _, d := range dirs {
+			if fileExists(filepath.Join(d, marker)) {
+				return d
+			}
+		}
+	}
+	return ""
+}
+
+// openerCommand returns the OS default handler invocation for path, or false
+// when none is available (e.g. a Linux host without a desktop session).
+func openerCommand(path string) (string, []string, bool) {
+	switch runtime.GOOS {
+	case "darwin":
+		if p, err := exec.LookPath("open"); err == nil {
+			return p, []string{path}, true
+		}
+	case "windows":
+		return "cmd", []string{"/C", "start", "", path}, true
+	default:
+		if os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
+			return "", nil, false
+		}
+		if p, err := exec.LookPath("xdg-open"); err == nil {
+			return p, []string{path}, true
+		}
+	}
+	return "", nil, false
+}
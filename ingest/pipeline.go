@@ -0,0 +1,346 @@
+// media-ingest (mingest) - Media Ingestion CLI tool
+// Copyright (C) 2026  Harrison Wang <https://mingest.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ingest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type pipelineOptions struct {
+	URL    string
+	Target string
+	Goal   string
+	To     string
+	With   string
+	OutDir string
+	NoLLM  bool
+	Zip    bool
+	JSON   bool
+
+	ArchiveFormat string
+}
+
+type pipelineStageResult struct {
+	Stage      string `json:"stage"`
+	OK         bool   `json:"ok"`
+	ExitCode   int    `json:"exit_code"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+type pipelineJSONResult struct {
+	OK            bool                  `json:"ok"`
+	ExitCode      int                   `json:"exit_code"`
+	Error         string                `json:"error,omitempty"`
+	FailedStage   string                `json:"failed_stage,omitempty"`
+	URL           string                `json:"url,omitempty"`
+	Target        string                `json:"target,omitempty"`
+	Goal          string                `json:"goal,omitempty"`
+	To            string                `json:"to,omitempty"`
+	AssetID       string                `json:"asset_id,omitempty"`
+	OutputPath    string                `json:"output_path,omitempty"`
+	PrepPlan      string                `json:"prep_plan,omitempty"`
+	SemanticDir   string                `json:"semantic_dir,omitempty"`
+	SelectedCount int                   `json:"selected_count,omitempty"`
+	ExportDir     string                `json:"export_dir,omitempty"`
+	Exported      map[string]string     `json:"exported,omitempty"`
+	ZipPath       string                `json:"zip_path,omitempty"`
+	ArchivePath   string                `json:"archive_path,omitempty"`
+	Warnings      []string              `json:"warnings,omitempty"`
+	Stages        []pipelineStageResult `json:"stages"`
+}
+
+func parsePipelineOptions(args []string) (pipelineOptions, error) {
+	opts := pipelineOptions{
+		Target: "shorts",
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		switch {
+		case arg == "--json":
+			opts.JSON = true
+		case arg == "--no-llm":
+			opts.NoLLM = true
+		case arg == "--zip":
+			opts.Zip = true
+		case arg == "--target":
+			if i+1 >= len(args) {
+				return pipelineOptions{}, fmt.Errorf("`--target` 缺少参数")
+			}
+			i++
+			opts.Target = strings.ToLower(strings.TrimSpace(args[i]))
+		case strings.HasPrefix(arg, "--target="):
+			opts.Target = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--target=")))
+		case arg == "--goal":
+			if i+1 >= len(args) {
+				return pipelineOptions{}, fmt.Errorf("`--goal` 缺少参数")
+			}
+			i++
+			opts.Goal = strings.ToLower(strings.TrimSpace(args[i]))
+		case strings.HasPrefix(arg, "--goal="):
+			opts.Goal = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--goal=")))
+		case arg == "--to":
+			if i+1 >= len(args) {
+				return pipelineOptions{}, fmt.Errorf("`--to` 缺少参数")
+			}
+			i++
+			opts.To = strings.ToLower(strings.TrimSpace(args[i]))
+		case strings.HasPrefix(arg, "--to="):
+			opts.To = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--to=")))
+		case arg == "--with":
+			if i+1 >= len(args) {
+				return pipelineOptions{}, fmt.Errorf("`--with` 缺少参数")
+			}
+			i++
+			opts.With = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--with="):
+			opts.With = strings.TrimSpace(strings.TrimPrefix(arg, "--with="))
+		case arg == "--out-dir":
+			if i+1 >= len(args) {
+				return pipelineOptions{}, fmt.Errorf("`--out-dir` 缺少参数")
+			}
+			i++
+			opts.OutDir = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--out-dir="):
+			opts.OutDir = strings.TrimSpace(strings.TrimPrefix(arg, "--out-dir="))
+		case arg == "--archive-format":
+			if i+1 >= len(args) {
+				return pipelineOptions{}, fmt.Errorf("`--archive-format` 缺少参数")
+			}
+			i++
+			opts.ArchiveFormat = strings.ToLower(strings.TrimSpace(args[i]))
+		case strings.HasPrefix(arg, "--archive-format="):
+			opts.ArchiveFormat = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--archive-format=")))
+		case strings.HasPrefix(arg, "-"):
+			return pipelineOptions{}, fmt.Errorf("不支持的参数: %s", arg)
+		default:
+			if opts.URL != "" {
+				return pipelineOptions{}, fmt.Errorf("`mingest pipeline` 仅支持一个 URL")
+			}
+			opts.URL = arg
+		}
+	}
+
+	if opts.URL == "" {
+		return pipelineOptions{}, fmt.Errorf("缺少 URL。用法: mingest pipeline <url> --to <premiere|resolve|capcut|youtube-chapters|audacity> [--target <v>]")
+	}
+	if opts.To == "" {
+		return pipelineOptions{}, fmt.Errorf("缺少 --to")
+	}
+	if opts.Goal == "" {
+		opts.Goal = pipelineGoalForTarget(opts.Target)
+	}
+
+	// Validate the stage flags up front so a typo fails before the download, not after.
+	if _, err := parsePrepOptions([]string{"pipeline", "--goal=" + opts.Goal}); err != nil {
+		return pipelineOptions{}, err
+	}
+	if _, err := parseSemanticOptions(pipelineSemanticArgs(opts, "pipeline")); err != nil {
+		return pipelineOptions{}, err
+	}
+	if _, err := parseExportOptions(pipelineExportArgs(opts, "pipeline")); err != nil {
+		return pipelineOptions{}, err
+	}
+	return opts, nil
+}
+
+// pipelineGoalForTarget picks the prep goal that feeds semantic best for target.
+func pipelineGoalForTarget(target string) string {
+	if semanticVerticalTarget(target) {
+		return "shorts"
+	}
+	return "highlights"
+}
+
+func pipelineSemanticArgs(opts pipelineOptions, assetRef string) []string {
+	args := []string{assetRef, "--target=" + opts.Target, "--apply", "--json"}
+	if opts.NoLLM {
+		args = append(args, "--no-llm")
+	}
+	return args
+}
+
+func pipelineExportArgs(opts pipelineOptions, assetRef string) []string {
+	args := []string{assetRef, "--to=" + opts.To, "--json"}
+	if opts.With != "" {
+		args = append(args, "--with="+opts.With)
+	}
+	if opts.Zip {
+		args = append(args, "--zip")
+	}
+	if opts.ArchiveFormat != "" {
+		args = append(args, "--archive-format="+opts.ArchiveFormat)
+	}
+	return args
+}
+
+func runPipeline(opts pipelineOptions, cfg appConfig) int {
+	result := executePipeline(opts, cfg)
+	if opts.JSON {
+		printPipelineJSON(result)
+		return result.ExitCode
+	}
+	if !result.OK {
+		logError("pipeline.failed", "stage", result.FailedStage, "exit_code", result.ExitCode, "detail", result.Error)
+		return result.ExitCode
+	}
+	fmt.Printf("asset_id: %s\n", result.AssetID)
+	fmt.Printf("output_path: %s\n", result.OutputPath)
+	fmt.Printf("prep_plan: %s\n", result.PrepPlan)
+	fmt.Printf("semantic_dir: %s\n", result.SemanticDir)
+	fmt.Printf("selected_count: %d\n", result.SelectedCount)
+	fmt.Printf("export_dir: %s\n", result.ExportDir)
+	keys := make([]string, 0, len(result.Exported))
+	for k := range result.Exported {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Printf("%s: %s\n", k, result.Exported[k])
+	}
+	if result.ArchivePath != "" {
+		fmt.Printf("archive: %s\n", result.ArchivePath)
+	}
+	for _, w := range result.Warnings {
+		fmt.Printf("warning: %s\n", w)
+	}
+	return exitOK
+}
+
+// executePipeline runs get → prep → semantic --apply (gated by doctor) → export,
+// threading the asset_id between stages and stopping at the first failure.
+func executePipeline(opts pipelineOptions, cfg appConfig) pipelineJSONResult {
+	result := pipelineJSONResult{
+		URL:    opts.URL,
+		Target: opts.Target,
+		Goal:   opts.Goal,
+		To:     opts.To,
+		Stages: []pipelineStageResult{},
+	}
+	started := time.Now()
+	stage := func(name string, exitCode int, msg string) bool {
+		ok := exitCode == exitOK
+		result.Stages = append(result.Stages, pipelineStageResult{
+			Stage:      name,
+			OK:         ok,
+			ExitCode:   exitCode,
+			Error:      msg,
+			DurationMS: time.Since(started).Milliseconds(),
+		})
+		started = time.Now()
+		if !ok {
+			result.ExitCode = exitCode
+			result.Error = msg
+			result.FailedStage = name
+			return false
+		}
+		logInfo("pipeline.stage_finished", "stage", name, "asset_id", result.AssetID)
+		return true
+	}
+
+	logInfo("pipeline.stage_started", "stage", "get", "url", opts.URL)
+	getArgs := []string{opts.URL, "--json"}
+	if opts.OutDir != "" {
+		getArgs = append(getArgs, "--out-dir="+opts.OutDir)
+	}
+	getOpts, err := parseGetOptions(cfg.withDefaults("get", getArgs))
+	if err != nil {
+		stage("get", exitUsage, err.Error())
+		return result
+	}
+	got := executeGet(getOpts)
+	if !got.OK {
+		stage("get", got.ExitCode, got.Error)
+		return result
+	}
+	if strings.TrimSpace(got.AssetID) == "" {
+		stage("get", exitDownloadFailed, "下载成功，但未能解析输出文件路径")
+		return result
+	}
+	result.AssetID = got.AssetID
+	result.OutputPath = got.OutputPath
+	stage("get", exitOK, "")
+
+	logInfo("pipeline.stage_started", "stage", "prep", "asset_id", result.AssetID)
+	prepOpts, err := parsePrepOptions(cfg.withDefaults("prep", []string{result.AssetID, "--goal=" + opts.Goal, "--json"}))
+	if err != nil {
+		stage("prep", exitUsage, err.Error())
+		return result
+	}
+	prepped := executePrep(prepOpts)
+	if !prepped.OK {
+		stage("prep", prepped.ExitCode, prepped.Error)
+		return result
+	}
+	result.PrepPlan = prepped.PlanPath
+	stage("prep", exitOK, "")
+
+	logInfo("pipeline.stage_started", "stage", "semantic", "asset_id", result.AssetID)
+	semanticOpts, err := parseSemanticOptions(cfg.withDefaults("semantic", pipelineSemanticArgs(opts, result.AssetID)))
+	if err != nil {
+		stage("semantic", exitUsage, err.Error())
+		return result
+	}
+	state, code := runSemanticPipeline(semanticOpts)
+	result.SemanticDir = state.Artifacts.BundleDir
+	result.SelectedCount = len(state.Selected)
+	if code != exitOK {
+		name := "semantic"
+		if code == exitDoctorFailed {
+			name = "doctor"
+		}
+		stage(name, code, strings.Join(state.Warnings, "; "))
+		return result
+	}
+	result.Warnings = append(result.Warnings, state.Warnings...)
+	stage("semantic", exitOK, "")
+
+	logInfo("pipeline.stage_started", "stage", "export", "asset_id", result.AssetID)
+	exportOpts, err := parseExportOptions(cfg.withDefaults("export", pipelineExportArgs(opts, result.AssetID)))
+	if err != nil {
+		stage("export", exitUsage, err.Error())
+		return result
+	}
+	exported := executeExport(exportOpts)
+	if !exported.OK {
+		stage("export", exported.ExitCode, exported.Error)
+		return result
+	}
+	result.ExportDir = exported.OutDir
+	result.Exported = exported.Exported
+	result.ZipPath = exported.ZipPath
+	result.ArchivePath = exported.ArchivePath
+	result.Warnings = append(result.Warnings, exported.Warnings...)
+	stage("export", exitOK, "")
+
+	result.OK = true
+	result.ExitCode = exitOK
+	return result
+}
+
+func printPipelineJSON(v pipelineJSONResult) {
+	data, err := marshalJSONResult(v)
+	if err != nil {
+		logError("json.marshal_failed", "context", "pipeline_result", "error", err)
+		return
+	}
+	fmt.Println(string(data))
+}
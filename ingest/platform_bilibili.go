@@ -16,6 +16,8 @@
 
 package ingest
 
+import "time"
+
 func bilibiliPlatform() videoPlatform {
 	return videoPlatform{
 		ID:   "bilibili",
@@ -32,6 +34,7 @@ func bilibiliPlatform() videoPlatform {
 		AuthCookieNames: []string{
 			"SESSDATA",
 		},
+		RateLimitCooldown: 30 * time.Second,
 	}
 }
 
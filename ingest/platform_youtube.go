@@ -16,6 +16,8 @@
 
 package ingest
 
+import "time"
+
 func youtubePlatform() videoPlatform {
 	return videoPlatform{
 		ID:   "youtube",
@@ -36,6 +38,17 @@ func youtubePlatform() videoPlatform {
 			"__Secure-3PSID",
 			"__Secure-1PSID",
 		},
+		// consent.youtube.com (EU cookie wall) and the in-page consent bump.
+		ConsentSelectors: []string{
+			`form[action*="consent.youtube.com"] button[aria-label^="Accept"]`,
+			`ytd-consent-bump-v2-lightbox button[aria-label^="Accept"]`,
+			`button[aria-label^="Accept all"]`,
+		},
+		ConsentCookieNames: []string{
+			"SOCS",
+			"CONSENT",
+		},
+		RateLimitCooldown: 60 * time.Second,
 	}
 }
 
@@ -19,10 +19,13 @@ package ingest
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/csv"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"fmt"
+	"html"
 	"io"
 	"math"
 	"net/url"
@@ -44,13 +47,24 @@ const (
 )
 
 type prepOptions struct {
-	AssetRef      string `json:"asset_ref"`
-	Goal          string `json:"goal"`
-	Lang          string `json:"lang"`
-	MaxClips      int    `json:"max_clips"`
-	ClipSeconds   int    `json:"clip_seconds"`
-	SubtitleStyle string `json:"subtitle_style"`
-	JSON          bool   `json:"-"`
+	AssetRef        string  `json:"asset_ref"`
+	Goal            string  `json:"goal"`
+	Lang            string  `json:"lang"`
+	MaxClips        int     `json:"max_clips"`
+	ClipSeconds     int     `json:"clip_seconds"`
+	SubtitleStyle   string  `json:"subtitle_style"`
+	Aspect          string  `json:"aspect,omitempty"`
+	Strategy        string  `json:"strategy"`
+	SkipIntroSec    int     `json:"skip_intro_sec,omitempty"`
+	MinGapSec       float64 `json:"min_gap_sec,omitempty"`
+	LabelTemplate   string  `json:"label_template,omitempty"`
+	SingleFile      bool    `json:"single_file,omitempty"`
+	BundleDir       string  `json:"bundle_dir,omitempty"`
+	KeepTemp        bool    `json:"keep_temp,omitempty"`
+	WhisperDevice   string  `json:"whisper_device,omitempty"`
+	SubtitleSegment string  `json:"subtitle_segment,omitempty"`
+	Summary         bool    `json:"-"`
+	JSON            bool    `json:"-"`
 }
 
 type prepResolvedAsset struct {
@@ -68,6 +82,30 @@ type mediaProbe struct {
 	FPS         float64 `json:"fps"`
 	VideoCodec  string  `json:"video_codec"`
 	AudioTracks int     `json:"audio_tracks"`
+	// AudioLanguages lists audio stream language tags (e.g. "eng", "chi") when present.
+	AudioLanguages []string `json:"audio_languages,omitempty"`
+	// Color fields carry ffprobe's names (e.g. bt2020 / arib-std-b67 / bt2020nc); empty when untagged.
+	ColorPrimaries string `json:"color_primaries,omitempty"`
+	ColorTransfer  string `json:"color_transfer,omitempty"`
+	ColorSpace     string `json:"color_space,omitempty"`
+	// AudioOnly marks media without a video stream; width/height/fps stay zero.
+	AudioOnly bool `json:"audio_only,omitempty"`
+	// SponsorSegments come from chapters embedded by `get --sponsorblock mark`.
+	SponsorSegments []sponsorSegment `json:"sponsor_segments,omitempty"`
+	// Chapters are the source's own chapters embedded by `get --embed-chapters`.
+	Chapters []mediaChapter `json:"chapters,omitempty"`
+}
+
+type mediaChapter struct {
+	StartSec float64 `json:"start_sec"`
+	EndSec   float64 `json:"end_sec"`
+	Title    string  `json:"title,omitempty"`
+}
+
+type sponsorSegment struct {
+	StartSec float64 `json:"start_sec"`
+	EndSec   float64 `json:"end_sec"`
+	Category string  `json:"category"`
 }
 
 type prepClip struct {
@@ -77,6 +115,7 @@ type prepClip struct {
 	DurationSec float64 `json:"duration_sec"`
 	Label       string  `json:"label"`
 	Reason      string  `json:"reason"`
+	Rank        int     `json:"rank,omitempty"`
 }
 
 type prepPlan struct {
@@ -88,34 +127,67 @@ type prepPlan struct {
 	Clips     []prepClip        `json:"clips"`
 	Subtitle  *prepSubtitlePlan `json:"subtitle,omitempty"`
 	Outputs   prepOutputFiles   `json:"outputs"`
+	// Embedded carries file contents for `prep --single-file` plans.
+	Embedded *prepEmbeddedFiles `json:"embedded,omitempty"`
+}
+
+// prepEmbeddedFiles holds the text of the files a single-file plan doesn't
+// write separately. Readers restore them at the paths in Outputs when missing.
+type prepEmbeddedFiles struct {
+	MarkersCSV       string `json:"markers_csv,omitempty"`
+	ChaptersCSV      string `json:"chapters_csv,omitempty"`
+	Subtitle         string `json:"subtitle,omitempty"`
+	SubtitleTemplate string `json:"subtitle_template,omitempty"`
 }
 
 type prepOutputFiles struct {
 	BundleDir        string `json:"bundle_dir"`
 	PlanPath         string `json:"plan_path"`
 	MarkersCSV       string `json:"markers_csv"`
+	ChaptersCSV      string `json:"chapters_csv,omitempty"`
 	SubtitlePath     string `json:"subtitle_path,omitempty"`
 	SubtitleTemplate string `json:"subtitle_template,omitempty"`
 }
 
 type prepJSONResult struct {
-	OK                   bool    `json:"ok"`
-	ExitCode             int     `json:"exit_code"`
-	Error                string  `json:"error,omitempty"`
-	AssetID              string  `json:"asset_id,omitempty"`
-	AssetPath            string  `json:"asset_path,omitempty"`
-	Goal                 string  `json:"goal,omitempty"`
-	DurationSec          float64 `json:"duration_sec,omitempty"`
-	ClipCount            int     `json:"clip_count,omitempty"`
-	BundleDir            string  `json:"bundle_dir,omitempty"`
-	PlanPath             string  `json:"plan_path,omitempty"`
-	MarkersCSV           string  `json:"markers_csv,omitempty"`
-	SubtitlePath         string  `json:"subtitle_path,omitempty"`
-	SubtitleTemplate     string  `json:"subtitle_template,omitempty"`
-	SubtitleSource       string  `json:"subtitle_source,omitempty"`
-	SubtitleLanguage     string  `json:"subtitle_language,omitempty"`
-	SubtitleQualityScore float64 `json:"subtitle_quality_score,omitempty"`
-	SubtitleQualityNote  string  `json:"subtitle_quality_note,omitempty"`
+	OK                   bool             `json:"ok"`
+	ExitCode             int              `json:"exit_code"`
+	Error                string           `json:"error,omitempty"`
+	AssetID              string           `json:"asset_id,omitempty"`
+	AssetPath            string           `json:"asset_path,omitempty"`
+	Goal                 string           `json:"goal,omitempty"`
+	DurationSec          float64          `json:"duration_sec,omitempty"`
+	ClipCount            int              `json:"clip_count,omitempty"`
+	BundleDir            string           `json:"bundle_dir,omitempty"`
+	PlanPath             string           `json:"plan_path,omitempty"`
+	MarkersCSV           string           `json:"markers_csv,omitempty"`
+	ChaptersCSV          string           `json:"chapters_csv,omitempty"`
+	SubtitlePath         string           `json:"subtitle_path,omitempty"`
+	SubtitleTemplate     string           `json:"subtitle_template,omitempty"`
+	SubtitleSource       string           `json:"subtitle_source,omitempty"`
+	SubtitleLanguage     string           `json:"subtitle_language,omitempty"`
+	SubtitleQualityScore float64          `json:"subtitle_quality_score,omitempty"`
+	SubtitleQualityNote  string           `json:"subtitle_quality_note,omitempty"`
+	SubtitleTracks       []string         `json:"subtitle_tracks,omitempty"`
+	ColorPrimaries       string           `json:"color_primaries,omitempty"`
+	ColorTransfer        string           `json:"color_transfer,omitempty"`
+	ColorSpace           string           `json:"color_space,omitempty"`
+	Summary              *prepClipSummary `json:"summary,omitempty"`
+	Warnings             []string         `json:"warnings,omitempty"`
+}
+
+// prepClipSummary describes how the generated clips spread over the asset, for
+// tuning --max-clips/--clip-seconds without opening the markers CSV.
+type prepClipSummary struct {
+	ClipCount     int     `json:"clip_count"`
+	CoveredSec    float64 `json:"covered_sec"`
+	CoverageRatio float64 `json:"coverage_ratio"`
+	MinClipSec    float64 `json:"min_clip_sec"`
+	MeanClipSec   float64 `json:"mean_clip_sec"`
+	MaxClipSec    float64 `json:"max_clip_sec"`
+	MinGapSec     float64 `json:"min_gap_sec"`
+	MeanGapSec    float64 `json:"mean_gap_sec"`
+	MaxGapSec     float64 `json:"max_gap_sec"`
 }
 
 type prepSubtitlePlan struct {
@@ -127,6 +199,16 @@ type prepSubtitlePlan struct {
 	QualityNote      string                `json:"quality_note,omitempty"`
 	SelectedPath     string                `json:"selected_path,omitempty"`
 	Attempts         []prepSubtitleAttempt `json:"attempts,omitempty"`
+	Tracks           []prepSubtitleTrack   `json:"tracks,omitempty"`
+	// Segmentation records how subtitle.srt was re-cut by --subtitle-segment.
+	Segmentation *prepSubtitleSegmentation `json:"segmentation,omitempty"`
+}
+
+// prepSubtitleTrack is one manual platform track saved by `--lang all`.
+type prepSubtitleTrack struct {
+	Language string `json:"language"`
+	Path     string `json:"path,omitempty"`
+	Error    string `json:"error,omitempty"`
 }
 
 type prepSubtitleAttempt struct {
@@ -137,6 +219,9 @@ type prepSubtitleAttempt struct {
 	QualityNote  string  `json:"quality_note,omitempty"`
 	Accepted     bool    `json:"accepted"`
 	Error        string  `json:"error,omitempty"`
+	TempDir      string  `json:"temp_dir,omitempty"`
+	// Formats lists the --sub-format requests tried, in order (the last one produced the file).
+	Formats []string `json:"formats,omitempty"`
 }
 
 type ytDlpSubtitleMeta struct {
@@ -157,16 +242,40 @@ func parsePrepOptions(args []string) (prepOptions, error) {
 	opts := prepOptions{
 		Lang:          "auto",
 		SubtitleStyle: "clean",
+		Strategy:      "even",
 	}
 
 	var maxClipsProvided bool
 	var clipSecondsProvided bool
+	var skipIntroProvided bool
 
 	for i := 0; i < len(args); i++ {
 		arg := strings.TrimSpace(args[i])
 		switch {
 		case arg == "--json":
 			opts.JSON = true
+		case arg == "--keep-temp":
+			opts.KeepTemp = true
+		case arg == "--single-file":
+			opts.SingleFile = true
+		case arg == "--summary":
+			opts.Summary = true
+		case arg == "--subtitle-segment":
+			if i+1 >= len(args) {
+				return prepOptions{}, fmt.Errorf("`--subtitle-segment` 缺少参数")
+			}
+			i++
+			opts.SubtitleSegment = strings.ToLower(strings.TrimSpace(args[i]))
+		case strings.HasPrefix(arg, "--subtitle-segment="):
+			opts.SubtitleSegment = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--subtitle-segment=")))
+		case arg == "--whisper-device":
+			if i+1 >= len(args) {
+				return prepOptions{}, fmt.Errorf("`--whisper-device` 缺少参数")
+			}
+			i++
+			opts.WhisperDevice = strings.ToLower(strings.TrimSpace(args[i]))
+		case strings.HasPrefix(arg, "--whisper-device="):
+			opts.WhisperDevice = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--whisper-device=")))
 		case arg == "--goal":
 			if i+1 >= len(args) {
 				return prepOptions{}, fmt.Errorf("`--goal` 缺少参数")
@@ -203,6 +312,52 @@ func parsePrepOptions(args []string) (prepOptions, error) {
 			}
 			opts.MaxClips = n
 			maxClipsProvided = true
+		case arg == "--strategy":
+			if i+1 >= len(args) {
+				return prepOptions{}, fmt.Errorf("`--strategy` 缺少参数")
+			}
+			i++
+			opts.Strategy = strings.ToLower(strings.TrimSpace(args[i]))
+		case strings.HasPrefix(arg, "--strategy="):
+			opts.Strategy = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--strategy=")))
+		case arg == "--skip-intro-sec":
+			if i+1 >= len(args) {
+				return prepOptions{}, fmt.Errorf("`--skip-intro-sec` 缺少参数")
+			}
+			i++
+			v := strings.TrimSpace(args[i])
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return prepOptions{}, fmt.Errorf("`--skip-intro-sec` 必须是整数: %s", v)
+			}
+			opts.SkipIntroSec = n
+			skipIntroProvided = true
+		case strings.HasPrefix(arg, "--skip-intro-sec="):
+			v := strings.TrimSpace(strings.TrimPrefix(arg, "--skip-intro-sec="))
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return prepOptions{}, fmt.Errorf("`--skip-intro-sec` 必须是整数: %s", v)
+			}
+			opts.SkipIntroSec = n
+			skipIntroProvided = true
+		case arg == "--min-gap":
+			if i+1 >= len(args) {
+				return prepOptions{}, fmt.Errorf("`--min-gap` 缺少参数")
+			}
+			i++
+			v := strings.TrimSpace(args[i])
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return prepOptions{}, fmt.Errorf("`--min-gap` 必须是数字: %s", v)
+			}
+			opts.MinGapSec = f
+		case strings.HasPrefix(arg, "--min-gap="):
+			v := strings.TrimSpace(strings.TrimPrefix(arg, "--min-gap="))
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return prepOptions{}, fmt.Errorf("`--min-gap` 必须是数字: %s", v)
+			}
+			opts.MinGapSec = f
 		case arg == "--clip-seconds":
 			if i+1 >= len(args) {
 				return prepOptions{}, fmt.Errorf("`--clip-seconds` 缺少参数")
@@ -231,6 +386,30 @@ func parsePrepOptions(args []string) (prepOptions, error) {
 			opts.SubtitleStyle = strings.ToLower(strings.TrimSpace(args[i]))
 		case strings.HasPrefix(arg, "--subtitle-style="):
 			opts.SubtitleStyle = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--subtitle-style=")))
+		case arg == "--aspect":
+			if i+1 >= len(args) {
+				return prepOptions{}, fmt.Errorf("`--aspect` 缺少参数")
+			}
+			i++
+			opts.Aspect = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--aspect="):
+			opts.Aspect = strings.TrimSpace(strings.TrimPrefix(arg, "--aspect="))
+		case arg == "--label-template":
+			if i+1 >= len(args) {
+				return prepOptions{}, fmt.Errorf("`--label-template` 缺少参数")
+			}
+			i++
+			opts.LabelTemplate = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--label-template="):
+			opts.LabelTemplate = strings.TrimSpace(strings.TrimPrefix(arg, "--label-template="))
+		case arg == "--bundle-dir":
+			if i+1 >= len(args) {
+				return prepOptions{}, fmt.Errorf("`--bundle-dir` 缺少参数")
+			}
+			i++
+			opts.BundleDir = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--bundle-dir="):
+			opts.BundleDir = strings.TrimSpace(strings.TrimPrefix(arg, "--bundle-dir="))
 		case strings.HasPrefix(arg, "-"):
 			return prepOptions{}, fmt.Errorf("不支持的参数: %s", arg)
 		default:
@@ -242,19 +421,23 @@ func parsePrepOptions(args []string) (prepOptions, error) {
 	}
 
 	if strings.TrimSpace(opts.AssetRef) == "" {
-		return prepOptions{}, fmt.Errorf("缺少 asset_ref。用法: mingest prep <asset_ref> --goal <subtitle|highlights|shorts>")
+		return prepOptions{}, fmt.Errorf("缺少 asset_ref。用法: mingest prep <asset_ref> --goal <subtitle|highlights|shorts|chapters>")
 	}
 
 	switch opts.Goal {
-	case "subtitle", "highlights", "shorts":
+	case "subtitle", "highlights", "shorts", "chapters":
 	default:
-		return prepOptions{}, fmt.Errorf("`--goal` 仅支持 subtitle|highlights|shorts")
+		return prepOptions{}, fmt.Errorf("`--goal` 仅支持 subtitle|highlights|shorts|chapters")
 	}
 
 	switch opts.Lang {
 	case "auto", "zh", "en":
+	case "all":
+		if opts.Goal == "highlights" {
+			return prepOptions{}, fmt.Errorf("`--lang all` 仅适用于 `--goal subtitle|shorts|chapters`")
+		}
 	default:
-		return prepOptions{}, fmt.Errorf("`--lang` 仅支持 auto|zh|en")
+		return prepOptions{}, fmt.Errorf("`--lang` 仅支持 auto|zh|en|all")
 	}
 
 	switch opts.SubtitleStyle {
@@ -263,6 +446,49 @@ func parsePrepOptions(args []string) (prepOptions, error) {
 		return prepOptions{}, fmt.Errorf("`--subtitle-style` 仅支持 clean|shorts")
 	}
 
+	switch opts.SubtitleSegment {
+	case "":
+		opts.SubtitleSegment = subtitleSegmentOff
+		if opts.SubtitleStyle == "shorts" {
+			opts.SubtitleSegment = subtitleSegmentWords
+		}
+	case subtitleSegmentOff, subtitleSegmentWords, subtitleSegmentSentences:
+	default:
+		return prepOptions{}, fmt.Errorf("`--subtitle-segment` 仅支持 off|words|sentences")
+	}
+
+	if opts.Aspect != "" && !contains(outputAspects, opts.Aspect) {
+		return prepOptions{}, fmt.Errorf("`--aspect` 仅支持 %s", strings.Join(outputAspects, "|"))
+	}
+
+	switch opts.Strategy {
+	case "even", "frontload":
+		if skipIntroProvided {
+			return prepOptions{}, fmt.Errorf("`--skip-intro-sec` 仅在 `--strategy skip-intro` 时有效")
+		}
+	case "skip-intro":
+		if !skipIntroProvided {
+			opts.SkipIntroSec = defaultPrepSkipIntroSec
+		}
+		if opts.SkipIntroSec < 0 {
+			return prepOptions{}, fmt.Errorf("`--skip-intro-sec` 不能为负数")
+		}
+	default:
+		return prepOptions{}, fmt.Errorf("`--strategy` 仅支持 even|frontload|skip-intro")
+	}
+
+	if opts.MinGapSec < 0 || math.IsNaN(opts.MinGapSec) || math.IsInf(opts.MinGapSec, 0) {
+		return prepOptions{}, fmt.Errorf("`--min-gap` 不能为负数")
+	}
+
+	if err := validateClipLabelTemplate(opts.LabelTemplate); err != nil {
+		return prepOptions{}, err
+	}
+
+	if !validWhisperDevice(opts.WhisperDevice) {
+		return prepOptions{}, fmt.Errorf("`--whisper-device` 仅支持 cpu|cuda")
+	}
+
 	if maxClipsProvided && opts.MaxClips <= 0 {
 		return prepOptions{}, fmt.Errorf("`--max-clips` 必须大于 0")
 	}
@@ -270,6 +496,10 @@ func parsePrepOptions(args []string) (prepOptions, error) {
 		return prepOptions{}, fmt.Errorf("`--clip-seconds` 必须大于 0")
 	}
 
+	if v, err := strconv.ParseBool(strings.TrimSpace(os.Getenv("MINGEST_KEEP_TEMP"))); err == nil && v {
+		opts.KeepTemp = true
+	}
+
 	defaultMax, defaultClipSeconds := prepGoalDefaults(opts.Goal)
 	if !maxClipsProvided {
 		opts.MaxClips = defaultMax
@@ -282,29 +512,98 @@ func parsePrepOptions(args []string) (prepOptions, error) {
 }
 
 func runPrep(opts prepOptions) int {
+	result := executePrep(opts)
+	if opts.JSON {
+		printPrepJSON(result)
+		return result.ExitCode
+	}
+	if !result.OK {
+		logError("prep.failed", "exit_code", result.ExitCode, "detail", result.Error)
+		return result.ExitCode
+	}
+
+	fmt.Printf("asset_id: %s\n", result.AssetID)
+	fmt.Printf("asset_path: %s\n", result.AssetPath)
+	fmt.Printf("goal: %s\n", result.Goal)
+	fmt.Printf("duration_sec: %.3f\n", result.DurationSec)
+	fmt.Printf("clip_count: %d\n", result.ClipCount)
+	if result.ColorPrimaries != "" || result.ColorTransfer != "" {
+		fmt.Printf("color: %s/%s/%s\n", firstNonEmpty(result.ColorPrimaries, "-"), firstNonEmpty(result.ColorTransfer, "-"), firstNonEmpty(result.ColorSpace, "-"))
+	}
+	fmt.Printf("bundle_dir: %s\n", result.BundleDir)
+	fmt.Printf("plan_path: %s\n", result.PlanPath)
+	if result.MarkersCSV != "" {
+		fmt.Printf("markers_csv: %s\n", result.MarkersCSV)
+	}
+	if result.ChaptersCSV != "" {
+		fmt.Printf("chapters_csv: %s\n", result.ChaptersCSV)
+	}
+	if result.SubtitlePath != "" {
+		fmt.Printf("subtitle_path: %s\n", result.SubtitlePath)
+	}
+	if result.SubtitleTemplate != "" {
+		fmt.Printf("subtitle_template: %s\n", result.SubtitleTemplate)
+	}
+	if result.SubtitleSource != "" {
+		fmt.Printf("subtitle_source: %s\n", result.SubtitleSource)
+		if result.SubtitleLanguage != "" {
+			fmt.Printf("subtitle_language: %s\n", result.SubtitleLanguage)
+		}
+		if result.SubtitleQualityScore > 0 {
+			fmt.Printf("subtitle_quality_score: %.3f\n", result.SubtitleQualityScore)
+		}
+		if result.SubtitleQualityNote != "" {
+			fmt.Printf("subtitle_quality_note: %s\n", result.SubtitleQualityNote)
+		}
+	}
+	for _, p := range result.SubtitleTracks {
+		fmt.Printf("subtitle_track: %s\n", p)
+	}
+	if s := result.Summary; s != nil {
+		fmt.Printf("summary_covered_sec: %.3f\n", s.CoveredSec)
+		fmt.Printf("summary_coverage_ratio: %.3f\n", s.CoverageRatio)
+		fmt.Printf("summary_clip_sec: min=%.3f mean=%.3f max=%.3f\n", s.MinClipSec, s.MeanClipSec, s.MaxClipSec)
+		if s.ClipCount > 1 {
+			fmt.Printf("summary_gap_sec: min=%.3f mean=%.3f max=%.3f\n", s.MinGapSec, s.MeanGapSec, s.MaxGapSec)
+		}
+	}
+	for _, w := range result.Warnings {
+		fmt.Printf("warning: %s\n", w)
+	}
+	return exitOK
+}
+
+// executePrep builds the prep bundle without printing, so batch runs can reuse it.
+func executePrep(opts prepOptions) prepJSONResult {
 	asset, err := resolvePrepAsset(opts.AssetRef)
 	if err != nil {
-		return prepExitWithErr(opts.JSON, exitDownloadFailed, err.Error())
+		return prepFailure(exitDownloadFailed, err.Error())
 	}
 
 	ffprobePath, err := detectPrepFFprobe()
 	if err != nil {
 		var depErr dependencyError
 		if errors.As(err, &depErr) {
-			return prepExitWithErr(opts.JSON, depErr.ExitCode, depErr.Message)
+			return prepFailure(depErr.ExitCode, depErr.Message)
 		}
-		return prepExitWithErr(opts.JSON, exitDownloadFailed, fmt.Sprintf("依赖检测失败: %v", err))
+		return prepFailure(exitDownloadFailed, fmt.Sprintf("依赖检测失败: %v", err))
 	}
 
 	probe, err := probeMediaFile(ffprobePath, asset.OutputPath)
 	if err != nil {
-		return prepExitWithErr(opts.JSON, exitDownloadFailed, fmt.Sprintf("读取媒体元数据失败: %v", err))
+		return prepFailure(exitDownloadFailed, fmt.Sprintf("读取媒体元数据失败: %v", err))
+	}
+	if probe.AudioOnly {
+		if opts.Goal != "subtitle" && opts.Goal != "chapters" {
+			return prepFailure(exitUsage, fmt.Sprintf("素材没有视频流，`--goal %s` 需要视频；纯音频素材请使用 `--goal subtitle|chapters`", opts.Goal))
+		}
+		logInfo("prep.audio_only", "path", asset.OutputPath, "duration_sec", probe.DurationSec)
 	}
 
 	if strings.TrimSpace(asset.AssetID) == "" {
 		assetID, err := computeAssetID(asset.OutputPath)
 		if err != nil {
-			return prepExitWithErr(opts.JSON, exitDownloadFailed, fmt.Sprintf("生成 asset_id 失败: %v", err))
+			return prepFailure(exitDownloadFailed, fmt.Sprintf("生成 asset_id 失败: %v", err))
 		}
 		asset.AssetID = assetID
 	}
@@ -312,11 +611,25 @@ func runPrep(opts prepOptions) int {
 		asset.Title = filepath.Base(asset.OutputPath)
 	}
 
-	clips := buildPrepClips(probe.DurationSec, opts.MaxClips, opts.ClipSeconds, opts.Goal)
+	var clips []prepClip
+	var warnings []string
+	if opts.Goal != "chapters" {
+		clips = alignPrepClipsToChapters(buildPrepClips(probe.DurationSec, opts), probe.Chapters, probe.DurationSec, opts)
+		applyClipLabelTemplate(clips, opts.LabelTemplate, opts.Goal, asset.Title)
+		if opts.MinGapSec > 0 {
+			// Blame --min-gap only for clips it dropped, not for a cap that a
+			// short source imposes anyway.
+			ungapped := opts
+			ungapped.MinGapSec = 0
+			if n := len(alignPrepClipsToChapters(buildPrepClips(probe.DurationSec, ungapped), probe.Chapters, probe.DurationSec, ungapped)); len(clips) < n {
+				warnings = append(warnings, fmt.Sprintf("在 --min-gap=%gs 约束下仅能放下 %d/%d 个片段", opts.MinGapSec, len(clips), n))
+			}
+		}
+	}
 
-	outputs, err := createPrepBundle(asset.OutputPath, asset.AssetID)
+	outputs, err := createPrepBundle(asset, opts.BundleDir)
 	if err != nil {
-		return prepExitWithErr(opts.JSON, exitDownloadFailed, fmt.Sprintf("创建 prep 输出目录失败: %v", err))
+		return prepFailure(exitDownloadFailed, fmt.Sprintf("创建 prep 输出目录失败: %v", err))
 	}
 	var subtitlePlan *prepSubtitlePlan
 	if opts.Goal == "subtitle" || opts.Goal == "shorts" {
@@ -326,6 +639,34 @@ func runPrep(opts prepOptions) int {
 		if subtitlePlan != nil && strings.TrimSpace(subtitlePlan.SelectedPath) == "" {
 			outputs.SubtitlePath = ""
 		}
+		if subtitlePlan != nil && outputs.SubtitlePath != "" && opts.SubtitleSegment != subtitleSegmentOff {
+			seg, err := applySubtitleSegmentation(subtitlePlan.SelectedPath, opts.SubtitleSegment)
+			if err != nil {
+				logWarn("prep.subtitle_segment_failed", "path", subtitlePlan.SelectedPath, "mode", opts.SubtitleSegment, "error", err)
+				warnings = append(warnings, fmt.Sprintf("字幕分段（%s）失败，保留原字幕: %v", opts.SubtitleSegment, err))
+			} else {
+				subtitlePlan.Segmentation = seg
+				logInfo("prep.subtitle_segmented", "mode", seg.Mode, "cues_before", seg.CuesBefore, "cues_after", seg.CuesAfter)
+			}
+		}
+	}
+	if opts.Goal == "chapters" {
+		outputs.SubtitlePath = filepath.Join(outputs.BundleDir, "subtitle.srt")
+		outputs.ChaptersCSV = filepath.Join(outputs.BundleDir, "chapters.csv")
+		subtitlePlan = runSubtitlePolicy(opts, asset, probe, outputs.SubtitlePath)
+		var cues []subtitleCue
+		if subtitlePlan != nil && strings.TrimSpace(subtitlePlan.SelectedPath) != "" {
+			cues, err = parseSubtitleCues(subtitlePlan.SelectedPath)
+			if err != nil {
+				logWarn("prep.chapters_cues_unreadable", "path", subtitlePlan.SelectedPath, "error", err)
+			}
+		} else {
+			outputs.SubtitlePath = ""
+		}
+		clips = buildPrepChapters(cues, probe.Chapters, probe.DurationSec, opts)
+		if len(cues) == 0 && len(probe.Chapters) == 0 {
+			warnings = append(warnings, "没有可用字幕，章节按时长均分；配置 Whisper 或登录后重试可得到基于话题切换的章节")
+		}
 	}
 
 	planDoc := prepPlan{
@@ -340,94 +681,181 @@ func runPrep(opts prepOptions) int {
 	}
 
 	if err := writePrepPlan(outputs.PlanPath, planDoc); err != nil {
-		return prepExitWithErr(opts.JSON, exitDownloadFailed, fmt.Sprintf("写入 prep-plan.json 失败: %v", err))
+		return prepFailure(exitDownloadFailed, fmt.Sprintf("写入 prep-plan.json 失败: %v", err))
 	}
 	if err := writePrepMarkers(outputs.MarkersCSV, clips); err != nil {
-		return prepExitWithErr(opts.JSON, exitDownloadFailed, fmt.Sprintf("写入 markers.csv 失败: %v", err))
+		return prepFailure(exitDownloadFailed, fmt.Sprintf("写入 markers.csv 失败: %v", err))
+	}
+	if outputs.ChaptersCSV != "" {
+		if err := writePrepChapters(outputs.ChaptersCSV, clips, probe.DurationSec); err != nil {
+			return prepFailure(exitDownloadFailed, fmt.Sprintf("写入 chapters.csv 失败: %v", err))
+		}
 	}
 	if outputs.SubtitleTemplate != "" {
 		if err := writeSubtitleTemplate(outputs.SubtitleTemplate, clips, opts.SubtitleStyle, opts.Lang); err != nil {
-			return prepExitWithErr(opts.JSON, exitDownloadFailed, fmt.Sprintf("写入 subtitle-template.srt 失败: %v", err))
+			return prepFailure(exitDownloadFailed, fmt.Sprintf("写入 subtitle-template.srt 失败: %v", err))
 		}
 	}
-
-	if opts.JSON {
-		jsonResult := prepJSONResult{
-			OK:               true,
-			ExitCode:         exitOK,
-			AssetID:          asset.AssetID,
-			AssetPath:        asset.OutputPath,
-			Goal:             opts.Goal,
-			DurationSec:      roundMillis(probe.DurationSec),
-			ClipCount:        len(clips),
-			BundleDir:        outputs.BundleDir,
-			PlanPath:         outputs.PlanPath,
-			MarkersCSV:       outputs.MarkersCSV,
-			SubtitlePath:     outputs.SubtitlePath,
-			SubtitleTemplate: outputs.SubtitleTemplate,
-		}
-		if subtitlePlan != nil {
-			jsonResult.SubtitleSource = subtitlePlan.SelectedSource
-			jsonResult.SubtitleLanguage = subtitlePlan.SelectedLanguage
-			jsonResult.SubtitleQualityScore = roundMillis(subtitlePlan.QualityScore)
-			jsonResult.SubtitleQualityNote = subtitlePlan.QualityNote
-		}
-		printPrepJSON(jsonResult)
-		return exitOK
-	}
-
-	fmt.Printf("asset_id: %s\n", asset.AssetID)
-	fmt.Printf("asset_path: %s\n", asset.OutputPath)
-	fmt.Printf("goal: %s\n", opts.Goal)
-	fmt.Printf("duration_sec: %.3f\n", roundMillis(probe.DurationSec))
-	fmt.Printf("clip_count: %d\n", len(clips))
-	fmt.Printf("bundle_dir: %s\n", outputs.BundleDir)
-	fmt.Printf("plan_path: %s\n", outputs.PlanPath)
-	fmt.Printf("markers_csv: %s\n", outputs.MarkersCSV)
-	if outputs.SubtitlePath != "" {
-		fmt.Printf("subtitle_path: %s\n", outputs.SubtitlePath)
+	if opts.SingleFile {
+		if err := embedPrepOutputs(&planDoc); err != nil {
+			return prepFailure(exitDownloadFailed, fmt.Sprintf("生成单文件 prep-plan.json 失败: %v", err))
+		}
 	}
-	if outputs.SubtitleTemplate != "" {
-		fmt.Printf("subtitle_template: %s\n", outputs.SubtitleTemplate)
+
+	result := prepJSONResult{
+		OK:               true,
+		ExitCode:         exitOK,
+		AssetID:          asset.AssetID,
+		AssetPath:        asset.OutputPath,
+		Goal:             opts.Goal,
+		DurationSec:      roundMillis(probe.DurationSec),
+		ClipCount:        len(clips),
+		BundleDir:        outputs.BundleDir,
+		PlanPath:         outputs.PlanPath,
+		MarkersCSV:       outputs.MarkersCSV,
+		ChaptersCSV:      outputs.ChaptersCSV,
+		SubtitlePath:     outputs.SubtitlePath,
+		SubtitleTemplate: outputs.SubtitleTemplate,
+		ColorPrimaries:   probe.ColorPrimaries,
+		ColorTransfer:    probe.ColorTransfer,
+		ColorSpace:       probe.ColorSpace,
+		Warnings:         warnings,
+	}
+	if opts.Summary {
+		result.Summary = summarizePrepClips(clips, probe.DurationSec)
+	}
+	if opts.SingleFile {
+		// Contents live in prep-plan.json under "embedded".
+		result.MarkersCSV = ""
+		result.ChaptersCSV = ""
+		result.SubtitlePath = ""
+		result.SubtitleTemplate = ""
 	}
 	if subtitlePlan != nil {
-		fmt.Printf("subtitle_source: %s\n", subtitlePlan.SelectedSource)
-		if subtitlePlan.SelectedLanguage != "" {
-			fmt.Printf("subtitle_language: %s\n", subtitlePlan.SelectedLanguage)
+		result.SubtitleSource = subtitlePlan.SelectedSource
+		result.SubtitleLanguage = subtitlePlan.SelectedLanguage
+		result.SubtitleQualityScore = roundMillis(subtitlePlan.QualityScore)
+		result.SubtitleQualityNote = subtitlePlan.QualityNote
+		for _, track := range subtitlePlan.Tracks {
+			if track.Path != "" {
+				result.SubtitleTracks = append(result.SubtitleTracks, track.Path)
+			}
+		}
+	}
+	return result
+}
+
+// summarizePrepClips reports coverage (overlaps counted once), clip lengths and the
+// gaps between consecutive clips. Overlapping neighbours count as a zero gap.
+func summarizePrepClips(clips []prepClip, durationSec float64) *prepClipSummary {
+	s := &prepClipSummary{ClipCount: len(clips)}
+	if len(clips) == 0 {
+		return s
+	}
+
+	sorted := append([]prepClip(nil), clips...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].StartSec < sorted[j].StartSec })
+
+	total := 0.0
+	s.MinClipSec = math.Inf(1)
+	coveredEnd := math.Inf(-1)
+	for i, c := range sorted {
+		length := math.Max(c.EndSec-c.StartSec, 0)
+		total += length
+		s.MinClipSec = math.Min(s.MinClipSec, length)
+		s.MaxClipSec = math.Max(s.MaxClipSec, length)
+
+		start := math.Max(c.StartSec, coveredEnd)
+		if c.EndSec > start {
+			s.CoveredSec += c.EndSec - start
 		}
-		if subtitlePlan.QualityScore > 0 {
-			fmt.Printf("subtitle_quality_score: %.3f\n", roundMillis(subtitlePlan.QualityScore))
+		coveredEnd = math.Max(coveredEnd, c.EndSec)
+
+		if i == 0 {
+			continue
 		}
-		if subtitlePlan.QualityNote != "" {
-			fmt.Printf("subtitle_quality_note: %s\n", subtitlePlan.QualityNote)
+		gap := math.Max(c.StartSec-sorted[i-1].EndSec, 0)
+		if i == 1 {
+			s.MinGapSec = gap
 		}
+		s.MinGapSec = math.Min(s.MinGapSec, gap)
+		s.MaxGapSec = math.Max(s.MaxGapSec, gap)
+		s.MeanGapSec += gap
+	}
+	s.MeanClipSec = total / float64(len(sorted))
+	if len(sorted) > 1 {
+		s.MeanGapSec /= float64(len(sorted) - 1)
+	}
+	if durationSec > 0 {
+		s.CoverageRatio = math.Min(s.CoveredSec/durationSec, 1)
+	}
+
+	s.CoveredSec = roundMillis(s.CoveredSec)
+	s.CoverageRatio = roundMillis(s.CoverageRatio)
+	s.MinClipSec = roundMillis(s.MinClipSec)
+	s.MeanClipSec = roundMillis(s.MeanClipSec)
+	s.MaxClipSec = roundMillis(s.MaxClipSec)
+	s.MinGapSec = roundMillis(s.MinGapSec)
+	s.MeanGapSec = roundMillis(s.MeanGapSec)
+	s.MaxGapSec = roundMillis(s.MaxGapSec)
+	return s
+}
+
+func prepFailure(exitCode int, msg string) prepJSONResult {
+	return prepJSONResult{
+		OK:       false,
+		ExitCode: exitCode,
+		Error:    msg,
 	}
-	return exitOK
 }
 
-func prepExitWithErr(asJSON bool, exitCode int, msg string) int {
-	if asJSON {
-		printPrepJSON(prepJSONResult{
-			OK:       false,
-			ExitCode: exitCode,
-			Error:    msg,
-		})
-	} else {
-		logError("prep.failed", "exit_code", exitCode, "detail", msg)
+// outputAspects are the aspect ratios accepted by --aspect.
+var outputAspects = []string{"16:9", "9:16", "1:1", "4:5"}
+
+// aspectFrameSize returns the delivery frame size for an --aspect value.
+func aspectFrameSize(aspect string) (int, int, bool) {
+	switch aspect {
+	case "16:9":
+		return 1920, 1080, true
+	case "9:16":
+		return 1080, 1920, true
+	case "1:1":
+		return 1080, 1080, true
+	case "4:5":
+		return 1080, 1350, true
+	}
+	return 0, 0, false
+}
+
+// aspectCropKeep returns the fraction of a srcW x srcH frame that survives a
+// center crop to aspect; 1 means nothing is cut (or the size is unknown).
+func aspectCropKeep(aspect string, srcW, srcH int) float64 {
+	w, h, ok := aspectFrameSize(aspect)
+	if !ok || srcW <= 0 || srcH <= 0 {
+		return 1
 	}
-	return exitCode
+	src := float64(srcW) / float64(srcH)
+	dst := float64(w) / float64(h)
+	if src > dst {
+		return dst / src
+	}
+	return src / dst
 }
 
 func prepGoalDefaults(goal string) (maxClips int, clipSeconds int) {
 	switch goal {
 	case "shorts":
 		return 3, 30
+	case "chapters":
+		// For chapters: at most 12 chapters, each at least 60s.
+		return 12, 60
 	default:
 		return 5, 45
 	}
 }
 
 func runSubtitlePolicy(opts prepOptions, asset prepResolvedAsset, probe mediaProbe, subtitleOutPath string) *prepSubtitlePlan {
+	lang := prepSelectionLang(opts.Lang)
 	plan := &prepSubtitlePlan{
 		Policy:           "platform_manual->platform_auto->whisper",
 		QualityThreshold: prepSubtitleQualityThreshold,
@@ -459,14 +887,17 @@ func runSubtitlePolicy(opts prepOptions, asset prepResolvedAsset, probe mediaPro
 					prepSubtitleAttempt{Source: "platform_auto", Error: msg},
 				)
 			} else {
-				manualAttempt := runPlatformSubtitleAttempt("platform_manual", false, depsFound, videoURL, cookieFile, meta.Subtitles, opts.Lang, probe.DurationSec, subtitleOutPath, prepSubtitleQualityThreshold)
+				if opts.Lang == "all" {
+					plan.Tracks = downloadAllPlatformSubtitleTracks(depsFound, videoURL, cookieFile, meta.Subtitles, filepath.Dir(subtitleOutPath), opts.KeepTemp)
+				}
+				manualAttempt := runPlatformSubtitleAttempt("platform_manual", false, depsFound, videoURL, cookieFile, meta.Subtitles, lang, probe.DurationSec, subtitleOutPath, prepSubtitleQualityThreshold, opts.KeepTemp)
 				plan.Attempts = append(plan.Attempts, manualAttempt)
 				if manualAttempt.Accepted {
 					applySelectedSubtitleAttempt(plan, manualAttempt)
 					return plan
 				}
 
-				autoAttempt := runPlatformSubtitleAttempt("platform_auto", true, depsFound, videoURL, cookieFile, meta.AutomaticCaptions, opts.Lang, probe.DurationSec, subtitleOutPath, prepSubtitleQualityThreshold)
+				autoAttempt := runPlatformSubtitleAttempt("platform_auto", true, depsFound, videoURL, cookieFile, meta.AutomaticCaptions, lang, probe.DurationSec, subtitleOutPath, prepSubtitleQualityThreshold, opts.KeepTemp)
 				plan.Attempts = append(plan.Attempts, autoAttempt)
 				if autoAttempt.Accepted {
 					applySelectedSubtitleAttempt(plan, autoAttempt)
@@ -496,7 +927,7 @@ func applySelectedSubtitleAttempt(plan *prepSubtitlePlan, attempt prepSubtitleAt
 	plan.SelectedPath = attempt.OutputPath
 }
 
-func runPlatformSubtitleAttempt(source string, automatic bool, d deps, videoURL, cookieFile string, tracks map[string]interface{}, lang string, mediaDurationSec float64, subtitleOutPath string, minScore float64) prepSubtitleAttempt {
+func runPlatformSubtitleAttempt(source string, automatic bool, d deps, videoURL, cookieFile string, tracks map[string]interface{}, lang string, mediaDurationSec float64, subtitleOutPath string, minScore float64, keepTemp bool) prepSubtitleAttempt {
 	attempt := prepSubtitleAttempt{Source: source}
 
 	langCode, ok := extractPreferredLanguageCode(tracks, lang)
@@ -511,9 +942,15 @@ func runPlatformSubtitleAttempt(source string, automatic bool, d deps, videoURL,
 		attempt.Error = fmt.Sprintf("创建临时目录失败: %v", err)
 		return attempt
 	}
-	defer os.RemoveAll(tempDir)
+	if keepTemp {
+		attempt.TempDir = tempDir
+		logInfo("prep.temp_kept", "source", source, "dir", tempDir)
+	} else {
+		defer os.RemoveAll(tempDir)
+	}
 
-	subPath, err := downloadYtDlpSubtitleTrack(d, videoURL, cookieFile, automatic, langCode, tempDir)
+	subPath, formats, err := downloadYtDlpSubtitleTrack(d, videoURL, cookieFile, automatic, langCode, subtitleTrackFormats(tracks, langCode), tempDir)
+	attempt.Formats = formats
 	if err != nil {
 		attempt.Error = err.Error()
 		return attempt
@@ -544,12 +981,12 @@ func runPlatformSubtitleAttempt(source string, automatic bool, d deps, videoURL,
 func runWhisperSubtitleAttempt(opts prepOptions, mediaPath string, mediaDurationSec float64, subtitleOutPath string, minScore float64) prepSubtitleAttempt {
 	attempt := prepSubtitleAttempt{
 		Source:   "whisper",
-		Language: opts.Lang,
+		Language: prepSelectionLang(opts.Lang),
 	}
 
 	whisperPath, ok := detectWhisperBinary()
 	if !ok {
-		attempt.Error = "未找到 whisper CLI，无法执行本地转写回退"
+		attempt.Error = fmt.Sprintf("未找到 whisper CLI（后端 %s），无法执行本地转写回退", whisperBackend())
 		return attempt
 	}
 
@@ -558,9 +995,18 @@ func runWhisperSubtitleAttempt(opts prepOptions, mediaPath string, mediaDuration
 		attempt.Error = fmt.Sprintf("创建临时目录失败: %v", err)
 		return attempt
 	}
-	defer os.RemoveAll(tempDir)
+	if opts.KeepTemp {
+		attempt.TempDir = tempDir
+		logInfo("prep.temp_kept", "source", attempt.Source, "dir", tempDir)
+	} else {
+		defer os.RemoveAll(tempDir)
+	}
 
-	subPath, err := runWhisperTranscribe(whisperPath, mediaPath, opts.Lang, tempDir)
+	var progress io.Writer
+	if !opts.JSON {
+		progress = statusWriter()
+	}
+	subPath, _, err := runWhisperTranscribe(whisperPath, mediaPath, attempt.Language, "", opts.WhisperDevice, tempDir, progress)
 	if err != nil {
 		attempt.Error = err.Error()
 		return attempt
@@ -610,6 +1056,22 @@ func prepPlatformForAsset(asset prepResolvedAsset, rawURL string) (videoPlatform
 }
 
 func fetchYtDlpSubtitleMeta(d deps, videoURL, cookieFile string) (ytDlpSubtitleMeta, error) {
+	out, err := fetchYtDlpInfoJSON(d, videoURL, cookieFile, 0)
+	if err != nil {
+		return ytDlpSubtitleMeta{}, err
+	}
+
+	var meta ytDlpSubtitleMeta
+	if err := json.Unmarshal([]byte(out), &meta); err != nil {
+		return ytDlpSubtitleMeta{}, fmt.Errorf("解析字幕元信息失败: %w", err)
+	}
+	return meta, nil
+}
+
+// fetchYtDlpInfoJSON returns yt-dlp's --dump-single-json output for videoURL
+// without downloading. timeout <= 0 means no limit. extraArgs go right before
+// the URL, so they override the base args (e.g. a per-run --proxy).
+func fetchYtDlpInfoJSON(d deps, videoURL, cookieFile string, timeout time.Duration, extraArgs ...string) (string, error) {
 	args := prepYtDlpBaseArgs(d)
 	args = append(args,
 		"--dump-single-json",
@@ -620,40 +1082,131 @@ func fetchYtDlpSubtitleMeta(d deps, videoURL, cookieFile string) (ytDlpSubtitleM
 	if strings.TrimSpace(cookieFile) != "" {
 		args = append(args, "--cookies", cookieFile)
 	}
+	args = append(args, extraArgs...)
 	args = append(args, videoURL)
 
-	stdout, stderr, err := runYtDlpQuiet(d, args)
+	stdout, stderr, err := runYtDlpQuiet(d, args, timeout)
 	if err != nil {
 		detail := strings.TrimSpace(stderr)
-		if detail == "" {
+		if detail == "" || errors.Is(err, context.DeadlineExceeded) {
 			detail = err.Error()
 		}
-		return ytDlpSubtitleMeta{}, fmt.Errorf("yt-dlp 拉取字幕元信息失败: %s", detail)
+		return "", fmt.Errorf("yt-dlp 拉取元信息失败: %s", detail)
 	}
 
 	out := strings.TrimSpace(stdout)
 	if out == "" {
-		return ytDlpSubtitleMeta{}, fmt.Errorf("yt-dlp 字幕元信息为空")
+		return "", fmt.Errorf("yt-dlp 元信息为空")
 	}
+	return out, nil
+}
 
-	var meta ytDlpSubtitleMeta
-	if err := json.Unmarshal([]byte(out), &meta); err != nil {
-		return ytDlpSubtitleMeta{}, fmt.Errorf("解析字幕元信息失败: %w", err)
+// prepSelectionLang maps `--lang all` to auto for picking the primary subtitle;
+// the extra tracks are fetched separately.
+func prepSelectionLang(lang string) string {
+	if lang == "all" {
+		return "auto"
 	}
-	return meta, nil
+	return lang
+}
+
+// downloadAllPlatformSubtitleTracks saves every manual track (live_chat
+// excluded) as subtitle.<lang>.srt in bundleDir. Failures are recorded per
+// track and never abort prep.
+func downloadAllPlatformSubtitleTracks(d deps, videoURL, cookieFile string, tracks map[string]interface{}, bundleDir string, keepTemp bool) []prepSubtitleTrack {
+	langs := make([]string, 0, len(tracks))
+	for raw := range tracks {
+		code := strings.TrimSpace(raw)
+		if code == "" || strings.Contains(normalizeLangCode(code), "live_chat") {
+			continue
+		}
+		langs = append(langs, code)
+	}
+	sort.Strings(langs)
+
+	out := make([]prepSubtitleTrack, 0, len(langs))
+	for _, code := range langs {
+		track := prepSubtitleTrack{Language: code}
+		tempDir, err := os.MkdirTemp("", "mingest-prep-platform-sub-*")
+		if err != nil {
+			track.Error = fmt.Sprintf("创建临时目录失败: %v", err)
+			out = append(out, track)
+			continue
+		}
+		subPath, _, err := downloadYtDlpSubtitleTrack(d, videoURL, cookieFile, false, code, subtitleTrackFormats(tracks, code), tempDir)
+		if err == nil {
+			target := filepath.Join(bundleDir, "subtitle."+sanitizeFileName(code)+".srt")
+			if err = copySubtitleFile(subPath, target); err == nil {
+				track.Path = target
+			} else {
+				err = fmt.Errorf("写入字幕文件失败: %w", err)
+			}
+		}
+		if err != nil {
+			track.Error = err.Error()
+			logWarn("prep.subtitle_track_failed", "lang", code, "error", err)
+		}
+		if keepTemp {
+			logInfo("prep.temp_kept", "source", "platform_manual:"+code, "dir", tempDir)
+		} else {
+			os.RemoveAll(tempDir)
+		}
+		out = append(out, track)
+	}
+	logInfo("prep.subtitle_tracks", "total", len(out))
+	return out
+}
+
+// prepSubtitleDefaultFormat is the first --sub-format request; yt-dlp converts it to srt.
+const prepSubtitleDefaultFormat = "srt/vtt/best"
+
+// prepSubtitleNativeFormats are platform-native formats we can convert ourselves,
+// in order of preference, when yt-dlp's own conversion yields nothing.
+var prepSubtitleNativeFormats = []string{"json3", "srv3", "srv2", "srv1", "json"}
+
+// downloadYtDlpSubtitleTrack fetches one track as srt. If yt-dlp's conversion fails or
+// leaves no file, it retries once with a native format offered by the track (json3/srv*)
+// and converts that in-process. The returned formats list every request made.
+func downloadYtDlpSubtitleTrack(d deps, videoURL, cookieFile string, automatic bool, langCode string, available []string, outDir string) (string, []string, error) {
+	formats := []string{prepSubtitleDefaultFormat}
+	path, err := fetchYtDlpSubtitle(d, videoURL, cookieFile, automatic, langCode, prepSubtitleDefaultFormat, outDir)
+	if err == nil {
+		return path, formats, nil
+	}
+
+	native := pickNativeSubtitleFormat(available)
+	if native == "" {
+		return "", formats, err
+	}
+	logWarn("prep.subtitle_format_fallback", "lang", langCode, "format", native, "error", err)
+	formats = append(formats, native)
+
+	rawPath, nativeErr := fetchYtDlpSubtitle(d, videoURL, cookieFile, automatic, langCode, native, outDir)
+	if nativeErr != nil {
+		return "", formats, fmt.Errorf("%v；改用 %s 仍失败: %v", err, native, nativeErr)
+	}
+	srtPath := strings.TrimSuffix(rawPath, filepath.Ext(rawPath)) + ".srt"
+	if convErr := convertNativeSubtitleToSRT(rawPath, native, srtPath); convErr != nil {
+		return "", formats, fmt.Errorf("%v；%s 字幕转换失败: %v", err, native, convErr)
+	}
+	return srtPath, formats, nil
 }
 
-func downloadYtDlpSubtitleTrack(d deps, videoURL, cookieFile string, automatic bool, langCode string, outDir string) (string, error) {
+// fetchYtDlpSubtitle runs one yt-dlp subtitle download. The default format is
+// converted to srt by yt-dlp; native formats are saved as-is for our converter.
+func fetchYtDlpSubtitle(d deps, videoURL, cookieFile string, automatic bool, langCode, format, outDir string) (string, error) {
 	args := prepYtDlpBaseArgs(d)
 	args = append(args,
 		"--skip-download",
 		"--no-warnings",
 		"--no-playlist",
 		"--sub-langs", langCode,
-		"--sub-format", "srt/vtt/best",
-		"--convert-subs", "srt",
-		"--output", filepath.Join(outDir, "platform-%(id)s.%(ext)s"),
+		"--sub-format", format,
 	)
+	if format == prepSubtitleDefaultFormat {
+		args = append(args, "--convert-subs", "srt")
+	}
+	args = append(args, "--output", filepath.Join(outDir, "platform-%(id)s.%(ext)s"))
 	if automatic {
 		args = append(args, "--write-auto-sub")
 	} else {
@@ -664,7 +1217,7 @@ func downloadYtDlpSubtitleTrack(d deps, videoURL, cookieFile string, automatic b
 	}
 	args = append(args, videoURL)
 
-	_, stderr, err := runYtDlpQuiet(d, args)
+	_, stderr, err := runYtDlpQuiet(d, args, 0)
 	if err != nil {
 		detail := strings.TrimSpace(stderr)
 		if detail == "" {
@@ -673,11 +1226,186 @@ func downloadYtDlpSubtitleTrack(d deps, videoURL, cookieFile string, automatic b
 		return "", fmt.Errorf("下载平台字幕失败: %s", detail)
 	}
 
-	path, err := findLatestSubtitleFile(outDir)
+	if format != prepSubtitleDefaultFormat {
+		matches, _ := filepath.Glob(filepath.Join(outDir, "*."+format))
+		if len(matches) == 0 {
+			return "", fmt.Errorf("未找到 %s 字幕文件", format)
+		}
+		sort.Strings(matches)
+		return matches[0], nil
+	}
+	return findLatestSubtitleFile(outDir)
+}
+
+// subtitleTrackFormats returns the `ext` values yt-dlp lists for one track.
+func subtitleTrackFormats(tracks map[string]interface{}, langCode string) []string {
+	entries, ok := tracks[langCode].([]interface{})
+	if !ok {
+		return nil
+	}
+	out := make([]string, 0, len(entries))
+	for _, e := range entries {
+		m, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if ext, ok := m["ext"].(string); ok && strings.TrimSpace(ext) != "" {
+			out = append(out, strings.ToLower(strings.TrimSpace(ext)))
+		}
+	}
+	return out
+}
+
+func pickNativeSubtitleFormat(available []string) string {
+	for _, want := range prepSubtitleNativeFormats {
+		if contains(available, want) {
+			return want
+		}
+	}
+	return ""
+}
+
+// convertNativeSubtitleToSRT converts YouTube json3/srv1-3 or Bilibili json subtitles to srt.
+func convertNativeSubtitleToSRT(srcPath, format, dstPath string) error {
+	b, err := os.ReadFile(srcPath)
 	if err != nil {
-		return "", err
+		return err
+	}
+	var cues []subtitleCue
+	switch format {
+	case "json3":
+		cues, err = parseJSON3Subtitle(b)
+	case "json":
+		cues, err = parseBilibiliJSONSubtitle(b)
+	default:
+		cues, err = parseTimedTextXMLSubtitle(b)
+	}
+	if err != nil {
+		return err
+	}
+	if len(cues) == 0 {
+		return fmt.Errorf("字幕内容为空")
+	}
+	return os.WriteFile(dstPath, []byte(formatSRTCues(cues)), 0o644)
+}
+
+func parseJSON3Subtitle(b []byte) ([]subtitleCue, error) {
+	var doc struct {
+		Events []struct {
+			StartMs    float64 `json:"tStartMs"`
+			DurationMs float64 `json:"dDurationMs"`
+			Segs       []struct {
+				UTF8 string `json:"utf8"`
+			} `json:"segs"`
+		} `json:"events"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	cues := make([]subtitleCue, 0, len(doc.Events))
+	for _, ev := range doc.Events {
+		var text strings.Builder
+		for _, seg := range ev.Segs {
+			text.WriteString(seg.UTF8)
+		}
+		cue := subtitleCue{
+			StartSec: ev.StartMs / 1000,
+			EndSec:   (ev.StartMs + ev.DurationMs) / 1000,
+			Text:     strings.TrimSpace(text.String()),
+		}
+		if cue.Text == "" || cue.EndSec <= cue.StartSec {
+			continue
+		}
+		cues = append(cues, cue)
+	}
+	return cues, nil
+}
+
+func parseBilibiliJSONSubtitle(b []byte) ([]subtitleCue, error) {
+	var doc struct {
+		Body []struct {
+			From    float64 `json:"from"`
+			To      float64 `json:"to"`
+			Content string  `json:"content"`
+		} `json:"body"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	cues := make([]subtitleCue, 0, len(doc.Body))
+	for _, item := range doc.Body {
+		text := strings.TrimSpace(item.Content)
+		if text == "" || item.To <= item.From {
+			continue
+		}
+		cues = append(cues, subtitleCue{StartSec: item.From, EndSec: item.To, Text: text})
+	}
+	return cues, nil
+}
+
+// parseTimedTextXMLSubtitle handles srv3/srv2 (<p t="ms" d="ms">) and srv1 (<text start="s" dur="s">).
+func parseTimedTextXMLSubtitle(b []byte) ([]subtitleCue, error) {
+	dec := xml.NewDecoder(bytes.NewReader(b))
+	var cues []subtitleCue
+	var cur *subtitleCue
+	var text strings.Builder
+	attr := func(el xml.StartElement, name string) (float64, bool) {
+		for _, a := range el.Attr {
+			if a.Name.Local == name {
+				v, err := strconv.ParseFloat(strings.TrimSpace(a.Value), 64)
+				return v, err == nil
+			}
+		}
+		return 0, false
+	}
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			switch el.Name.Local {
+			case "p":
+				start, ok := attr(el, "t")
+				if !ok {
+					continue
+				}
+				dur, _ := attr(el, "d")
+				cur = &subtitleCue{StartSec: start / 1000, EndSec: (start + dur) / 1000}
+				text.Reset()
+			case "text":
+				start, ok := attr(el, "start")
+				if !ok {
+					continue
+				}
+				dur, _ := attr(el, "dur")
+				cur = &subtitleCue{StartSec: start, EndSec: start + dur}
+				text.Reset()
+			case "br":
+				if cur != nil {
+					text.WriteByte('\n')
+				}
+			}
+		case xml.CharData:
+			if cur != nil {
+				text.Write(el)
+			}
+		case xml.EndElement:
+			if cur != nil && (el.Name.Local == "p" || el.Name.Local == "text") {
+				// srv1 text is HTML-escaped inside the XML escaping.
+				cur.Text = strings.TrimSpace(html.UnescapeString(text.String()))
+				if cur.Text != "" && cur.EndSec > cur.StartSec {
+					cues = append(cues, *cur)
+				}
+				cur = nil
+			}
+		}
 	}
-	return path, nil
+	return cues, nil
 }
 
 func prepYtDlpBaseArgs(d deps) []string {
@@ -688,22 +1416,50 @@ func prepYtDlpBaseArgs(d deps) []string {
 	if runtime.GOOS == "windows" {
 		args = append(args, "--encoding", "utf-8")
 	}
+	proxy, err := resolveProxy("")
+	if err != nil {
+		logWarn("prep.proxy_invalid", "error", err)
+	} else if proxy != "" {
+		args = append(args, "--proxy", proxy)
+	}
 	return args
 }
 
-func runYtDlpQuiet(d deps, args []string) (stdout string, stderr string, err error) {
-	cmd := exec.Command(d.YtDlp.Path, args...)
+// runYtDlpQuiet runs yt-dlp with captured output. Like runYtDlpClassified it
+// runs in its own process group, registered for signal cleanup, and is killed
+// with its helpers once timeout (if > 0) elapses.
+func runYtDlpQuiet(d deps, args []string, timeout time.Duration) (stdout string, stderr string, err error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, d.YtDlp.Path, args...)
 	env := withPrependedPath(os.Environ(), filepath.Dir(d.JSRuntime.Path))
 	env = withEnvVar(env, "PYTHONUTF8", "1")
 	env = withEnvVar(env, "PYTHONIOENCODING", "utf-8")
 	cmd.Env = env
+	cmd.SysProcAttr = newProcessGroupAttr()
+	cmd.Cancel = func() error { return killProcessTree(cmd.Process) }
+	// Orphaned helpers may still hold the pipes; don't let them block us.
+	cmd.WaitDelay = 5 * time.Second
 
 	var outBuf bytes.Buffer
 	var errBuf bytes.Buffer
 	cmd.Stdout = &outBuf
 	cmd.Stderr = &errBuf
-	runErr := cmd.Run()
+	if err := cmd.Start(); err != nil {
+		return "", "", err
+	}
+	unregister := registerCleanup(func() { _ = killProcessTree(cmd.Process) })
+	runErr := cmd.Wait()
+	unregister()
 
+	if ctx.Err() == context.DeadlineExceeded {
+		logWarn("yt_dlp.timed_out", "timeout", timeout.String())
+		return outBuf.String(), errBuf.String(), fmt.Errorf("%w (%s)", context.DeadlineExceeded, timeout)
+	}
 	if runErr != nil {
 		return outBuf.String(), errBuf.String(), runErr
 	}
@@ -786,50 +1542,265 @@ func normalizeLangCode(v string) string {
 	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(v), "_", "-"))
 }
 
+// iso639TwoLetter maps common ISO 639-2/3 codes (as found in container tags) to ISO 639-1.
+var iso639TwoLetter = map[string]string{
+	"eng": "en", "chi": "zh", "zho": "zh", "cmn": "zh", "yue": "zh",
+	"jpn": "ja", "kor": "ko", "fra": "fr", "fre": "fr", "deu": "de",
+	"ger": "de", "spa": "es", "rus": "ru", "por": "pt", "ita": "it",
+}
+
+// baseLanguage reduces a language tag (zh-Hans, en_US, eng) to its primary
+// ISO 639-1 subtag; unknown/undetermined tags return "".
+func baseLanguage(v string) string {
+	code := normalizeLangCode(v)
+	if i := strings.Index(code, "-"); i >= 0 {
+		code = code[:i]
+	}
+	switch code {
+	case "", "und", "unk", "mul", "zxx":
+		return ""
+	}
+	if mapped, ok := iso639TwoLetter[code]; ok {
+		return mapped
+	}
+	return code
+}
+
+// Local transcription backends selected by MINGEST_WHISPER_BACKEND. They
+// differ in binary names and flags, not in what they produce (an SRT file).
+const (
+	whisperBackendOpenAI = "openai"
+	whisperBackendFaster = "faster"
+	whisperBackendCpp    = "cpp"
+)
+
+// whisperBackend reads MINGEST_WHISPER_BACKEND, defaulting to the openai CLI.
+func whisperBackend() string {
+	raw := strings.ToLower(strings.TrimSpace(os.Getenv("MINGEST_WHISPER_BACKEND")))
+	switch raw {
+	case "", whisperBackendOpenAI:
+		return whisperBackendOpenAI
+	case whisperBackendFaster, whisperBackendCpp:
+		return raw
+	default:
+		logWarn("prep.whisper_backend_invalid", "env", "MINGEST_WHISPER_BACKEND", "value", raw, "fallback", whisperBackendOpenAI)
+		return whisperBackendOpenAI
+	}
+}
+
+// whisperBinaryNames lists the executables each backend ships under, in
+// lookup order.
+func whisperBinaryNames(backend string) []string {
+	switch backend {
+	case whisperBackendFaster:
+		// whisper-ctranslate2 is pip's faster-whisper CLI; faster-whisper(-xxl)
+		// is the standalone build.
+		return []string{"whisper-ctranslate2", "faster-whisper", "faster-whisper-xxl"}
+	case whisperBackendCpp:
+		// whisper.cpp renamed its CLI from main to whisper-cli.
+		return []string{"whisper-cli", "whisper-cpp", "whisper.cpp"}
+	default:
+		return []string{"whisper"}
+	}
+}
+
+// Whisper compute devices accepted by --whisper-device and
+// MINGEST_WHISPER_DEVICE. Unset keeps each backend's own default.
+const (
+	whisperDeviceCPU  = "cpu"
+	whisperDeviceCUDA = "cuda"
+)
+
+func validWhisperDevice(v string) bool {
+	switch v {
+	case "", whisperDeviceCPU, whisperDeviceCUDA:
+		return true
+	default:
+		return false
+	}
+}
+
+// whisperDevice returns the flag value, else MINGEST_WHISPER_DEVICE. An
+// invalid env value is ignored with a warning, like MINGEST_WHISPER_BACKEND.
+func whisperDevice(flagValue string) string {
+	if v := strings.ToLower(strings.TrimSpace(flagValue)); v != "" {
+		return v
+	}
+	raw := strings.ToLower(strings.TrimSpace(os.Getenv("MINGEST_WHISPER_DEVICE")))
+	if !validWhisperDevice(raw) {
+		logWarn("prep.whisper_device_invalid", "env", "MINGEST_WHISPER_DEVICE", "value", raw)
+		return ""
+	}
+	return raw
+}
+
 func detectWhisperBinary() (string, bool) {
 	if p := strings.TrimSpace(os.Getenv("MINGEST_WHISPER_PATH")); p != "" && isRunnableFile(p) {
 		return p, true
 	}
 	exeDir, _ := executableDir()
 	wd, _ := os.Getwd()
-	return findBinary("whisper", wd, exeDir)
+	for _, name := range whisperBinaryNames(whisperBackend()) {
+		if p, ok := findBinary(name, wd, exeDir); ok {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+// whisperDetectedLangRE matches the line each backend prints when it picks
+// the language itself: "Detected language: Chinese" (openai),
+// "Detected language 'zh' with probability 0.98" (faster) and
+// "auto-detected language: zh (p = 0.98)" (cpp).
+var whisperDetectedLangRE = regexp.MustCompile(`(?i)detected language:?\s*'?([A-Za-z][A-Za-z ]*[A-Za-z]|[A-Za-z])'?`)
+
+// whisperArgs builds the command line for backend. whisper.cpp takes a model
+// file and a 16 kHz WAV, so its input is converted into outDir first. device
+// is "", cpu or cuda; "" leaves the backend's device choice alone.
+func whisperArgs(backend, whisperPath, mediaPath, lang, model, device, outDir string) ([]string, error) {
+	auto := strings.TrimSpace(lang) == "" || strings.TrimSpace(lang) == "auto"
+	switch backend {
+	case whisperBackendCpp:
+		modelPath, err := whisperCppModelPath(model, whisperPath)
+		if err != nil {
+			return nil, err
+		}
+		wavPath, err := whisperCppInput(mediaPath, outDir)
+		if err != nil {
+			return nil, err
+		}
+		if auto {
+			// whisper.cpp defaults to English rather than detecting.
+			lang = "auto"
+		}
+		base := strings.TrimSuffix(filepath.Base(mediaPath), filepath.Ext(mediaPath))
+		args := []string{
+			"-m", modelPath,
+			"-f", wavPath,
+			"-l", lang,
+			"-osrt",
+			"-of", filepath.Join(outDir, base),
+		}
+		if device == whisperDeviceCPU {
+			// whisper.cpp uses the GPU whenever it was built with one.
+			args = append(args, "-ng")
+		}
+		return args, nil
+	case whisperBackendFaster:
+		args := []string{
+			mediaPath,
+			"--task", "transcribe",
+			"--output_format", "srt",
+			"--output_dir", outDir,
+			"--model", model,
+		}
+		if device != "" {
+			args = append(args, "--device", device)
+		}
+		if !auto {
+			args = append(args, "--language", lang)
+		}
+		return args, nil
+	default:
+		args := []string{
+			mediaPath,
+			"--task", "transcribe",
+			"--output_format", "srt",
+			"--output_dir", outDir,
+			"--model", model,
+		}
+		switch device {
+		case whisperDeviceCUDA:
+			// fp16 is whisper's default and what makes CUDA fast.
+			args = append(args, "--device", device)
+		case whisperDeviceCPU:
+			args = append(args, "--device", device, "--fp16", "False")
+		default:
+			// fp16 is unsupported on CPU and only warns there; keep the
+			// CPU-safe setting when the device is not known.
+			args = append(args, "--fp16", "False")
+		}
+		if !auto {
+			args = append(args, "--language", lang)
+		}
+		return args, nil
+	}
 }
 
-func runWhisperTranscribe(whisperPath, mediaPath, lang, outDir string) (string, error) {
-	model := strings.TrimSpace(os.Getenv("MINGEST_WHISPER_MODEL"))
-	if model == "" {
-		model = prepWhisperDefaultModel
+// whisperCppModelPath accepts a model file path or a model name such as
+// "small", looked up as ggml-<name>.bin under models/ next to the binary or
+// in the working directory.
+func whisperCppModelPath(model, whisperPath string) (string, error) {
+	if fileExists(model) {
+		return model, nil
+	}
+	name := "ggml-" + model + ".bin"
+	wd, _ := os.Getwd()
+	binDir := filepath.Dir(whisperPath)
+	for _, dir := range []string{filepath.Join(binDir, "models"), binDir, filepath.Join(wd, "models"), wd} {
+		if p := filepath.Join(dir, name); fileExists(p) {
+			return p, nil
+		}
 	}
+	return "", fmt.Errorf("未找到 whisper.cpp 模型 %s：请放在 whisper.cpp 的 models 目录，或用 MINGEST_WHISPER_MODEL 指定模型文件路径", name)
+}
 
-	args := []string{
-		mediaPath,
-		"--task", "transcribe",
-		"--output_format", "srt",
-		"--output_dir", outDir,
-		"--model", model,
-		"--fp16", "False",
+// whisperCppInput converts the media to the 16 kHz mono WAV whisper.cpp reads.
+func whisperCppInput(mediaPath, outDir string) (string, error) {
+	ffmpegPath, ok := detectSemanticFFmpeg()
+	if !ok {
+		return "", fmt.Errorf("whisper.cpp 需要 ffmpeg 将素材转为 16kHz WAV，但未找到 ffmpeg")
 	}
-	if strings.TrimSpace(lang) != "" && strings.TrimSpace(lang) != "auto" {
-		args = append(args, "--language", lang)
+	wavPath := filepath.Join(outDir, "whisper-input.wav")
+	cmd := exec.Command(ffmpegPath, "-y", "-hide_banner", "-loglevel", "error", "-i", mediaPath, "-vn", "-ar", "16000", "-ac", "1", "-c:a", "pcm_s16le", wavPath)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("转换 whisper.cpp 输入音频失败: %v %s", err, strings.TrimSpace(string(out)))
 	}
+	return wavPath, nil
+}
 
-	var stderr bytes.Buffer
+// runWhisperTranscribe writes an SRT into outDir and returns its path plus the
+// language whisper detected (the requested one when lang is set). An empty
+// model falls back to MINGEST_WHISPER_MODEL, then the default; an empty device
+// falls back to MINGEST_WHISPER_DEVICE. When progress is non-nil whisper's
+// stderr (where all backends report progress) is copied to it as it runs.
+func runWhisperTranscribe(whisperPath, mediaPath, lang, model, device, outDir string, progress io.Writer) (string, string, error) {
+	model = firstNonEmpty(strings.TrimSpace(model), strings.TrimSpace(os.Getenv("MINGEST_WHISPER_MODEL")), prepWhisperDefaultModel)
+	device = whisperDevice(device)
+
+	args, err := whisperArgs(whisperBackend(), whisperPath, mediaPath, lang, model, device, outDir)
+	if err != nil {
+		return "", "", err
+	}
+
+	var stdout, stderr bytes.Buffer
 	cmd := exec.Command(whisperPath, args...)
-	cmd.Stdout = io.Discard
+	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
+	if progress != nil {
+		cmd.Stderr = io.MultiWriter(&stderr, progress)
+	}
+	logInfo("prep.whisper_started", "backend", whisperBackend(), "model", model, "device", firstNonEmpty(device, "default"))
 	if err := cmd.Run(); err != nil {
 		detail := strings.TrimSpace(stderr.String())
 		if detail == "" {
 			detail = err.Error()
 		}
-		return "", fmt.Errorf("Whisper 转写失败: %s", detail)
+		return "", "", fmt.Errorf("Whisper 转写失败: %s", detail)
 	}
 
 	path, err := findLatestSubtitleFile(outDir)
 	if err != nil {
-		return "", err
+		return "", "", err
+	}
+	detected := strings.TrimSpace(lang)
+	if detected == "" || detected == "auto" {
+		detected = ""
+		if m := whisperDetectedLangRE.FindStringSubmatch(stdout.String() + "\n" + stderr.String()); m != nil {
+			detected = strings.TrimSpace(m[1])
+		}
 	}
-	return path, nil
+	return path, detected, nil
 }
 
 func findLatestSubtitleFile(dir string) (string, error) {
@@ -864,6 +1835,38 @@ func copySubtitleFile(srcPath, dstPath string) error {
 	return os.WriteFile(dstPath, b, 0o644)
 }
 
+// Speech rates (characters per second) below which subtitles look too sparse:
+// under subtitleSparseCharsPerSec is implausible, under subtitleLowCharsPerSec
+// is suspicious. Shared by prep's subtitle scoring and doctor's per-clip check.
+const (
+	subtitleSparseCharsPerSec = 1.0
+	subtitleLowCharsPerSec    = 1.5
+)
+
+type subtitleCueStats struct {
+	CoverageSec float64 // cue time inside the window
+	Chars       float64 // cue characters, prorated by the share of each cue inside the window
+}
+
+// measureSubtitleCues totals cue time and characters within [fromSec, toSec];
+// pass an infinite window to measure the whole track.
+func measureSubtitleCues(cues []subtitleCue, fromSec, toSec float64) subtitleCueStats {
+	var s subtitleCueStats
+	for _, cue := range cues {
+		dur := cue.EndSec - cue.StartSec
+		if dur <= 0 {
+			continue
+		}
+		inter := math.Min(cue.EndSec, toSec) - math.Max(cue.StartSec, fromSec)
+		if inter <= 0 {
+			continue
+		}
+		s.CoverageSec += inter
+		s.Chars += float64(utf8.RuneCountInString(strings.TrimSpace(cue.Text))) * inter / dur
+	}
+	return s
+}
+
 func evaluateSubtitleFileQuality(path string, mediaDurationSec float64) (float64, string, error) {
 	cues, err := parseSubtitleCues(path)
 	if err != nil {
@@ -873,16 +1876,8 @@ func evaluateSubtitleFileQuality(path string, mediaDurationSec float64) (float64
 		return 0, "无有效字幕条目", nil
 	}
 
-	coverageSec := 0.0
-	charCount := 0
-	for _, cue := range cues {
-		dur := cue.EndSec - cue.StartSec
-		if dur <= 0 {
-			continue
-		}
-		coverageSec += dur
-		charCount += utf8.RuneCountInString(strings.TrimSpace(cue.Text))
-	}
+	stats := measureSubtitleCues(cues, math.Inf(-1), math.Inf(1))
+	coverageSec, charCount := stats.CoverageSec, stats.Chars
 	if coverageSec <= 0 || charCount == 0 {
 		return 0, "字幕内容为空", nil
 	}
@@ -891,7 +1886,7 @@ func evaluateSubtitleFileQuality(path string, mediaDurationSec float64) (float64
 	if mediaDurationSec > 0 {
 		coverageRatio = coverageSec / mediaDurationSec
 	}
-	charsPerSec := float64(charCount) / coverageSec
+	charsPerSec := charCount / coverageSec
 	avgCueSec := coverageSec / float64(len(cues))
 	expectedMinCues := 4
 	if mediaDurationSec > 0 {
@@ -911,9 +1906,9 @@ func evaluateSubtitleFileQuality(path string, mediaDurationSec float64) (float64
 	}
 
 	switch {
-	case charsPerSec < 1.0:
+	case charsPerSec < subtitleSparseCharsPerSec:
 		score -= 0.35
-	case charsPerSec < 1.5:
+	case charsPerSec < subtitleLowCharsPerSec:
 		score -= 0.18
 	case charsPerSec > 24.0:
 		score -= 0.35
@@ -949,9 +1944,14 @@ func parseSubtitleCues(path string) ([]subtitleCue, error) {
 		return nil, err
 	}
 	defer f.Close()
+	return parseSubtitleCuesFrom(f)
+}
 
+// parseSubtitleCuesFrom parses SRT/WebVTT text, e.g. a single-file plan's
+// embedded subtitle.
+func parseSubtitleCuesFrom(r io.Reader) ([]subtitleCue, error) {
 	lines := make([]string, 0, 256)
-	scanner := bufio.NewScanner(f)
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		lines = append(lines, scanner.Text())
 	}
@@ -1180,18 +2180,36 @@ func probeMediaFile(ffprobePath, mediaPath string) (mediaProbe, error) {
 		Height       int    `json:"height"`
 		RFrameRate   string `json:"r_frame_rate"`
 		AvgFrameRate string `json:"avg_frame_rate"`
+		Primaries    string `json:"color_primaries"`
+		Transfer     string `json:"color_transfer"`
+		ColorSpace   string `json:"color_space"`
+		Tags         struct {
+			Language string `json:"language"`
+		} `json:"tags"`
+		Disposition struct {
+			AttachedPic int `json:"attached_pic"`
+		} `json:"disposition"`
 	}
 	type ffprobeFormat struct {
 		Duration string `json:"duration"`
 	}
+	type ffprobeChapter struct {
+		StartTime string `json:"start_time"`
+		EndTime   string `json:"end_time"`
+		Tags      struct {
+			Title string `json:"title"`
+		} `json:"tags"`
+	}
 	type ffprobeResult struct {
-		Streams []ffprobeStream `json:"streams"`
-		Format  ffprobeFormat   `json:"format"`
+		Streams  []ffprobeStream  `json:"streams"`
+		Format   ffprobeFormat    `json:"format"`
+		Chapters []ffprobeChapter `json:"chapters"`
 	}
 
 	args := []string{
 		"-v", "error",
-		"-show_entries", "format=duration:stream=codec_type,codec_name,width,height,avg_frame_rate,r_frame_rate",
+		"-show_entries", "format=duration:stream=codec_type,codec_name,width,height,avg_frame_rate,r_frame_rate,color_primaries,color_transfer,color_space:stream_tags=language:stream_disposition=attached_pic",
+		"-show_chapters",
 		"-of", "json",
 		mediaPath,
 	}
@@ -1222,27 +2240,74 @@ func probeMediaFile(ffprobePath, mediaPath string) (mediaProbe, error) {
 		}
 	}
 
+	for _, c := range parsed.Chapters {
+		title := strings.TrimSpace(c.Tags.Title)
+		start, err1 := strconv.ParseFloat(strings.TrimSpace(c.StartTime), 64)
+		end, err2 := strconv.ParseFloat(strings.TrimSpace(c.EndTime), 64)
+		if err1 != nil || err2 != nil || end <= start {
+			continue
+		}
+		if !strings.HasPrefix(title, sponsorBlockChapterPrefix) {
+			probe.Chapters = append(probe.Chapters, mediaChapter{
+				StartSec: roundMillis(start),
+				EndSec:   roundMillis(end),
+				Title:    title,
+			})
+			continue
+		}
+		probe.SponsorSegments = append(probe.SponsorSegments, sponsorSegment{
+			StartSec: roundMillis(start),
+			EndSec:   roundMillis(end),
+			Category: strings.TrimSpace(strings.TrimPrefix(title, sponsorBlockChapterPrefix)),
+		})
+	}
+
 	for _, s := range parsed.Streams {
 		switch strings.TrimSpace(s.CodecType) {
 		case "video":
+			if s.Disposition.AttachedPic == 1 {
+				// Embedded cover art, not a picture track.
+				continue
+			}
 			if probe.Width == 0 && probe.Height == 0 {
 				probe.Width = s.Width
 				probe.Height = s.Height
 				probe.VideoCodec = strings.TrimSpace(s.CodecName)
 				probe.FPS = roundMillis(selectFrameRate(s.AvgFrameRate, s.RFrameRate))
+				probe.ColorPrimaries = ffprobeColorValue(s.Primaries)
+				probe.ColorTransfer = ffprobeColorValue(s.Transfer)
+				probe.ColorSpace = ffprobeColorValue(s.ColorSpace)
 			}
 		case "audio":
 			probe.AudioTracks++
+			if lang := strings.TrimSpace(s.Tags.Language); lang != "" {
+				probe.AudioLanguages = append(probe.AudioLanguages, lang)
+			}
 		}
 	}
 
 	if probe.Width == 0 || probe.Height == 0 {
+		if probe.AudioTracks > 0 && probe.DurationSec > 0 {
+			// Audio-only (e.g. podcasts): usable for subtitle work, callers decide per goal.
+			probe.Width, probe.Height, probe.FPS, probe.VideoCodec = 0, 0, 0, ""
+			probe.AudioOnly = true
+			return probe, nil
+		}
 		return mediaProbe{}, fmt.Errorf("未检测到视频流（文件可能不是视频）")
 	}
 
 	return probe, nil
 }
 
+// ffprobeColorValue normalizes an ffprobe color tag, treating "unknown" as absent.
+func ffprobeColorValue(v string) string {
+	v = strings.ToLower(strings.TrimSpace(v))
+	if v == "unknown" || v == "unspecified" || v == "reserved" {
+		return ""
+	}
+	return v
+}
+
 func selectFrameRate(avgFrameRate, rawFrameRate string) float64 {
 	if v := parseRate(strings.TrimSpace(avgFrameRate)); v > 0 {
 		return v
@@ -1273,12 +2338,51 @@ func parseRate(v string) float64 {
 	return n
 }
 
-func buildPrepClips(durationSec float64, maxClips int, clipSeconds int, goal string) []prepClip {
-	if durationSec <= 0 || maxClips <= 0 || clipSeconds <= 0 {
+// defaultPrepSkipIntroSec is the intro (and outro) excluded by `--strategy skip-intro`.
+const defaultPrepSkipIntroSec = 30
+
+// prepClipWindow returns the part of the timeline clips may be placed in. For
+// skip-intro the same offset is cut from both ends; it is ignored when the
+// remaining window could not hold a single clip.
+func prepClipWindow(durationSec, clipLen float64, opts prepOptions) (lo, hi float64) {
+	lo, hi, ok := prepClipBounds(durationSec, clipLen, opts)
+	if !ok {
+		logWarn("prep.skip_intro_ignored", "duration_sec", durationSec, "skip_intro_sec", opts.SkipIntroSec, "reason", "window shorter than clip")
+	}
+	return lo, hi
+}
+
+// prepClipBounds is prepClipWindow without the warning; ok is false when a
+// requested skip-intro window had to be dropped.
+func prepClipBounds(durationSec, clipLen float64, opts prepOptions) (lo, hi float64, ok bool) {
+	if opts.Strategy != "skip-intro" || opts.SkipIntroSec <= 0 {
+		return 0, durationSec, true
+	}
+	off := float64(opts.SkipIntroSec)
+	if durationSec-2*off < clipLen {
+		return 0, durationSec, false
+	}
+	return off, durationSec - off, true
+}
+
+// prepClipCenter places clip i of n within [lo, hi]. frontload biases centers
+// toward the start (t^1.5), so earlier parts of the video get denser coverage.
+func prepClipCenter(strategy string, i, n int, lo, hi float64) float64 {
+	t := float64(i+1) / float64(n+1)
+	if strategy == "frontload" {
+		t = math.Pow(t, 1.5)
+	}
+	return lo + (hi-lo)*t
+}
+
+func buildPrepClips(durationSec float64, opts prepOptions) []prepClip {
+	maxClips := opts.MaxClips
+	goal := opts.Goal
+	if durationSec <= 0 || maxClips <= 0 || opts.ClipSeconds <= 0 {
 		return []prepClip{}
 	}
 
-	clipLen := float64(clipSeconds)
+	clipLen := float64(opts.ClipSeconds)
 	if durationSec <= clipLen {
 		return []prepClip{
 			{
@@ -1292,29 +2396,44 @@ func buildPrepClips(durationSec float64, maxClips int, clipSeconds int, goal str
 		}
 	}
 
-	maxUseful := int(math.Ceil(durationSec / clipLen))
+	lo, hi := prepClipWindow(durationSec, clipLen, opts)
+	maxUseful := int(math.Ceil((hi - lo) / clipLen))
 	if maxUseful < 1 {
 		maxUseful = 1
 	}
 	if maxClips > maxUseful {
 		maxClips = maxUseful
 	}
+	if gap := opts.MinGapSec; gap > 0 {
+		// n clips need n*clipLen + (n-1)*gap of timeline; drop clips until they fit.
+		requested := maxClips
+		for maxClips > 1 && float64(maxClips)*clipLen+float64(maxClips-1)*gap > hi-lo {
+			maxClips--
+		}
+		if maxClips < requested {
+			logWarn("prep.min_gap_dropped_clips", "min_gap_sec", gap, "requested", requested, "placed", maxClips)
+		}
+	}
 
-	step := durationSec / float64(maxClips+1)
-	out := make([]prepClip, 0, maxClips)
-	for i := 0; i < maxClips; i++ {
-		center := step * float64(i+1)
+	starts := make([]float64, maxClips)
+	for i := range starts {
+		center := prepClipCenter(opts.Strategy, i, maxClips, lo, hi)
 		start := center - clipLen/2
-		if start < 0 {
-			start = 0
+		if start < lo {
+			start = lo
 		}
-		if start+clipLen > durationSec {
-			start = durationSec - clipLen
+		if start+clipLen > hi {
+			start = hi - clipLen
 		}
 		if start < 0 {
 			start = 0
 		}
+		starts[i] = start
+	}
+	enforcePrepClipGap(starts, clipLen, opts.MinGapSec, lo, hi)
 
+	out := make([]prepClip, 0, maxClips)
+	for i, start := range starts {
 		end := start + clipLen
 		if end > durationSec {
 			end = durationSec
@@ -1336,6 +2455,326 @@ func buildPrepClips(durationSec float64, maxClips int, clipSeconds int, goal str
 	return out
 }
 
+// clipLabelPlaceholderRE matches `{name}` placeholders in a --label-template.
+var clipLabelPlaceholderRE = regexp.MustCompile(`\{[^{}]*\}`)
+
+// clipLabelTitleMaxRunes caps {title} so labels stay short enough for NLE bins.
+const clipLabelTitleMaxRunes = 40
+
+func validateClipLabelTemplate(tmpl string) error {
+	for _, m := range clipLabelPlaceholderRE.FindAllString(tmpl, -1) {
+		switch m {
+		case "{index}", "{type}", "{title}", "{start}":
+		default:
+			return fmt.Errorf("`--label-template` 不支持的占位符 %s（可用: {index} {type} {title} {start}）", m)
+		}
+	}
+	return nil
+}
+
+// renderClipLabel fills a --label-template and sanitizes the result so it is
+// safe as a file name and as an EDL/FCPXML clip name.
+func renderClipLabel(tmpl string, index int, typ, title string, startSec float64) string {
+	if runes := []rune(strings.TrimSpace(title)); len(runes) > clipLabelTitleMaxRunes {
+		title = string(runes[:clipLabelTitleMaxRunes])
+	}
+	total := int(math.Max(startSec, 0))
+	start := fmt.Sprintf("%02dm%02ds", total/60, total%60)
+	if total >= 3600 {
+		start = fmt.Sprintf("%dh%02dm%02ds", total/3600, total%3600/60, total%60)
+	}
+	out := strings.NewReplacer(
+		"{index}", fmt.Sprintf("%02d", index),
+		"{type}", typ,
+		"{title}", title,
+		"{start}", start,
+	).Replace(tmpl)
+	// sanitizeFileName maps every unsafe rune to '-'; collapse the runs.
+	return strings.Join(strings.FieldsFunc(sanitizeFileName(out), func(r rune) bool { return r == '-' }), "-")
+}
+
+// applyClipLabelTemplate relabels clips in place; an empty template keeps the
+// default clip-NN labels.
+func applyClipLabelTemplate(clips []prepClip, tmpl, typ, title string) {
+	if strings.TrimSpace(tmpl) == "" {
+		return
+	}
+	for i := range clips {
+		clips[i].Label = renderClipLabel(tmpl, clips[i].Index, typ, title, clips[i].StartSec)
+	}
+}
+
+// alignPrepClipsToChapters moves a clip to start on a nearby source chapter
+// boundary (within a third of the clip length), since chapter starts are
+// natural cut points. A move that would break the clip window, overlap a
+// neighbour or violate --min-gap is skipped.
+func alignPrepClipsToChapters(clips []prepClip, chapters []mediaChapter, durationSec float64, opts prepOptions) []prepClip {
+	if len(clips) == 0 || len(chapters) == 0 || opts.ClipSeconds <= 0 {
+		return clips
+	}
+	clipLen := float64(opts.ClipSeconds)
+	lo, hi, _ := prepClipBounds(durationSec, clipLen, opts)
+	maxShift := clipLen / 3
+
+	for i := range clips {
+		best := -1
+		for j, ch := range chapters {
+			if math.Abs(ch.StartSec-clips[i].StartSec) > maxShift {
+				continue
+			}
+			if best < 0 || math.Abs(ch.StartSec-clips[i].StartSec) < math.Abs(chapters[best].StartSec-clips[i].StartSec) {
+				best = j
+			}
+		}
+		if best < 0 || chapters[best].StartSec == clips[i].StartSec {
+			continue
+		}
+		start := chapters[best].StartSec
+		end := math.Min(start+clipLen, durationSec)
+		if start < lo || end > hi {
+			continue
+		}
+		if i > 0 && start < clips[i-1].EndSec+opts.MinGapSec {
+			continue
+		}
+		if i+1 < len(clips) && end+opts.MinGapSec > clips[i+1].StartSec {
+			continue
+		}
+		clips[i].StartSec = roundMillis(start)
+		clips[i].EndSec = roundMillis(end)
+		clips[i].DurationSec = roundMillis(end - start)
+		if title := strings.TrimSpace(chapters[best].Title); title != "" {
+			clips[i].Reason += fmt.Sprintf("；对齐来源章节「%s」", title)
+		} else {
+			clips[i].Reason += "；对齐来源章节"
+		}
+	}
+	return clips
+}
+
+// enforcePrepClipGap shifts ascending clip starts so consecutive clips are at
+// least gap seconds apart: a forward pass pushes clips later, then a backward
+// pass pulls any that ran past hi back toward the start. The caller guarantees
+// the clips fit in [lo, hi] with the gap.
+func enforcePrepClipGap(starts []float64, clipLen, gap, lo, hi float64) {
+	if gap <= 0 || len(starts) < 2 {
+		return
+	}
+	for i := 1; i < len(starts); i++ {
+		if minStart := starts[i-1] + clipLen + gap; starts[i] < minStart {
+			starts[i] = minStart
+		}
+	}
+	if last := len(starts) - 1; starts[last]+clipLen > hi {
+		starts[last] = math.Max(lo, hi-clipLen)
+	}
+	for i := len(starts) - 2; i >= 0; i-- {
+		if maxStart := starts[i+1] - clipLen - gap; starts[i] > maxStart {
+			starts[i] = math.Max(lo, maxStart)
+		}
+	}
+}
+
+// Chapter boundary scoring: a long pause before a cue and hook wording in the
+// cues that follow both suggest a topic shift.
+const (
+	prepChapterGapFullSec  = 4.0
+	prepChapterGapWeight   = 0.6
+	prepChapterHookWeight  = 0.4
+	prepChapterMinScore    = 0.3
+	prepChapterLookahead   = 3
+	prepChapterTitleLength = 24
+	// prepChapterSourceScore ranks chapters embedded by the source (get
+	// --embed-chapters) ahead of any boundary detected from cues.
+	prepChapterSourceScore = 2.0
+)
+
+type prepChapterBoundary struct {
+	StartSec float64
+	Score    float64
+	GapSec   float64
+	Hook     float64
+	CueIndex int
+	// Source marks chapters embedded in the media; they carry their own Title.
+	Source bool
+	Title  string
+}
+
+// buildPrepChapters turns subtitle cues into chapter markers: each clip runs
+// from one chapter start to the next. opts.MaxClips caps the chapter count and
+// opts.ClipSeconds is the minimum chapter length. Source chapters embedded in
+// the media win over detected boundaries; with neither the timeline is split
+// evenly.
+func buildPrepChapters(cues []subtitleCue, source []mediaChapter, durationSec float64, opts prepOptions) []prepClip {
+	if durationSec <= 0 || opts.MaxClips <= 0 {
+		return []prepClip{}
+	}
+	minLen := float64(opts.ClipSeconds)
+
+	var starts []float64
+	reasons := map[float64]string{0: "开头"}
+	titles := map[float64]string{}
+	if len(cues) == 0 && len(source) == 0 {
+		n := opts.MaxClips
+		if minLen > 0 {
+			if fit := int(durationSec / minLen); fit < n {
+				n = fit
+			}
+		}
+		if n < 1 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			start := roundMillis(durationSec * float64(i) / float64(n))
+			starts = append(starts, start)
+			if i > 0 {
+				reasons[start] = "无字幕，按时长均分"
+			}
+		}
+	} else {
+		var candidates []prepChapterBoundary
+		for i := 1; i < len(cues); i++ {
+			gap := cues[i].StartSec - cues[i-1].EndSec
+			end := i + prepChapterLookahead
+			if end > len(cues) {
+				end = len(cues)
+			}
+			var text strings.Builder
+			for _, c := range cues[i:end] {
+				text.WriteString(c.Text)
+				text.WriteString(" ")
+			}
+			signals, _ := semanticScoreSignals(text.String(), cues[end-1].EndSec-cues[i].StartSec)
+			gapScore := clamp01(gap / prepChapterGapFullSec)
+			score := prepChapterGapWeight*gapScore + prepChapterHookWeight*signals.Hook
+			if score < prepChapterMinScore {
+				continue
+			}
+			candidates = append(candidates, prepChapterBoundary{
+				StartSec: cues[i].StartSec,
+				Score:    score,
+				GapSec:   gap,
+				Hook:     signals.Hook,
+				CueIndex: i,
+			})
+		}
+		for _, ch := range source {
+			if ch.StartSec <= 0 {
+				if ch.Title != "" {
+					titles[0] = ch.Title
+				}
+				continue
+			}
+			candidates = append(candidates, prepChapterBoundary{
+				StartSec: ch.StartSec,
+				Score:    prepChapterSourceScore,
+				Source:   true,
+				Title:    ch.Title,
+			})
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].Score > candidates[j].Score
+		})
+
+		starts = []float64{0}
+		if titles[0] == "" {
+			titles[0] = prepChapterTitle(cues, 0)
+		}
+		for _, c := range candidates {
+			if len(starts) >= opts.MaxClips {
+				break
+			}
+			if c.StartSec < minLen || durationSec-c.StartSec < minLen {
+				continue
+			}
+			tooClose := false
+			for _, s := range starts {
+				if math.Abs(s-c.StartSec) < minLen {
+					tooClose = true
+					break
+				}
+			}
+			if tooClose {
+				continue
+			}
+			start := roundMillis(c.StartSec)
+			starts = append(starts, start)
+			if c.Source {
+				reasons[start] = "来源章节"
+				titles[start] = c.Title
+				continue
+			}
+			reasons[start] = fmt.Sprintf("话题切换：停顿 %.1fs，hook %.2f", math.Max(c.GapSec, 0), c.Hook)
+			titles[start] = prepChapterTitle(cues, c.CueIndex)
+		}
+		sort.Float64s(starts)
+	}
+
+	clips := make([]prepClip, 0, len(starts))
+	for i, start := range starts {
+		end := durationSec
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		label := titles[start]
+		if label == "" {
+			label = fmt.Sprintf("chapter-%02d", i+1)
+		}
+		clips = append(clips, prepClip{
+			Index:       i + 1,
+			StartSec:    roundMillis(start),
+			EndSec:      roundMillis(end),
+			DurationSec: roundMillis(end - start),
+			Label:       label,
+			Reason:      reasons[start],
+		})
+	}
+	return clips
+}
+
+// prepChapterTitle uses the opening words of the chapter's first cue.
+func prepChapterTitle(cues []subtitleCue, index int) string {
+	if index < 0 || index >= len(cues) {
+		return ""
+	}
+	text := strings.Join(strings.Fields(cues[index].Text), " ")
+	runes := []rune(text)
+	if len(runes) > prepChapterTitleLength {
+		text = strings.TrimSpace(string(runes[:prepChapterTitleLength])) + "…"
+	}
+	return text
+}
+
+func writePrepChapters(path string, clips []prepClip, durationSec float64) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	useHours := durationSec >= 3600
+	w := csv.NewWriter(f)
+	if err := w.Write([]string{"index", "timestamp", "start_sec", "end_sec", "duration_sec", "title", "reason"}); err != nil {
+		return err
+	}
+	for _, c := range clips {
+		row := []string{
+			strconv.Itoa(c.Index),
+			formatChapterTimestamp(c.StartSec, useHours),
+			strconv.FormatFloat(c.StartSec, 'f', 3, 64),
+			strconv.FormatFloat(c.EndSec, 'f', 3, 64),
+			strconv.FormatFloat(c.DurationSec, 'f', 3, 64),
+			c.Label,
+			c.Reason,
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
 func prepClipReason(goal string) string {
 	switch goal {
 	case "subtitle":
@@ -1347,12 +2786,87 @@ func prepClipReason(goal string) string {
 	}
 }
 
-func createPrepBundle(assetPath, assetID string) (prepOutputFiles, error) {
+// mingestBundleRoot returns the base directory for prep/semantic/export bundles:
+// --bundle-dir, then MINGEST_BUNDLE_ROOT, then `.mingest` next to the media file.
+func mingestBundleRoot(assetPath, bundleDir string) string {
+	if d := strings.TrimSpace(bundleDir); d != "" {
+		return d
+	}
+	if d := strings.TrimSpace(os.Getenv("MINGEST_BUNDLE_ROOT")); d != "" {
+		return d
+	}
+	return filepath.Join(filepath.Dir(assetPath), ".mingest")
+}
+
+// mingestLibraryDir returns the central library root (--library / MINGEST_LIBRARY), or "".
+func mingestLibraryDir() string {
+	v := strings.TrimSpace(os.Getenv("MINGEST_LIBRARY"))
+	if v == "" {
+		return ""
+	}
+	if abs, err := filepath.Abs(v); err == nil {
+		return abs
+	}
+	return v
+}
+
+// mingestArtifactRoot returns the directory holding one asset's kind bundles
+// (prep|semantic|export). Library mode keeps everything for an asset together
+// under <library>/<asset_id>/<kind>; otherwise it is <bundle root>/<kind>/<asset_id>.
+// An explicit --bundle-dir still wins over the library.
+func mingestArtifactRoot(assetPath, assetID, bundleDir, kind string) string {
+	if strings.TrimSpace(bundleDir) == "" {
+		if lib := mingestLibraryDir(); lib != "" {
+			return filepath.Join(lib, assetID, kind)
+		}
+	}
+	return filepath.Join(mingestBundleRoot(assetPath, bundleDir), kind, assetID)
+}
+
+// libraryAssetRecord is written as <library>/<asset_id>/asset.json so the library
+// stays self-describing while the media itself lives elsewhere.
+type libraryAssetRecord struct {
+	AssetID   string `json:"asset_id"`
+	MediaPath string `json:"media_path"`
+	Title     string `json:"title,omitempty"`
+	URL       string `json:"url,omitempty"`
+	Platform  string `json:"platform,omitempty"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// writeLibraryAssetRecord refreshes asset.json in library mode; a no-op otherwise.
+func writeLibraryAssetRecord(asset prepResolvedAsset, bundleDir string) error {
+	lib := mingestLibraryDir()
+	if lib == "" || strings.TrimSpace(bundleDir) != "" || strings.TrimSpace(asset.AssetID) == "" {
+		return nil
+	}
+	mediaPath := asset.OutputPath
+	if abs, err := filepath.Abs(mediaPath); err == nil {
+		mediaPath = abs
+	}
+	dir := filepath.Join(lib, asset.AssetID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	return writeJSONFile(filepath.Join(dir, "asset.json"), libraryAssetRecord{
+		AssetID:   asset.AssetID,
+		MediaPath: mediaPath,
+		Title:     asset.Title,
+		URL:       asset.URL,
+		Platform:  asset.Platform,
+		UpdatedAt: time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+func createPrepBundle(asset prepResolvedAsset, bundleDir string) (prepOutputFiles, error) {
 	ts := time.Now().UTC().Format("20060102T150405Z")
-	base := filepath.Join(filepath.Dir(assetPath), ".mingest", "prep", assetID, ts)
+	base := filepath.Join(mingestArtifactRoot(asset.OutputPath, asset.AssetID, bundleDir, "prep"), ts)
 	if err := os.MkdirAll(base, 0o755); err != nil {
 		return prepOutputFiles{}, err
 	}
+	if err := writeLibraryAssetRecord(asset, bundleDir); err != nil {
+		logWarn("prep.library_record_failed", "asset_id", asset.AssetID, "error", err)
+	}
 	return prepOutputFiles{
 		BundleDir:  base,
 		PlanPath:   filepath.Join(base, "prep-plan.json"),
@@ -1360,6 +2874,97 @@ func createPrepBundle(assetPath, assetID string) (prepOutputFiles, error) {
 	}, nil
 }
 
+// embedPrepOutputs moves markers/subtitle contents into plan.Embedded, rewrites
+// the plan and removes the separate files.
+func embedPrepOutputs(plan *prepPlan) error {
+	read := func(path string) (string, error) {
+		if strings.TrimSpace(path) == "" || !fileExists(path) {
+			return "", nil
+		}
+		b, err := os.ReadFile(path)
+		return string(b), err
+	}
+
+	var embedded prepEmbeddedFiles
+	var err error
+	if embedded.MarkersCSV, err = read(plan.Outputs.MarkersCSV); err != nil {
+		return err
+	}
+	if embedded.ChaptersCSV, err = read(plan.Outputs.ChaptersCSV); err != nil {
+		return err
+	}
+	if embedded.Subtitle, err = read(plan.Outputs.SubtitlePath); err != nil {
+		return err
+	}
+	if embedded.SubtitleTemplate, err = read(plan.Outputs.SubtitleTemplate); err != nil {
+		return err
+	}
+	plan.Embedded = &embedded
+	if err := writePrepPlan(plan.Outputs.PlanPath, *plan); err != nil {
+		return err
+	}
+	for _, p := range []string{plan.Outputs.MarkersCSV, plan.Outputs.ChaptersCSV, plan.Outputs.SubtitlePath, plan.Outputs.SubtitleTemplate} {
+		if strings.TrimSpace(p) != "" {
+			_ = os.Remove(p)
+		}
+	}
+	return nil
+}
+
+// extractPrepEmbedded writes a single-file plan's embedded contents into a
+// fresh temp dir and returns a copy of plan whose Outputs point there, for
+// commands that must hand real files on (e.g. export copying the SRT). The
+// original Outputs paths may be stale when the plan was moved, so they are
+// never written. Call cleanup when done; plans without Embedded pass through.
+func extractPrepEmbedded(plan prepPlan) (prepPlan, func(), error) {
+	if plan.Embedded == nil {
+		return plan, func() {}, nil
+	}
+	dir, err := os.MkdirTemp("", "mingest-plan-*")
+	if err != nil {
+		return plan, func() {}, err
+	}
+	unregister := registerCleanup(func() { _ = os.RemoveAll(dir) })
+	cleanup := func() {
+		unregister()
+		_ = os.RemoveAll(dir)
+	}
+
+	extract := func(path *string, content, fallbackName string) error {
+		if content == "" {
+			return nil
+		}
+		name := filepath.Base(strings.TrimSpace(*path))
+		if name == "." || name == string(filepath.Separator) {
+			name = fallbackName
+		}
+		target := filepath.Join(dir, name)
+		if err := os.WriteFile(target, []byte(content), 0o644); err != nil {
+			return err
+		}
+		*path = target
+		return nil
+	}
+	out := plan
+	for _, f := range []struct {
+		path     *string
+		content  string
+		fallback string
+	}{
+		{&out.Outputs.MarkersCSV, plan.Embedded.MarkersCSV, "markers.csv"},
+		{&out.Outputs.ChaptersCSV, plan.Embedded.ChaptersCSV, "chapters.csv"},
+		{&out.Outputs.SubtitlePath, plan.Embedded.Subtitle, "subtitle.srt"},
+		{&out.Outputs.SubtitleTemplate, plan.Embedded.SubtitleTemplate, "subtitle-template.srt"},
+	} {
+		if err := extract(f.path, f.content, f.fallback); err != nil {
+			cleanup()
+			return plan, func() {}, err
+		}
+	}
+	logDebug("prep.embedded_extracted", "dir", dir)
+	return out, cleanup, nil
+}
+
 func writePrepPlan(path string, plan prepPlan) error {
 	data, err := json.MarshalIndent(plan, "", "  ")
 	if err != nil {
@@ -1435,7 +3040,7 @@ func roundMillis(v float64) float64 {
 }
 
 func printPrepJSON(v prepJSONResult) {
-	data, err := json.Marshal(v)
+	data, err := marshalJSONResult(v)
 	if err != nil {
 		logError("json.marshal_failed", "context", "prep_result", "error", err)
 		return
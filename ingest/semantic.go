@@ -17,14 +17,22 @@
 package ingest
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"html/template"
+	"io"
 	"math"
 	"math/bits"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -44,8 +52,18 @@ const (
 	defaultSemanticModelOpenAI      = "gpt-4.1-mini"
 	defaultSemanticModelOpenRouter  = "openai/gpt-4.1-mini"
 	defaultOpenRouterBaseURL        = "https://openrouter.ai/api/v1"
+	defaultSemanticModelAnthropic   = "claude-3-5-haiku-latest"
+	defaultAnthropicBaseURL         = "https://api.anthropic.com/v1/"
+	defaultSemanticModelGemini      = "gemini-2.0-flash"
+	defaultGeminiBaseURL            = "https://generativelanguage.googleapis.com/v1beta/openai/"
 	maxSemanticCandidateWindows     = 900
 	maxSemanticVisualHashCandidates = 48
+	defaultSemanticVisualGapSec     = 20
+	// defaultSemanticCandidateLimit applies when --candidate-limit is unset
+	// (CandidateLimit 0) and the prep probe has no duration.
+	defaultSemanticCandidateLimit = 20
+	// semanticCandidatesPerMinute scales the default candidate limit with length.
+	semanticCandidatesPerMinute = 1.0
 )
 
 type semanticOptions struct {
@@ -53,19 +71,40 @@ type semanticOptions struct {
 	Target          string
 	Provider        string
 	Model           string
+	FallbackModels  string
+	LLMTimeout      time.Duration
+	Temperature     float64
+	Seed            *int64
 	BaseURL         string
 	APIKey          string
+	Proxy           string
 	CandidateLimit  int
 	TopK            int
 	PreviewLimit    int
+	Render          semanticRenderOptions
 	VisualDiversity float64
+	VisualGaps      bool
+	VisualGapSec    float64
 	DecisionsPath   string
+	BundleDir       string
+	Bundle          string
 	NoLLM           bool
+	Resume          bool
+	SavePrompt      bool
 	Apply           bool
+	Pick            bool
+	Chronological   bool
+	ExplainScores   bool
+	EnforceSpread   bool
+	PositionBias    string
+	LabelTemplate   string
+	SelfContained   bool
 	Strict          bool
 	JSON            bool
 }
 
+const semanticTypeVisual = "visual"
+
 type semanticSignals struct {
 	Hook        float64 `json:"hook"`
 	Insight     float64 `json:"insight"`
@@ -90,6 +129,7 @@ type semanticCandidate struct {
 	Signals       semanticSignals `json:"signals"`
 	VisualHash    string          `json:"visual_hash,omitempty"`
 	PreviewPath   string          `json:"preview_path,omitempty"`
+	Rank          int             `json:"rank,omitempty"`
 }
 
 type semanticLLMItem struct {
@@ -119,15 +159,18 @@ type semanticDecisionFile struct {
 }
 
 type semanticArtifacts struct {
-	BundleDir       string `json:"bundle_dir"`
-	StageAPath      string `json:"stage_a_path"`
-	StageBPath      string `json:"stage_b_path,omitempty"`
-	StageCPath      string `json:"stage_c_path"`
-	ReviewHTMLPath  string `json:"review_html_path"`
-	ReviewDecisions string `json:"review_decisions_path"`
-	PreviewDir      string `json:"preview_dir"`
-	AppliedPlanPath string `json:"applied_plan_path,omitempty"`
-	BackupPlanPath  string `json:"backup_plan_path,omitempty"`
+	BundleDir       string   `json:"bundle_dir"`
+	StageAPath      string   `json:"stage_a_path"`
+	StageBPath      string   `json:"stage_b_path,omitempty"`
+	StageCPath      string   `json:"stage_c_path"`
+	StageCExplain   string   `json:"stage_c_explain_path,omitempty"`
+	ReviewHTMLPath  string   `json:"review_html_path"`
+	ReviewDecisions string   `json:"review_decisions_path"`
+	PreviewDir      string   `json:"preview_dir"`
+	StatePath       string   `json:"state_path,omitempty"`
+	AppliedPlanPath string   `json:"applied_plan_path,omitempty"`
+	BackupPlanPath  string   `json:"backup_plan_path,omitempty"`
+	ClipSubtitles   []string `json:"clip_subtitle_paths,omitempty"`
 }
 
 type semanticJSONResult struct {
@@ -142,9 +185,11 @@ type semanticJSONResult struct {
 	Model           string            `json:"model,omitempty"`
 	UsedLLM         bool              `json:"used_llm"`
 	Applied         bool              `json:"applied"`
+	CandidateLimit  int               `json:"candidate_limit,omitempty"`
 	CandidateCount  int               `json:"candidate_count,omitempty"`
 	SelectedCount   int               `json:"selected_count,omitempty"`
 	VisualDiversity float64           `json:"visual_diversity,omitempty"`
+	ResumedStages   []string          `json:"resumed_stages,omitempty"`
 	Artifacts       semanticArtifacts `json:"artifacts,omitempty"`
 	Warnings        []string          `json:"warnings,omitempty"`
 	DoctorSummary   doctorSummary     `json:"doctor_summary,omitempty"`
@@ -161,26 +206,62 @@ type semanticRunState struct {
 	Provider   string
 	Model      string
 	UsedLLM    bool
+	Resumed    []string
+	// CandidateLimit is the effective Stage A cap (explicit or duration-based).
+	CandidateLimit int
+	Explain        []semanticScoreExplain
+}
+
+// semanticScoreExplain breaks a selected clip's score into the values Stage C
+// used (--explain-scores). Novelty and SelectionScore are recomputed in rank
+// order against the clips picked before it; the bucket spread bonus is only
+// reported as NewBucket.
+type semanticScoreExplain struct {
+	ID             string          `json:"id"`
+	Rank           int             `json:"rank"`
+	StartSec       float64         `json:"start_sec"`
+	EndSec         float64         `json:"end_sec"`
+	Signals        semanticSignals `json:"signals"`
+	BaseScore      float64         `json:"base_score"`
+	SemanticScore  float64         `json:"semantic_score"`
+	LLMScored      bool            `json:"llm_scored"`
+	FinalScore     float64         `json:"final_score"`
+	Novelty        float64         `json:"novelty"`
+	HasVisualHash  bool            `json:"has_visual_hash"`
+	VisualWeight   float64         `json:"visual_weight,omitempty"`
+	NewBucket      bool            `json:"new_bucket"`
+	SelectionScore float64         `json:"selection_score"`
 }
 
 type semanticLLMConfig struct {
 	Provider string
 	Model    string
-	BaseURL  string
-	APIKey   string
-	Referer  string
-	Title    string
+	// FallbackModels are tried in order when Model fails with a retryable error.
+	FallbackModels []string
+	BaseURL        string
+	APIKey         string
+	Proxy          string
+	Referer        string
+	Title          string
+	// Timeout bounds each request attempt, not the whole retry sequence.
+	Timeout     time.Duration
+	Temperature float64
+	// Seed is forwarded as-is; not every provider honors it.
+	Seed *int64
 }
 
 func parseSemanticOptions(args []string) (semanticOptions, error) {
 	opts := semanticOptions{
 		Target:          "shorts",
 		Provider:        "auto",
-		CandidateLimit:  20,
+		PositionBias:    semanticPositionBiasNone,
 		TopK:            3,
 		PreviewLimit:    8,
 		VisualDiversity: 0.50,
+		Temperature:     defaultSemanticTemperature,
+		VisualGapSec:    defaultSemanticVisualGapSec,
 	}
+	candidateLimitSet := false
 
 	for i := 0; i < len(args); i++ {
 		arg := strings.TrimSpace(args[i])
@@ -189,10 +270,82 @@ func parseSemanticOptions(args []string) (semanticOptions, error) {
 			opts.JSON = true
 		case arg == "--strict":
 			opts.Strict = true
+		case arg == "--resume":
+			opts.Resume = true
 		case arg == "--no-llm":
 			opts.NoLLM = true
+		case arg == "--save-prompt":
+			opts.SavePrompt = true
 		case arg == "--apply":
 			opts.Apply = true
+		case arg == "--pick":
+			opts.Pick = true
+		case arg == "--chronological":
+			opts.Chronological = true
+		case arg == "--explain-scores":
+			opts.ExplainScores = true
+		case arg == "--enforce-spread":
+			opts.EnforceSpread = true
+		case arg == "--self-contained-review":
+			opts.SelfContained = true
+		case arg == "--normalize-audio":
+			opts.Render.Loudnorm.Enabled = true
+		case arg == "--loudnorm-two-pass":
+			opts.Render.Loudnorm.TwoPass = true
+		case arg == "--burn-subs":
+			opts.Render.BurnSubs = true
+		case arg == "--vertical":
+			opts.Render.Vertical = true
+		case arg == "--aspect":
+			if i+1 >= len(args) {
+				return semanticOptions{}, fmt.Errorf("`--aspect` 缺少参数")
+			}
+			i++
+			opts.Render.Aspect = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--aspect="):
+			opts.Render.Aspect = strings.TrimSpace(strings.TrimPrefix(arg, "--aspect="))
+		case arg == "--reframe":
+			if i+1 >= len(args) {
+				return semanticOptions{}, fmt.Errorf("`--reframe` 缺少参数")
+			}
+			i++
+			opts.Render.Reframe = strings.ToLower(strings.TrimSpace(args[i]))
+		case strings.HasPrefix(arg, "--reframe="):
+			opts.Render.Reframe = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--reframe=")))
+		case arg == "--loudness-target":
+			if i+1 >= len(args) {
+				return semanticOptions{}, fmt.Errorf("`--loudness-target` 缺少参数")
+			}
+			i++
+			v, err := strconv.ParseFloat(strings.TrimSpace(args[i]), 64)
+			if err != nil {
+				return semanticOptions{}, fmt.Errorf("`--loudness-target` 必须是数字")
+			}
+			opts.Render.Loudnorm.TargetLUFS = v
+		case strings.HasPrefix(arg, "--loudness-target="):
+			v, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimPrefix(arg, "--loudness-target=")), 64)
+			if err != nil {
+				return semanticOptions{}, fmt.Errorf("`--loudness-target` 必须是数字")
+			}
+			opts.Render.Loudnorm.TargetLUFS = v
+		case arg == "--visual-gaps":
+			opts.VisualGaps = true
+		case arg == "--visual-gap-sec":
+			if i+1 >= len(args) {
+				return semanticOptions{}, fmt.Errorf("`--visual-gap-sec` 缺少参数")
+			}
+			i++
+			v, err := strconv.ParseFloat(strings.TrimSpace(args[i]), 64)
+			if err != nil {
+				return semanticOptions{}, fmt.Errorf("`--visual-gap-sec` 必须是数字")
+			}
+			opts.VisualGapSec = v
+		case strings.HasPrefix(arg, "--visual-gap-sec="):
+			v, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimPrefix(arg, "--visual-gap-sec=")), 64)
+			if err != nil {
+				return semanticOptions{}, fmt.Errorf("`--visual-gap-sec` 必须是数字")
+			}
+			opts.VisualGapSec = v
 		case arg == "--target":
 			if i+1 >= len(args) {
 				return semanticOptions{}, fmt.Errorf("`--target` 缺少参数")
@@ -201,6 +354,22 @@ func parseSemanticOptions(args []string) (semanticOptions, error) {
 			opts.Target = strings.ToLower(strings.TrimSpace(args[i]))
 		case strings.HasPrefix(arg, "--target="):
 			opts.Target = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--target=")))
+		case arg == "--label-template":
+			if i+1 >= len(args) {
+				return semanticOptions{}, fmt.Errorf("`--label-template` 缺少参数")
+			}
+			i++
+			opts.LabelTemplate = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--label-template="):
+			opts.LabelTemplate = strings.TrimSpace(strings.TrimPrefix(arg, "--label-template="))
+		case arg == "--position-bias":
+			if i+1 >= len(args) {
+				return semanticOptions{}, fmt.Errorf("`--position-bias` 缺少参数")
+			}
+			i++
+			opts.PositionBias = strings.ToLower(strings.TrimSpace(args[i]))
+		case strings.HasPrefix(arg, "--position-bias="):
+			opts.PositionBias = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--position-bias=")))
 		case arg == "--provider":
 			if i+1 >= len(args) {
 				return semanticOptions{}, fmt.Errorf("`--provider` 缺少参数")
@@ -217,6 +386,38 @@ func parseSemanticOptions(args []string) (semanticOptions, error) {
 			opts.Model = strings.TrimSpace(args[i])
 		case strings.HasPrefix(arg, "--model="):
 			opts.Model = strings.TrimSpace(strings.TrimPrefix(arg, "--model="))
+		case arg == "--fallback-model":
+			if i+1 >= len(args) {
+				return semanticOptions{}, fmt.Errorf("`--fallback-model` 缺少参数")
+			}
+			i++
+			opts.FallbackModels = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--fallback-model="):
+			opts.FallbackModels = strings.TrimSpace(strings.TrimPrefix(arg, "--fallback-model="))
+		case arg == "--llm-timeout":
+			if i+1 >= len(args) {
+				return semanticOptions{}, fmt.Errorf("`--llm-timeout` 缺少参数")
+			}
+			i++
+			d, err := parseTimeoutValue(args[i])
+			if err != nil {
+				return semanticOptions{}, fmt.Errorf("`--llm-timeout` %v", err)
+			}
+			opts.LLMTimeout = d
+		case strings.HasPrefix(arg, "--llm-timeout="):
+			d, err := parseTimeoutValue(strings.TrimPrefix(arg, "--llm-timeout="))
+			if err != nil {
+				return semanticOptions{}, fmt.Errorf("`--llm-timeout` %v", err)
+			}
+			opts.LLMTimeout = d
+		case arg == "--proxy":
+			if i+1 >= len(args) {
+				return semanticOptions{}, fmt.Errorf("`--proxy` 缺少参数")
+			}
+			i++
+			opts.Proxy = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--proxy="):
+			opts.Proxy = strings.TrimSpace(strings.TrimPrefix(arg, "--proxy="))
 		case arg == "--base-url":
 			if i+1 >= len(args) {
 				return semanticOptions{}, fmt.Errorf("`--base-url` 缺少参数")
@@ -243,12 +444,14 @@ func parseSemanticOptions(args []string) (semanticOptions, error) {
 				return semanticOptions{}, fmt.Errorf("`--candidate-limit` 必须是整数")
 			}
 			opts.CandidateLimit = n
+			candidateLimitSet = true
 		case strings.HasPrefix(arg, "--candidate-limit="):
 			n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(arg, "--candidate-limit=")))
 			if err != nil {
 				return semanticOptions{}, fmt.Errorf("`--candidate-limit` 必须是整数")
 			}
 			opts.CandidateLimit = n
+			candidateLimitSet = true
 		case arg == "--preview-limit":
 			if i+1 >= len(args) {
 				return semanticOptions{}, fmt.Errorf("`--preview-limit` 缺少参数")
@@ -281,6 +484,38 @@ func parseSemanticOptions(args []string) (semanticOptions, error) {
 				return semanticOptions{}, fmt.Errorf("`--visual-diversity` 必须是 0-1 的小数")
 			}
 			opts.VisualDiversity = v
+		case arg == "--temperature":
+			if i+1 >= len(args) {
+				return semanticOptions{}, fmt.Errorf("`--temperature` 缺少参数")
+			}
+			i++
+			v, err := strconv.ParseFloat(strings.TrimSpace(args[i]), 64)
+			if err != nil {
+				return semanticOptions{}, fmt.Errorf("`--temperature` 必须是 0-2 的小数")
+			}
+			opts.Temperature = v
+		case strings.HasPrefix(arg, "--temperature="):
+			v, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimPrefix(arg, "--temperature=")), 64)
+			if err != nil {
+				return semanticOptions{}, fmt.Errorf("`--temperature` 必须是 0-2 的小数")
+			}
+			opts.Temperature = v
+		case arg == "--seed":
+			if i+1 >= len(args) {
+				return semanticOptions{}, fmt.Errorf("`--seed` 缺少参数")
+			}
+			i++
+			n, err := strconv.ParseInt(strings.TrimSpace(args[i]), 10, 64)
+			if err != nil {
+				return semanticOptions{}, fmt.Errorf("`--seed` 必须是整数")
+			}
+			opts.Seed = &n
+		case strings.HasPrefix(arg, "--seed="):
+			n, err := strconv.ParseInt(strings.TrimSpace(strings.TrimPrefix(arg, "--seed=")), 10, 64)
+			if err != nil {
+				return semanticOptions{}, fmt.Errorf("`--seed` 必须是整数")
+			}
+			opts.Seed = &n
 		case arg == "--top-k":
 			if i+1 >= len(args) {
 				return semanticOptions{}, fmt.Errorf("`--top-k` 缺少参数")
@@ -305,6 +540,22 @@ func parseSemanticOptions(args []string) (semanticOptions, error) {
 			opts.DecisionsPath = strings.TrimSpace(args[i])
 		case strings.HasPrefix(arg, "--decisions="):
 			opts.DecisionsPath = strings.TrimSpace(strings.TrimPrefix(arg, "--decisions="))
+		case arg == "--bundle-dir":
+			if i+1 >= len(args) {
+				return semanticOptions{}, fmt.Errorf("`--bundle-dir` 缺少参数")
+			}
+			i++
+			opts.BundleDir = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--bundle-dir="):
+			opts.BundleDir = strings.TrimSpace(strings.TrimPrefix(arg, "--bundle-dir="))
+		case arg == "--bundle":
+			if i+1 >= len(args) {
+				return semanticOptions{}, fmt.Errorf("`--bundle` 缺少参数")
+			}
+			i++
+			opts.Bundle = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--bundle="):
+			opts.Bundle = strings.TrimSpace(strings.TrimPrefix(arg, "--bundle="))
 		case strings.HasPrefix(arg, "-"):
 			return semanticOptions{}, fmt.Errorf("不支持的参数: %s", arg)
 		default:
@@ -319,16 +570,24 @@ func parseSemanticOptions(args []string) (semanticOptions, error) {
 		return semanticOptions{}, fmt.Errorf("缺少 asset_ref。用法: mingest semantic <asset_ref> [--target shorts] [--model gpt-4.1-mini] [--apply]")
 	}
 	switch opts.Target {
-	case "youtube", "bilibili", "shorts":
+	case "youtube", "bilibili", "shorts", "douyin", "xiaohongshu":
 	default:
-		return semanticOptions{}, fmt.Errorf("`--target` 仅支持 youtube|bilibili|shorts")
+		return semanticOptions{}, fmt.Errorf("`--target` 仅支持 youtube|bilibili|shorts|douyin|xiaohongshu")
 	}
 	switch opts.Provider {
-	case "auto", "openai", "openrouter":
+	case "auto", "openai", "openrouter", "anthropic", "gemini":
 	default:
-		return semanticOptions{}, fmt.Errorf("`--provider` 仅支持 auto|openai|openrouter")
+		return semanticOptions{}, fmt.Errorf("`--provider` 仅支持 auto|openai|openrouter|anthropic|gemini")
+	}
+	switch opts.PositionBias {
+	case semanticPositionBiasNone, semanticPositionBiasFront, semanticPositionBiasBack:
+	default:
+		return semanticOptions{}, fmt.Errorf("`--position-bias` 仅支持 front|back|none")
+	}
+	if err := validateClipLabelTemplate(opts.LabelTemplate); err != nil {
+		return semanticOptions{}, err
 	}
-	if opts.CandidateLimit <= 0 || opts.CandidateLimit > 100 {
+	if candidateLimitSet && (opts.CandidateLimit <= 0 || opts.CandidateLimit > 100) {
 		return semanticOptions{}, fmt.Errorf("`--candidate-limit` 需在 1-100")
 	}
 	if opts.PreviewLimit <= 0 || opts.PreviewLimit > 50 {
@@ -337,12 +596,81 @@ func parseSemanticOptions(args []string) (semanticOptions, error) {
 	if opts.VisualDiversity < 0 || opts.VisualDiversity > 1 {
 		return semanticOptions{}, fmt.Errorf("`--visual-diversity` 需在 0-1")
 	}
+	if opts.Temperature < 0 || opts.Temperature > 2 {
+		return semanticOptions{}, fmt.Errorf("`--temperature` 需在 0-2")
+	}
 	if opts.TopK <= 0 || opts.TopK > 10 {
 		return semanticOptions{}, fmt.Errorf("`--top-k` 需在 1-10")
 	}
+	if opts.VisualGapSec <= 0 {
+		return semanticOptions{}, fmt.Errorf("`--visual-gap-sec` 必须大于 0")
+	}
+	if opts.Pick && opts.DecisionsPath != "" {
+		return semanticOptions{}, fmt.Errorf("`--pick` 与 `--decisions` 不能同时使用")
+	}
+	loudnorm := &opts.Render.Loudnorm
+	if !loudnorm.Enabled && (loudnorm.TwoPass || loudnorm.TargetLUFS != 0) {
+		return semanticOptions{}, fmt.Errorf("`--loudness-target` / `--loudnorm-two-pass` 需配合 `--normalize-audio` 使用")
+	}
+	if loudnorm.Enabled {
+		if loudnorm.TargetLUFS == 0 {
+			loudnorm.TargetLUFS = loudnormDefaultTarget
+		}
+		if loudnorm.TargetLUFS < -70 || loudnorm.TargetLUFS > -5 {
+			return semanticOptions{}, fmt.Errorf("`--loudness-target` 需在 -70 到 -5 LUFS")
+		}
+	}
+	if opts.Render.Vertical && !semanticVerticalTarget(opts.Target) {
+		return semanticOptions{}, fmt.Errorf("`--vertical` 仅适用于 `--target shorts|douyin|xiaohongshu`")
+	}
+	if opts.Render.Aspect != "" && !contains(outputAspects, opts.Render.Aspect) {
+		return semanticOptions{}, fmt.Errorf("`--aspect` 仅支持 %s", strings.Join(outputAspects, "|"))
+	}
+	if opts.Render.Vertical {
+		if opts.Render.Aspect != "" && opts.Render.Aspect != "9:16" {
+			return semanticOptions{}, fmt.Errorf("`--vertical` 与 `--aspect %s` 冲突", opts.Render.Aspect)
+		}
+		opts.Render.Aspect = "9:16"
+	}
+	switch opts.Render.Reframe {
+	case "":
+		// Filled from --aspect or the plan's aspect in runSemanticPipeline.
+	case "center", "blur-pad":
+		if opts.Render.Aspect == "" {
+			return semanticOptions{}, fmt.Errorf("`--reframe` 需配合 `--vertical` 或 `--aspect` 使用")
+		}
+	default:
+		return semanticOptions{}, fmt.Errorf("`--reframe` 仅支持 center|blur-pad")
+	}
+	proxy, err := resolveProxy(opts.Proxy)
+	if err != nil {
+		return semanticOptions{}, err
+	}
+	opts.Proxy = proxy
 	return opts, nil
 }
 
+// semanticDefaultCandidateLimit budgets about one Stage A candidate per minute
+// of video, clamped to 1-100, so short clips and long lectures get
+// proportionate LLM input. It never drops below topK so short videos can
+// still fill the final picks.
+func semanticDefaultCandidateLimit(durationSec float64, topK int) int {
+	if durationSec <= 0 {
+		return defaultSemanticCandidateLimit
+	}
+	n := int(math.Round(durationSec / 60 * semanticCandidatesPerMinute))
+	if n < topK {
+		n = topK
+	}
+	if n < 1 {
+		n = 1
+	}
+	if n > 100 {
+		n = 100
+	}
+	return n
+}
+
 func runSemantic(opts semanticOptions) int {
 	state, exitCode := runSemanticPipeline(opts)
 	if opts.JSON {
@@ -371,7 +699,7 @@ func runSemanticPipeline(opts semanticOptions) (semanticRunState, int) {
 	}
 	state.Asset = asset
 
-	_, prepPlanPath, err := latestPrepBundle(asset)
+	_, prepPlanPath, err := resolvePrepBundle(asset, opts.BundleDir, opts.Bundle)
 	if err != nil {
 		state.Warnings = append(state.Warnings, err.Error())
 		return state, exitSemanticFailed
@@ -381,44 +709,115 @@ func runSemanticPipeline(opts semanticOptions) (semanticRunState, int) {
 		state.Warnings = append(state.Warnings, fmt.Sprintf("读取 prep-plan.json 失败: %v", err))
 		return state, exitSemanticFailed
 	}
+	if err := validatePrepPlan(plan); err != nil {
+		state.Warnings = append(state.Warnings, err.Error())
+		return state, exitSemanticFailed
+	}
 	state.PlanPath = prepPlanPath
 	state.Plan = plan
 
-	cues, subtitlePath, _ := loadDoctorSubtitle(plan)
+	if opts.CandidateLimit == 0 {
+		opts.CandidateLimit = semanticDefaultCandidateLimit(plan.Probe.DurationSec, opts.TopK)
+		logInfo("semantic.candidate_limit", "value", opts.CandidateLimit, "source", "duration", "duration_sec", plan.Probe.DurationSec)
+	} else {
+		logInfo("semantic.candidate_limit", "value", opts.CandidateLimit, "source", "flag")
+	}
+	state.CandidateLimit = opts.CandidateLimit
+
+	cues, subtitlePath, hasRealSubtitle := loadDoctorSubtitle(plan)
 	if len(cues) == 0 {
 		state.Warnings = append(state.Warnings, "未找到可用字幕条目（subtitle.srt/subtitle-template.srt）")
 		return state, exitSemanticFailed
 	}
 
-	artifacts, err := createSemanticArtifacts(asset)
-	if err != nil {
-		state.Warnings = append(state.Warnings, fmt.Sprintf("创建 semantic 输出目录失败: %v", err))
-		return state, exitSemanticFailed
+	var artifacts semanticArtifacts
+	resume := semanticResumeState{Version: semanticStateVersion, Stages: map[string]semanticStageState{}}
+	if opts.Resume {
+		artifacts, resume = findSemanticResume(asset, opts.BundleDir)
+		if artifacts.BundleDir == "" {
+			state.Warnings = append(state.Warnings, "未找到可续跑的 semantic 目录，将完整执行")
+		}
+	}
+	if artifacts.BundleDir == "" {
+		artifacts, err = createSemanticArtifacts(asset, opts.BundleDir)
+		if err != nil {
+			state.Warnings = append(state.Warnings, fmt.Sprintf("创建 semantic 输出目录失败: %v", err))
+			return state, exitSemanticFailed
+		}
 	}
 	state.Artifacts = artifacts
 
 	// Stage A: 基于字幕生成候选窗口
-	minSec, maxSec := semanticTargetDurationRange(opts.Target)
-	keyframes, keyframeErr := semanticDetectKeyframeBoundaries(asset.OutputPath)
-	if keyframeErr != nil {
-		state.Warnings = append(state.Warnings, fmt.Sprintf("镜头边界检测不可用，使用原字幕边界: %v", keyframeErr))
-	}
-	candidates := buildSemanticCandidates(cues, minSec, maxSec, keyframes)
-	candidates = semanticSelectTopCandidates(candidates, opts.CandidateLimit)
-	if len(candidates) == 0 {
-		state.Warnings = append(state.Warnings, "无法生成候选片段（字幕内容可能过短或不可解析）")
-		return state, exitSemanticFailed
+	if plan.Probe.AudioOnly {
+		state.Warnings = append(state.Warnings, "纯音频素材：跳过镜头边界检测、画面候选与视觉去重")
+	}
+	subtitleFingerprint := semanticFileFingerprint(subtitlePath)
+	if subtitleFingerprint == "" && plan.Embedded != nil {
+		sum := sha256.Sum256([]byte(plan.Embedded.Subtitle + plan.Embedded.SubtitleTemplate))
+		subtitleFingerprint = hex.EncodeToString(sum[:])
+	}
+	stageAKey := semanticStageFingerprint(
+		asset.AssetID,
+		subtitleFingerprint,
+		opts.Target,
+		strconv.Itoa(opts.CandidateLimit),
+		strconv.FormatBool(opts.VisualGaps),
+		strconv.FormatFloat(opts.VisualGapSec, 'f', -1, 64),
+		strconv.FormatBool(plan.Probe.AudioOnly),
+		fmt.Sprint(plan.Probe.SponsorSegments),
+	)
+	var candidates []semanticCandidate
+	if items, ok := resume.loadStage(artifacts.StageAPath, "a", semanticStageAVersion, stageAKey); ok {
+		if err := json.Unmarshal(items.Items, &candidates); err != nil || len(candidates) == 0 {
+			candidates = nil
+		} else {
+			state.Resumed = append(state.Resumed, "a")
+		}
 	}
-	if err := writeJSONFile(artifacts.StageAPath, map[string]interface{}{
-		"version":       "semantic-a-v1",
-		"created_at":    time.Now().UTC().Format(time.RFC3339),
-		"subtitle_path": subtitlePath,
-		"target":        opts.Target,
-		"keyframes":     len(keyframes),
-		"items":         candidates,
-	}); err != nil {
-		state.Warnings = append(state.Warnings, fmt.Sprintf("写入 Stage A 结果失败: %v", err))
-		return state, exitSemanticFailed
+	if candidates == nil {
+		minSec, maxSec := semanticTargetDurationRange(opts.Target)
+		var keyframes []float64
+		if !plan.Probe.AudioOnly {
+			var keyframeErr error
+			keyframes, keyframeErr = semanticDetectKeyframeBoundaries(asset.OutputPath)
+			if keyframeErr != nil {
+				state.Warnings = append(state.Warnings, fmt.Sprintf("镜头边界检测不可用，使用原字幕边界: %v", keyframeErr))
+			}
+		}
+		var sponsorDropped int
+		candidates, sponsorDropped = semanticDropSponsorCandidates(buildSemanticCandidates(cues, minSec, maxSec, keyframes), plan.Probe.SponsorSegments)
+		candidates = semanticSelectTopCandidates(candidates, opts.CandidateLimit)
+		visualCount := 0
+		if opts.VisualGaps && !plan.Probe.AudioOnly {
+			visual := buildSemanticVisualGapCandidates(cues, plan.Probe.DurationSec, minSec, maxSec, opts.VisualGapSec, keyframes)
+			var visualDropped int
+			visual, visualDropped = semanticDropSponsorCandidates(visual, plan.Probe.SponsorSegments)
+			sponsorDropped += visualDropped
+			candidates, visualCount = semanticMergeVisualCandidates(candidates, visual, opts.CandidateLimit)
+		}
+		if sponsorDropped > 0 {
+			state.Warnings = append(state.Warnings, fmt.Sprintf("已排除 %d 个与 SponsorBlock 段重叠的候选", sponsorDropped))
+		}
+		if len(candidates) == 0 {
+			state.Warnings = append(state.Warnings, "无法生成候选片段（字幕内容可能过短或不可解析）")
+			return state, exitSemanticFailed
+		}
+		if err := writeJSONFile(artifacts.StageAPath, map[string]interface{}{
+			"version":        semanticStageAVersion,
+			"created_at":     time.Now().UTC().Format(time.RFC3339),
+			"subtitle_path":  subtitlePath,
+			"target":         opts.Target,
+			"keyframes":      len(keyframes),
+			"visual_gaps":    opts.VisualGaps,
+			"visual_gap_sec": opts.VisualGapSec,
+			"visual_count":   visualCount,
+			"items":          candidates,
+		}); err != nil {
+			state.Warnings = append(state.Warnings, fmt.Sprintf("写入 Stage A 结果失败: %v", err))
+			return state, exitSemanticFailed
+		}
+		resume.complete("a", semanticStageAVersion, stageAKey)
+		resume.save(artifacts.StatePath)
 	}
 
 	// Stage B: GPT 语义重排
@@ -430,20 +829,55 @@ func runSemanticPipeline(opts semanticOptions) (semanticRunState, int) {
 		}
 		state.Provider = llmCfg.Provider
 		state.Model = llmCfg.Model
-		llmItems, raw, err := semanticRerankWithLLM(candidates, opts.Target, llmCfg)
-		if err != nil {
-			state.Warnings = append(state.Warnings, fmt.Sprintf("Stage B GPT 重排失败，已回退规则分: %v", err))
-		} else {
-			usedLLM = true
-			candidates = applySemanticLLMScores(candidates, llmItems)
-			_ = writeJSONFile(artifacts.StageBPath, map[string]interface{}{
-				"version":    "semantic-b-v1",
-				"created_at": time.Now().UTC().Format(time.RFC3339),
-				"provider":   llmCfg.Provider,
-				"model":      llmCfg.Model,
-				"raw":        raw,
-				"items":      llmItems,
-			})
+		// Stage B depends on Stage A's key, so new candidates invalidate it.
+		stageBKey := semanticStageFingerprint(stageAKey, llmCfg.Provider, llmCfg.Model, llmCfg.BaseURL, strings.Join(llmCfg.FallbackModels, ","), semanticSamplingKey(llmCfg))
+		var llmItems []semanticLLMItem
+		if items, ok := resume.loadStage(artifacts.StageBPath, "b", semanticStageBVersion, stageBKey); ok {
+			if err := json.Unmarshal(items.Items, &llmItems); err == nil && len(llmItems) > 0 {
+				usedLLM = true
+				candidates = applySemanticLLMScores(candidates, llmItems)
+				state.Resumed = append(state.Resumed, "b")
+				if items.Model != "" {
+					state.Model = items.Model
+				}
+			}
+		}
+		if !usedLLM {
+			items, raw, model, attempted, retries, err := semanticRerankWithFallback(candidates, opts.Target, llmCfg)
+			if retries > 0 {
+				state.Warnings = append(state.Warnings, fmt.Sprintf("Stage B 模型调用遇到限流或服务端错误，共重试 %d 次", retries))
+			}
+			if len(attempted) > 1 {
+				state.Warnings = append(state.Warnings, fmt.Sprintf("主模型 %s 调用失败，已依次尝试: %s", llmCfg.Model, strings.Join(attempted, " → ")))
+			}
+			if err != nil {
+				state.Warnings = append(state.Warnings, fmt.Sprintf("Stage B GPT 重排失败，已回退规则分: %v", err))
+			} else {
+				usedLLM = true
+				state.Model = model
+				// Render from the candidates as sent, before scores are applied.
+				systemPrompt, userPrompt := semanticRerankPrompts(candidates, opts.Target)
+				candidates = applySemanticLLMScores(candidates, items)
+				stageB := map[string]interface{}{
+					"version":          semanticStageBVersion,
+					"created_at":       time.Now().UTC().Format(time.RFC3339),
+					"provider":         llmCfg.Provider,
+					"model":            model,
+					"attempted_models": attempted,
+					"temperature":      llmCfg.Temperature,
+					"seed":             llmCfg.Seed,
+					"raw":              raw,
+					"items":            items,
+				}
+				if opts.SavePrompt {
+					stageB["system_prompt"] = systemPrompt
+					stageB["user_prompt"] = userPrompt
+				}
+				if err := writeJSONFile(artifacts.StageBPath, stageB); err == nil {
+					resume.complete("b", semanticStageBVersion, stageBKey)
+					resume.save(artifacts.StatePath)
+				}
+			}
 		}
 	}
 	if !usedLLM {
@@ -453,41 +887,116 @@ func runSemanticPipeline(opts semanticOptions) (semanticRunState, int) {
 	if state.Model == "" {
 		state.Model = defaultSemanticModelOpenAI
 	}
-	visualHashCount := semanticAnnotateVisualHashes(asset.OutputPath, candidates)
-	if visualHashCount == 0 {
-		state.Warnings = append(state.Warnings, "视觉去重不可用（未能生成候选帧哈希），将仅使用语义/时间多样性")
-	} else if visualHashCount < len(candidates)/3 {
-		state.Warnings = append(state.Warnings, fmt.Sprintf("仅 %d/%d 个候选生成了视觉哈希，视觉去重能力受限", visualHashCount, len(candidates)))
+	if !plan.Probe.AudioOnly {
+		visualHashCount := semanticAnnotateVisualHashes(asset.OutputPath, candidates)
+		if visualHashCount == 0 {
+			state.Warnings = append(state.Warnings, "视觉去重不可用（未能生成候选帧哈希），将仅使用语义/时间多样性")
+		} else if visualHashCount < len(candidates)/3 {
+			state.Warnings = append(state.Warnings, fmt.Sprintf("仅 %d/%d 个候选生成了视觉哈希，视觉去重能力受限", visualHashCount, len(candidates)))
+		}
 	}
 
 	// Stage C: 约束选 3 段
-	selected := semanticPickFinalCandidates(candidates, opts.TopK, opts.Target, opts.VisualDiversity)
+	timelineSec := plan.Probe.DurationSec
+	if timelineSec <= 0 {
+		_, timelineSec = semanticTimelineBounds(candidates)
+	}
+	candidates = semanticApplyPositionBias(candidates, opts.PositionBias, timelineSec)
+	picked := semanticPickFinalCandidates(candidates, opts.TopK, opts.Target, opts.VisualDiversity)
+	spreadEnforced := false
+	if opts.EnforceSpread {
+		picked, spreadEnforced = semanticEnforceSpread(candidates, picked, timelineSec, doctorThresholdFor(opts.Target, false), opts.VisualDiversity)
+		if spreadEnforced {
+			logInfo("semantic.spread_enforced", "asset_id", asset.AssetID, "replaced_with", picked[len(picked)-1].ID)
+		}
+	}
+	selected := semanticFinalizeOrder(picked, opts.Chronological)
 	if len(selected) == 0 {
 		state.Warnings = append(state.Warnings, "Stage C 未能选出有效片段")
 		return state, exitSemanticFailed
 	}
+	coverage := semanticCoverageSpan(selected, timelineSec)
+	if len(selected) >= 2 && (coverage < semanticMinCoverageSpan || !semanticHasFinalThirdPick(selected, timelineSec)) {
+		logWarn("semantic.coverage_low", "asset_id", asset.AssetID, "coverage_span", coverage, "duration_sec", timelineSec)
+		msg := fmt.Sprintf("入选片段只覆盖时间线的 %.0f%%，或最后三分之一没有片段", coverage*100)
+		if !opts.EnforceSpread {
+			msg += "；可加 --enforce-spread 强制从最后三分之一选取一段"
+		}
+		state.Warnings = append(state.Warnings, msg)
+	}
 	if err := writeJSONFile(artifacts.StageCPath, map[string]interface{}{
 		"version":          "semantic-c-v1",
 		"created_at":       time.Now().UTC().Format(time.RFC3339),
 		"target":           opts.Target,
 		"top_k":            opts.TopK,
 		"visual_diversity": opts.VisualDiversity,
+		"chronological":    opts.Chronological,
+		"position_bias":    opts.PositionBias,
+		"coverage_span":    roundMillis(coverage),
+		"spread_enforced":  spreadEnforced,
 		"items":            selected,
 	}); err != nil {
 		state.Warnings = append(state.Warnings, fmt.Sprintf("写入 Stage C 结果失败: %v", err))
 		return state, exitSemanticFailed
 	}
+	if opts.ExplainScores {
+		state.Explain = semanticExplainScores(candidates, selected, opts.TopK, opts.VisualDiversity, usedLLM)
+		explainPath := filepath.Join(artifacts.BundleDir, "stage-c-explain.json")
+		if err := writeJSONFile(explainPath, map[string]interface{}{
+			"version":          "semantic-c-explain-v1",
+			"created_at":       time.Now().UTC().Format(time.RFC3339),
+			"final_score":      "0.55*base_score + 0.45*semantic_score",
+			"selection_score":  fmt.Sprintf("%.2f*final_score + %.2f*novelty (+ bucket spread bonus)", semanticSelectScoreWeight, semanticSelectNoveltyWeight),
+			"visual_diversity": opts.VisualDiversity,
+			"items":            state.Explain,
+		}); err != nil {
+			state.Warnings = append(state.Warnings, fmt.Sprintf("写入 Stage C 评分说明失败: %v", err))
+		} else {
+			artifacts.StageCExplain = explainPath
+			state.Artifacts = artifacts
+		}
+	}
 
 	// Stage D: 预览+评审包
 	previewCandidates := semanticTopPreviewCandidates(candidates, selected, opts.PreviewLimit, opts.Target, opts.VisualDiversity)
-	if err := semanticGeneratePreviewFiles(asset.OutputPath, previewCandidates, artifacts.PreviewDir); err != nil {
+	render := opts.Render
+	if render.Aspect == "" && !plan.Probe.AudioOnly {
+		render.Aspect = plan.Options.Aspect
+	}
+	if render.Aspect != "" && render.Reframe == "" {
+		render.Reframe = "center"
+	}
+	if render.Loudnorm.Enabled && plan.Probe.AudioTracks == 0 {
+		state.Warnings = append(state.Warnings, "素材没有音轨，跳过响度标准化")
+		render.Loudnorm.Enabled = false
+	}
+	if render.BurnSubs {
+		if hasRealSubtitle {
+			render.burnCues = cues
+		} else {
+			state.Warnings = append(state.Warnings, "仅有字幕模板，跳过预览字幕烧录")
+			render.BurnSubs = false
+		}
+	}
+	if err := semanticGeneratePreviewFiles(asset.OutputPath, previewCandidates, artifacts.PreviewDir, render); err != nil {
 		state.Warnings = append(state.Warnings, fmt.Sprintf("生成预览视频失败（将继续，使用原始时间戳评审）: %v", err))
 	}
-	if err := writeSemanticReviewHTML(artifacts.ReviewHTMLPath, previewCandidates, selected, artifacts.ReviewDecisions); err != nil {
+	embedded, err := writeSemanticReviewHTML(artifacts.ReviewHTMLPath, previewCandidates, selected, artifacts.ReviewDecisions, opts.SelfContained)
+	if err != nil {
 		state.Warnings = append(state.Warnings, fmt.Sprintf("写入 review.html 失败: %v", err))
 		return state, exitSemanticFailed
 	}
+	if embedded > semanticReviewEmbedWarnBytes {
+		state.Warnings = append(state.Warnings, fmt.Sprintf("review.html 内嵌了 %.1f MiB 预览视频，体积较大，浏览器打开和邮件发送可能较慢", float64(embedded)/(1<<20)))
+	}
 	decisionTemplate := semanticBuildDecisionTemplate(asset.AssetID, opts.Target, previewCandidates, selected)
+	if opts.Pick {
+		if !stdinIsTerminal() {
+			state.Warnings = append(state.Warnings, "`--pick` 需要在交互终端中运行")
+			return state, exitSemanticFailed
+		}
+		semanticPickDecisions(&decisionTemplate, previewCandidates, os.Stdin, os.Stderr)
+	}
 	if err := writeJSONFile(artifacts.ReviewDecisions, decisionTemplate); err != nil {
 		state.Warnings = append(state.Warnings, fmt.Sprintf("写入评审模板失败: %v", err))
 		return state, exitSemanticFailed
@@ -507,9 +1016,13 @@ func runSemanticPipeline(opts semanticOptions) (semanticRunState, int) {
 			state.Warnings = append(state.Warnings, fmt.Sprintf("读取评审决策失败: %v", err))
 			return state, exitSemanticFailed
 		}
+		finalSelected = semanticFinalizeOrder(finalSelected, opts.Chronological)
 
 		planAfter := plan
-		planAfter.Clips = semanticCandidatesToPrepClips(finalSelected)
+		planAfter.Clips = semanticCandidatesToPrepClips(finalSelected, firstNonEmpty(opts.LabelTemplate, plan.Options.LabelTemplate), plan.Asset.Title)
+		if opts.Render.Aspect != "" {
+			planAfter.Options.Aspect = opts.Render.Aspect
+		}
 		checks := runDoctorChecks(doctorOptions{
 			Target: opts.Target,
 			Strict: opts.Strict,
@@ -534,6 +1047,12 @@ func runSemanticPipeline(opts semanticOptions) (semanticRunState, int) {
 		state.Selected = finalSelected
 		state.Artifacts.AppliedPlanPath = prepPlanPath
 		state.Artifacts.BackupPlanPath = backupPath
+
+		clipSubtitles, err := writeSemanticClipSubtitles(filepath.Join(artifacts.BundleDir, "clips"), planAfter.Clips, cues)
+		if err != nil {
+			state.Warnings = append(state.Warnings, fmt.Sprintf("写入片段字幕失败: %v", err))
+		}
+		state.Artifacts.ClipSubtitles = clipSubtitles
 	}
 
 	return state, exitOK
@@ -551,15 +1070,22 @@ func resolveSemanticLLMConfig(opts semanticOptions) (semanticLLMConfig, error) {
 
 	provider := strings.TrimSpace(opts.Provider)
 	if provider == "" || provider == "auto" {
-		if strings.TrimSpace(os.Getenv("MINGEST_OPENROUTER_API_KEY")) != "" || strings.TrimSpace(os.Getenv("OPENROUTER_API_KEY")) != "" {
-			provider = "openrouter"
-		} else {
-			provider = "openai"
+		// Auto picks the first provider with a key, OpenRouter first as before.
+		provider = "openai"
+		for _, p := range []string{"openrouter", "openai", "anthropic", "gemini"} {
+			if semanticProviderEnvKey(p) != "" {
+				provider = p
+				break
+			}
 		}
 	}
 
 	cfg := semanticLLMConfig{
-		Provider: provider,
+		Provider:    provider,
+		Proxy:       opts.Proxy,
+		Timeout:     resolveSemanticLLMTimeout(opts.LLMTimeout),
+		Temperature: opts.Temperature,
+		Seed:        opts.Seed,
 	}
 	switch provider {
 	case "openrouter":
@@ -569,12 +1095,29 @@ func resolveSemanticLLMConfig(opts semanticOptions) (semanticLLMConfig, error) {
 		if !strings.Contains(cfg.Model, "/") {
 			cfg.Model = "openai/" + cfg.Model
 		}
+		cfg.FallbackModels = semanticFallbackModels(opts.FallbackModels, provider, cfg.Model)
+		for i, m := range cfg.FallbackModels {
+			if !strings.Contains(m, "/") {
+				cfg.FallbackModels[i] = "openai/" + m
+			}
+		}
 		cfg.Referer = firstNonEmpty(strings.TrimSpace(os.Getenv("MINGEST_OPENROUTER_REFERER")), "https://mingest.local")
 		cfg.Title = firstNonEmpty(strings.TrimSpace(os.Getenv("MINGEST_OPENROUTER_TITLE")), "mingest")
 	case "openai":
-		cfg.APIKey = firstNonEmpty(strings.TrimSpace(opts.APIKey), strings.TrimSpace(os.Getenv("MINGEST_OPENAI_API_KEY")), strings.TrimSpace(os.Getenv("OPENAI_API_KEY")))
+		cfg.APIKey = firstNonEmpty(strings.TrimSpace(opts.APIKey), semanticProviderEnvKey(provider))
 		cfg.BaseURL = strings.TrimSpace(opts.BaseURL)
 		cfg.Model = firstNonEmpty(strings.TrimSpace(opts.Model), strings.TrimSpace(os.Getenv("MINGEST_LLM_MODEL")), defaultSemanticModelOpenAI)
+		cfg.FallbackModels = semanticFallbackModels(opts.FallbackModels, provider, cfg.Model)
+	case "anthropic":
+		cfg.APIKey = firstNonEmpty(strings.TrimSpace(opts.APIKey), semanticProviderEnvKey(provider))
+		cfg.BaseURL = firstNonEmpty(strings.TrimSpace(opts.BaseURL), strings.TrimSpace(os.Getenv("MINGEST_ANTHROPIC_BASE_URL")), defaultAnthropicBaseURL)
+		cfg.Model = firstNonEmpty(strings.TrimSpace(opts.Model), strings.TrimSpace(os.Getenv("MINGEST_LLM_MODEL")), defaultSemanticModelAnthropic)
+		cfg.FallbackModels = semanticFallbackModels(opts.FallbackModels, provider, cfg.Model)
+	case "gemini":
+		cfg.APIKey = firstNonEmpty(strings.TrimSpace(opts.APIKey), semanticProviderEnvKey(provider))
+		cfg.BaseURL = firstNonEmpty(strings.TrimSpace(opts.BaseURL), strings.TrimSpace(os.Getenv("MINGEST_GEMINI_BASE_URL")), defaultGeminiBaseURL)
+		cfg.Model = firstNonEmpty(strings.TrimSpace(opts.Model), strings.TrimSpace(os.Getenv("MINGEST_LLM_MODEL")), defaultSemanticModelGemini)
+		cfg.FallbackModels = semanticFallbackModels(opts.FallbackModels, provider, cfg.Model)
 	default:
 		return semanticLLMConfig{}, fmt.Errorf("不支持的 provider: %s", provider)
 	}
@@ -583,6 +1126,10 @@ func resolveSemanticLLMConfig(opts semanticOptions) (semanticLLMConfig, error) {
 		switch provider {
 		case "openrouter":
 			return semanticLLMConfig{}, errors.New("未设置 OpenRouter API Key。可用 `--api-key` 或环境变量 `MINGEST_OPENROUTER_API_KEY` / `OPENROUTER_API_KEY`")
+		case "anthropic":
+			return semanticLLMConfig{}, errors.New("未设置 Anthropic API Key。可用 `--api-key` 或环境变量 `MINGEST_ANTHROPIC_API_KEY` / `ANTHROPIC_API_KEY`")
+		case "gemini":
+			return semanticLLMConfig{}, errors.New("未设置 Gemini API Key。可用 `--api-key` 或环境变量 `MINGEST_GEMINI_API_KEY` / `GEMINI_API_KEY`")
 		default:
 			return semanticLLMConfig{}, errors.New("未设置 OpenAI API Key。可用 `--api-key` 或环境变量 `MINGEST_OPENAI_API_KEY` / `OPENAI_API_KEY`")
 		}
@@ -590,7 +1137,159 @@ func resolveSemanticLLMConfig(opts semanticOptions) (semanticLLMConfig, error) {
 	return cfg, nil
 }
 
-func semanticRerankWithLLM(candidates []semanticCandidate, target string, cfg semanticLLMConfig) ([]semanticLLMItem, string, error) {
+// semanticDefaultFallbackModels is the built-in chain per provider, used
+// unless --fallback-model is given.
+var semanticDefaultFallbackModels = map[string][]string{
+	"openai":     {"gpt-4o-mini"},
+	"openrouter": {"openai/gpt-4o-mini"},
+	"anthropic":  {"claude-3-haiku-20240307"},
+	"gemini":     {"gemini-1.5-flash"},
+}
+
+// semanticFallbackModels parses the comma-separated --fallback-model value
+// ("none" disables fallback) and drops the primary model from the chain.
+func semanticFallbackModels(raw, provider, primary string) []string {
+	var models []string
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "":
+		models = semanticDefaultFallbackModels[provider]
+	case "none":
+		return nil
+	default:
+		models = strings.Split(raw, ",")
+	}
+	out := make([]string, 0, len(models))
+	for _, m := range models {
+		m = strings.TrimSpace(m)
+		if m == "" || m == primary || contains(out, m) {
+			continue
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// semanticRetryableLLMError reports whether another model may succeed where
+// this one failed: rate limits, server errors, unknown/unavailable models and
+// timeouts. Bad output from a model that did answer is not retried.
+func semanticRetryableLLMError(err error) bool {
+	var apiErr *openai.Error
+	if errors.As(err, &apiErr) {
+		switch code := apiErr.StatusCode; {
+		case code == http.StatusTooManyRequests, code >= 500:
+			return true
+		case code == http.StatusNotFound:
+			return true
+		case code == http.StatusBadRequest:
+			return strings.Contains(strings.ToLower(apiErr.Error()), "model")
+		}
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// semanticProviderEnvKey returns the API key set in the environment for provider.
+func semanticProviderEnvKey(provider string) string {
+	var names []string
+	switch provider {
+	case "openrouter":
+		names = []string{"MINGEST_OPENROUTER_API_KEY", "OPENROUTER_API_KEY"}
+	case "openai":
+		names = []string{"MINGEST_OPENAI_API_KEY", "OPENAI_API_KEY"}
+	case "anthropic":
+		names = []string{"MINGEST_ANTHROPIC_API_KEY", "ANTHROPIC_API_KEY"}
+	case "gemini":
+		names = []string{"MINGEST_GEMINI_API_KEY", "GEMINI_API_KEY"}
+	}
+	for _, n := range names {
+		if v := strings.TrimSpace(os.Getenv(n)); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+const (
+	defaultSemanticLLMTimeout  = 90 * time.Second
+	defaultSemanticTemperature = 0.2
+	// semanticLLMMaxRetries bounds retries of one model on 429/5xx before
+	// moving on to the fallback chain.
+	semanticLLMMaxRetries     = 2
+	semanticLLMRetryBaseDelay = 2 * time.Second
+	semanticLLMRetryMaxDelay  = 30 * time.Second
+)
+
+// semanticSamplingKey folds temperature and seed into the Stage B resume
+// key so a changed sampling setup re-runs the rerank.
+func semanticSamplingKey(cfg semanticLLMConfig) string {
+	key := strconv.FormatFloat(cfg.Temperature, 'f', -1, 64)
+	if cfg.Seed != nil {
+		key += "/" + strconv.FormatInt(*cfg.Seed, 10)
+	}
+	return key
+}
+
+// resolveSemanticLLMTimeout prefers --llm-timeout, then MINGEST_LLM_TIMEOUT,
+// then the 90s default.
+func resolveSemanticLLMTimeout(flagValue time.Duration) time.Duration {
+	if flagValue > 0 {
+		return flagValue
+	}
+	raw := strings.TrimSpace(os.Getenv("MINGEST_LLM_TIMEOUT"))
+	if raw == "" {
+		return defaultSemanticLLMTimeout
+	}
+	d, err := parseTimeoutValue(raw)
+	if err != nil {
+		logWarn("semantic.llm_timeout_env_invalid", "env", "MINGEST_LLM_TIMEOUT", "value", raw, "error", err)
+		return defaultSemanticLLMTimeout
+	}
+	return d
+}
+
+// semanticLLMClient sends one rerank prompt and returns the raw model output.
+// Every provider is reached through an OpenAI-compatible endpoint; they only
+// differ in which structured-output modes the endpoint accepts.
+type semanticLLMClient interface {
+	Complete(ctx context.Context, systemPrompt, userPrompt string) (string, error)
+}
+
+type openAICompatClient struct {
+	client      openai.Client
+	model       string
+	temperature float64
+	seed        *int64
+	// jsonSchema requests strict json_schema output, falling back to
+	// json_object when the gateway rejects it. Without it the prompt and
+	// the caller's parser carry the format on their own.
+	jsonSchema bool
+	schema     llmOutputSchema
+}
+
+// llmOutputSchema is the strict JSON output a prompt asks for.
+type llmOutputSchema struct {
+	Name        string
+	Description string
+	Schema      map[string]interface{}
+}
+
+var semanticRerankOutputSchema = llmOutputSchema{
+	Name:        "semantic_rerank_result",
+	Description: "为每个候选返回语义评分与类型",
+	Schema:      semanticLLMResponseSchema(),
+}
+
+func newSemanticLLMClient(cfg semanticLLMConfig) semanticLLMClient {
+	return newLLMClient(cfg, semanticRerankOutputSchema)
+}
+
+// newLLMClient builds an OpenAI-compatible client for cfg's provider that
+// requests schema as structured output where the provider supports it.
+func newLLMClient(cfg semanticLLMConfig, schema llmOutputSchema) semanticLLMClient {
 	clientOpts := []option.RequestOption{
 		option.WithAPIKey(cfg.APIKey),
 	}
@@ -601,11 +1300,129 @@ func semanticRerankWithLLM(candidates []semanticCandidate, target string, cfg se
 		clientOpts = append(clientOpts, option.WithHeader("HTTP-Referer", cfg.Referer))
 		clientOpts = append(clientOpts, option.WithHeader("X-Title", cfg.Title))
 	}
+	// Retries are ours (semanticCompleteWithRetry) so they can be counted.
+	clientOpts = append(clientOpts, option.WithMaxRetries(0))
+	if cfg.Proxy != "" {
+		// resolveProxy already validated the URL.
+		proxyURL, _ := url.Parse(cfg.Proxy)
+		clientOpts = append(clientOpts, option.WithHTTPClient(&http.Client{
+			Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)},
+		}))
+	}
 
-	client := openai.NewClient(clientOpts...)
+	return &openAICompatClient{
+		client:      openai.NewClient(clientOpts...),
+		model:       cfg.Model,
+		temperature: cfg.Temperature,
+		seed:        cfg.Seed,
+		// Anthropic's OpenAI-compatible endpoint ignores response_format.
+		jsonSchema: cfg.Provider != "anthropic",
+		schema:     schema,
+	}
+}
 
+func (c *openAICompatClient) Complete(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
+	params := openai.ChatCompletionNewParams{
+		Messages: []openai.ChatCompletionMessageParamUnion{
+			openai.SystemMessage(systemPrompt),
+			openai.UserMessage(userPrompt),
+		},
+		Model:       c.model,
+		Temperature: openai.Float(c.temperature),
+	}
+	if c.seed != nil {
+		params.Seed = openai.Int(*c.seed)
+	}
+	if c.jsonSchema {
+		params.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
+			OfJSONSchema: &shared.ResponseFormatJSONSchemaParam{
+				JSONSchema: shared.ResponseFormatJSONSchemaJSONSchemaParam{
+					Name:        c.schema.Name,
+					Description: openai.String(c.schema.Description),
+					Strict:      openai.Bool(true),
+					Schema:      c.schema.Schema,
+				},
+			},
+		}
+	}
+	resp, err := c.client.Chat.Completions.New(ctx, params)
+	if err != nil && c.jsonSchema {
+		// 某些网关对 json_schema 支持不完整，回退到 json_object 并继续做强校验解析。
+		if semanticShouldFallbackJSONMode(err) {
+			params.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
+				OfJSONObject: &shared.ResponseFormatJSONObjectParam{Type: "json_object"},
+			}
+			resp, err = c.client.Chat.Completions.New(ctx, params)
+			if err == nil {
+				// Remember the downgrade so network retries skip the schema attempt.
+				c.jsonSchema = false
+			}
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Choices) == 0 {
+		return "", errors.New("模型未返回任何候选结果")
+	}
+	return strings.TrimSpace(resp.Choices[0].Message.Content), nil
+}
+
+// semanticRerankWithFallback tries the primary model, then each fallback
+// model while the failure is retryable. It returns the model that answered
+// and every model attempted, in order, plus the total retry count.
+func semanticRerankWithFallback(candidates []semanticCandidate, target string, cfg semanticLLMConfig) ([]semanticLLMItem, string, string, []string, int, error) {
+	models := append([]string{cfg.Model}, cfg.FallbackModels...)
+	var attempted []string
+	var lastErr error
+	totalRetries := 0
+	for _, m := range models {
+		attempted = append(attempted, m)
+		try := cfg
+		try.Model = m
+		items, raw, retries, err := semanticRerankWithLLM(candidates, target, try)
+		totalRetries += retries
+		if err == nil {
+			return items, raw, m, attempted, totalRetries, nil
+		}
+		lastErr = err
+		if !semanticRetryableLLMError(err) {
+			break
+		}
+		logWarn("semantic.llm_model_failed", "model", m, "error", err)
+	}
+	return nil, "", "", attempted, totalRetries, lastErr
+}
+
+func semanticRerankWithLLM(candidates []semanticCandidate, target string, cfg semanticLLMConfig) ([]semanticLLMItem, string, int, error) {
+	systemPrompt, userPrompt := semanticRerankPrompts(candidates, target)
+	raw, retries, err := semanticCompleteWithRetry(newSemanticLLMClient(cfg), cfg.Timeout, systemPrompt, userPrompt)
+	if err != nil {
+		return nil, "", retries, err
+	}
+	if raw == "" {
+		return nil, "", retries, errors.New("模型返回为空")
+	}
+
+	parsed, err := semanticParseLLMResponse(raw)
+	if err != nil {
+		return nil, raw, retries, err
+	}
+	if len(parsed.Items) == 0 {
+		return nil, raw, retries, errors.New("模型返回 items 为空")
+	}
+	return parsed.Items, raw, retries, nil
+}
+
+// semanticRerankPrompts renders the Stage B system and user prompts. It is
+// deterministic, so --save-prompt can re-render exactly what was sent.
+func semanticRerankPrompts(candidates []semanticCandidate, target string) (string, string) {
 	items := make([]map[string]interface{}, 0, len(candidates))
 	for _, c := range candidates {
+		// Visual gap candidates carry no text; the model has nothing to judge.
+		if c.Type == semanticTypeVisual {
+			continue
+		}
 		items = append(items, map[string]interface{}{
 			"id":         c.ID,
 			"start_sec":  roundMillis(c.StartSec),
@@ -631,57 +1448,44 @@ func semanticRerankWithLLM(candidates []semanticCandidate, target string, cfg se
 		"输出格式:\n" +
 		`{"items":[{"id":"...","semantic_score":0.0,"type":"hook","reason":"..."}]}` + "\n\n" +
 		"候选数据:\n" + string(payloadBytes)
+	return systemPrompt, userPrompt
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
-	defer cancel()
-
-	params := openai.ChatCompletionNewParams{
-		Messages: []openai.ChatCompletionMessageParamUnion{
-			openai.SystemMessage(systemPrompt),
-			openai.UserMessage(userPrompt),
-		},
-		Model:       cfg.Model,
-		Temperature: openai.Float(0.2),
-		ResponseFormat: openai.ChatCompletionNewParamsResponseFormatUnion{
-			OfJSONSchema: &shared.ResponseFormatJSONSchemaParam{
-				JSONSchema: shared.ResponseFormatJSONSchemaJSONSchemaParam{
-					Name:        "semantic_rerank_result",
-					Description: openai.String("为每个候选返回语义评分与类型"),
-					Strict:      openai.Bool(true),
-					Schema:      semanticLLMResponseSchema(),
-				},
-			},
-		},
+// semanticCompleteWithRetry calls the client with a fresh timeout per
+// attempt, retrying 429/5xx responses with exponential backoff (or the
+// server's Retry-After). It returns how many retries were made.
+func semanticCompleteWithRetry(client semanticLLMClient, timeout time.Duration, systemPrompt, userPrompt string) (string, int, error) {
+	if timeout <= 0 {
+		timeout = defaultSemanticLLMTimeout
 	}
-	resp, err := client.Chat.Completions.New(ctx, params)
-	if err != nil {
-		// 某些网关对 json_schema 支持不完整，回退到 json_object 并继续做强校验解析。
-		if semanticShouldFallbackJSONMode(err) {
-			params.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
-				OfJSONObject: &shared.ResponseFormatJSONObjectParam{Type: "json_object"},
-			}
-			resp, err = client.Chat.Completions.New(ctx, params)
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		raw, err := client.Complete(ctx, systemPrompt, userPrompt)
+		cancel()
+		if err == nil {
+			return raw, attempt, nil
 		}
+		var apiErr *openai.Error
+		if attempt >= semanticLLMMaxRetries || !errors.As(err, &apiErr) || !semanticRetryableStatus(apiErr.StatusCode) {
+			return "", attempt, err
+		}
+		delay := semanticLLMRetryDelay(apiErr, attempt)
+		logWarn("semantic.llm_retry", "attempt", attempt+1, "status", apiErr.StatusCode, "delay", delay.String())
+		time.Sleep(delay)
 	}
-	if err != nil {
-		return nil, "", err
-	}
-	if len(resp.Choices) == 0 {
-		return nil, "", errors.New("模型未返回任何候选结果")
-	}
-	raw := strings.TrimSpace(resp.Choices[0].Message.Content)
-	if raw == "" {
-		return nil, "", errors.New("模型返回为空")
-	}
+}
 
-	parsed, err := semanticParseLLMResponse(raw)
-	if err != nil {
-		return nil, raw, err
-	}
-	if len(parsed.Items) == 0 {
-		return nil, raw, errors.New("模型返回 items 为空")
+func semanticRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+func semanticLLMRetryDelay(apiErr *openai.Error, attempt int) time.Duration {
+	if apiErr.Response != nil {
+		if secs, err := strconv.Atoi(strings.TrimSpace(apiErr.Response.Header.Get("Retry-After"))); err == nil && secs >= 0 {
+			return min(time.Duration(secs)*time.Second, semanticLLMRetryMaxDelay)
+		}
 	}
-	return parsed.Items, raw, nil
+	return min(semanticLLMRetryBaseDelay<<attempt, semanticLLMRetryMaxDelay)
 }
 
 func semanticShouldFallbackJSONMode(err error) bool {
@@ -847,7 +1651,7 @@ func applySemanticLLMScores(candidates []semanticCandidate, llmItems []semanticL
 	for _, c := range candidates {
 		item, ok := m[c.ID]
 		semanticScore := c.BaseScore
-		if ok {
+		if ok && c.Type != semanticTypeVisual {
 			semanticScore = clamp01(item.SemanticScore)
 			c.Type = normalizeSemanticType(item.Type, c.Type)
 			c.Reason = strings.TrimSpace(item.Reason)
@@ -898,51 +1702,174 @@ func buildSemanticCandidates(cues []subtitleCue, minSec, maxSec float64, keyfram
 		return nil
 	}
 
-	out := make([]semanticCandidate, 0, 256)
-	for i := 0; i < len(clean); i++ {
-		var b strings.Builder
-		start := clean[i].StartSec
-		for j := i; j < len(clean); j++ {
-			if b.Len() > 0 {
-				b.WriteByte(' ')
-			}
-			b.WriteString(clean[j].Text)
-			end := clean[j].EndSec
+	out := make([]semanticCandidate, 0, 256)
+	for i := 0; i < len(clean); i++ {
+		var b strings.Builder
+		start := clean[i].StartSec
+		for j := i; j < len(clean); j++ {
+			if b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteString(clean[j].Text)
+			end := clean[j].EndSec
+			clipStart, clipEnd := semanticSnapCandidateToBoundaries(start, end, minSec, maxSec, keyframes)
+			dur := clipEnd - clipStart
+			if dur > maxSec+1.0 {
+				break
+			}
+			if dur < minSec {
+				continue
+			}
+			text := strings.TrimSpace(b.String())
+			if utf8.RuneCountInString(text) < 18 {
+				continue
+			}
+			signals, semType := semanticScoreSignals(text, dur)
+			base := semanticBaseScore(signals)
+			out = append(out, semanticCandidate{
+				ID:            fmt.Sprintf("w%03d", len(out)+1),
+				StartSec:      roundMillis(clipStart),
+				EndSec:        roundMillis(clipEnd),
+				DurationSec:   roundMillis(dur),
+				CueStartIndex: i,
+				CueEndIndex:   j,
+				Text:          text,
+				BaseScore:     roundMillis(base),
+				FinalScore:    roundMillis(base),
+				Type:          semType,
+				Signals:       signals,
+			})
+			if len(out) >= maxSemanticCandidateWindows {
+				return out
+			}
+		}
+	}
+	return out
+}
+
+// buildSemanticVisualGapCandidates proposes fixed-interval windows inside
+// subtitle gaps (silent or music-only stretches) that text windows never reach.
+// They carry no text, so the score comes from shot-change density only.
+func buildSemanticVisualGapCandidates(cues []subtitleCue, durationSec, minSec, maxSec, gapSec float64, keyframes []float64) []semanticCandidate {
+	if gapSec <= 0 || minSec <= 0 {
+		return nil
+	}
+	sorted := make([]subtitleCue, 0, len(cues))
+	for _, cue := range cues {
+		if cue.EndSec > cue.StartSec {
+			sorted = append(sorted, cue)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].StartSec < sorted[j].StartSec
+	})
+
+	type gap struct{ start, end float64 }
+	gaps := make([]gap, 0, 8)
+	cursor := 0.0
+	for _, cue := range sorted {
+		if cue.StartSec-cursor >= gapSec {
+			gaps = append(gaps, gap{start: cursor, end: cue.StartSec})
+		}
+		if cue.EndSec > cursor {
+			cursor = cue.EndSec
+		}
+	}
+	if durationSec > 0 && durationSec-cursor >= gapSec {
+		gaps = append(gaps, gap{start: cursor, end: durationSec})
+	}
+
+	windowSec := math.Min(maxSec, math.Max(minSec, (minSec+maxSec)/3))
+	out := make([]semanticCandidate, 0, len(gaps)*2)
+	for _, g := range gaps {
+		for start := g.start; start+minSec <= g.end; start += windowSec {
+			end := math.Min(start+windowSec, g.end)
 			clipStart, clipEnd := semanticSnapCandidateToBoundaries(start, end, minSec, maxSec, keyframes)
 			dur := clipEnd - clipStart
-			if dur > maxSec+1.0 {
-				break
-			}
-			if dur < minSec {
+			if dur < minSec || dur > maxSec+1.0 {
 				continue
 			}
-			text := strings.TrimSpace(b.String())
-			if utf8.RuneCountInString(text) < 18 {
-				continue
+			signals := semanticSignals{
+				Density: roundMillis(semanticKeyframeDensity(clipStart, clipEnd, keyframes)),
 			}
-			signals, semType := semanticScoreSignals(text, dur)
 			base := semanticBaseScore(signals)
 			out = append(out, semanticCandidate{
-				ID:            fmt.Sprintf("w%03d", len(out)+1),
+				ID:            fmt.Sprintf("v%03d", len(out)+1),
 				StartSec:      roundMillis(clipStart),
 				EndSec:        roundMillis(clipEnd),
 				DurationSec:   roundMillis(dur),
-				CueStartIndex: i,
-				CueEndIndex:   j,
-				Text:          text,
+				CueStartIndex: -1,
+				CueEndIndex:   -1,
 				BaseScore:     roundMillis(base),
 				FinalScore:    roundMillis(base),
-				Type:          semType,
+				Type:          semanticTypeVisual,
+				Reason:        "字幕空档内的画面候选",
 				Signals:       signals,
 			})
-			if len(out) >= maxSemanticCandidateWindows {
-				return out
-			}
 		}
 	}
 	return out
 }
 
+// semanticKeyframeDensity approximates visual activity as keyframes per 4s,
+// which roughly tracks shot changes for typical GOP settings.
+// semanticDropSponsorCandidates removes candidates that overlap SponsorBlock segments.
+func semanticDropSponsorCandidates(candidates []semanticCandidate, segments []sponsorSegment) ([]semanticCandidate, int) {
+	if len(segments) == 0 {
+		return candidates, 0
+	}
+	kept := make([]semanticCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		if sponsorOverlapSec(c.StartSec, c.EndSec, segments) > 0 {
+			continue
+		}
+		kept = append(kept, c)
+	}
+	return kept, len(candidates) - len(kept)
+}
+
+func semanticKeyframeDensity(start, end float64, keyframes []float64) float64 {
+	dur := end - start
+	if dur <= 0 || len(keyframes) == 0 {
+		return 0
+	}
+	hits := 0
+	for _, k := range keyframes {
+		if k > start && k < end {
+			hits++
+		}
+	}
+	return clamp01(float64(hits) / (dur / 4.0))
+}
+
+// semanticMergeVisualCandidates appends visual gap candidates to the text pool
+// while keeping them to roughly a fifth of the limit.
+func semanticMergeVisualCandidates(text, visual []semanticCandidate, limit int) ([]semanticCandidate, int) {
+	if len(visual) == 0 || limit <= 0 {
+		return text, 0
+	}
+	quota := limit / 5
+	if quota < 1 {
+		return text, 0
+	}
+	sort.SliceStable(visual, func(i, j int) bool {
+		return visual[i].BaseScore > visual[j].BaseScore
+	})
+	if len(visual) > quota {
+		visual = visual[:quota]
+	}
+	if len(text)+len(visual) > limit {
+		text = text[:limit-len(visual)]
+	}
+	out := make([]semanticCandidate, 0, len(text)+len(visual))
+	out = append(out, text...)
+	for i, c := range visual {
+		c.ID = fmt.Sprintf("v%03d", i+1)
+		out = append(out, c)
+	}
+	return out, len(visual)
+}
+
 func semanticSnapCandidateToBoundaries(start, end, minSec, maxSec float64, keyframes []float64) (float64, float64) {
 	if len(keyframes) == 0 {
 		return start, end
@@ -1226,11 +2153,74 @@ func semanticTargetDurationRange(target string) (float64, float64) {
 	switch target {
 	case "shorts":
 		return 15, 45
+	case "douyin":
+		return 15, 60
+	case "xiaohongshu":
+		return 30, 90
 	default:
 		return 18, 90
 	}
 }
 
+// semanticVerticalTarget reports whether the target publishes 9:16 video.
+func semanticVerticalTarget(target string) bool {
+	switch target {
+	case "shorts", "douyin", "xiaohongshu":
+		return true
+	}
+	return false
+}
+
+// Stage C blends a candidate's FinalScore with its novelty against the clips
+// already picked.
+const (
+	semanticSelectScoreWeight   = 0.74
+	semanticSelectNoveltyWeight = 0.26
+)
+
+// semanticExplainScores rebuilds the per-clip breakdown for --explain-scores.
+// Clips are walked in rank order so each novelty is measured against the
+// clips picked before it, as semanticSelectDiverseCandidates did.
+func semanticExplainScores(candidates, selected []semanticCandidate, topK int, visualDiversity float64, usedLLM bool) []semanticScoreExplain {
+	ranked := append([]semanticCandidate(nil), selected...)
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Rank < ranked[j].Rank })
+
+	minSec, maxSec := semanticTimelineBounds(candidates)
+	for _, c := range ranked {
+		minSec = math.Min(minSec, c.StartSec)
+		maxSec = math.Max(maxSec, c.EndSec)
+	}
+	span := math.Max(1.0, maxSec-minSec)
+	bucketCount := semanticSelectionBucketCount(topK)
+
+	out := make([]semanticScoreExplain, 0, len(ranked))
+	for i, c := range ranked {
+		prev := ranked[:i]
+		novelty := semanticNoveltyScore(prev, c, span, visualDiversity)
+		_, taken := semanticSelectedBuckets(prev, minSec, maxSec, bucketCount)[semanticBucketIndex(semanticCandidateMidpoint(c), minSec, maxSec, bucketCount)]
+		e := semanticScoreExplain{
+			ID:             c.ID,
+			Rank:           c.Rank,
+			StartSec:       c.StartSec,
+			EndSec:         c.EndSec,
+			Signals:        c.Signals,
+			BaseScore:      c.BaseScore,
+			SemanticScore:  c.SemanticScore,
+			LLMScored:      usedLLM,
+			FinalScore:     c.FinalScore,
+			Novelty:        roundMillis(novelty),
+			HasVisualHash:  c.VisualHash != "",
+			NewBucket:      !taken,
+			SelectionScore: roundMillis(semanticSelectScoreWeight*c.FinalScore + semanticSelectNoveltyWeight*novelty),
+		}
+		if e.HasVisualHash {
+			e.VisualWeight = roundMillis(semanticVisualNoveltyWeight(visualDiversity))
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
 func semanticPickFinalCandidates(candidates []semanticCandidate, topK int, target string, visualDiversity float64) []semanticCandidate {
 	if len(candidates) == 0 || topK <= 0 {
 		return nil
@@ -1301,7 +2291,7 @@ func semanticSelectDiverseCandidates(candidates, seed []semanticCandidate, topK
 					continue
 				}
 				novelty := semanticNoveltyScore(selected, c, span, visualDiversity)
-				score := 0.74*c.FinalScore + 0.26*novelty
+				score := semanticSelectScoreWeight*c.FinalScore + semanticSelectNoveltyWeight*novelty
 				bucket := semanticBucketIndex(semanticCandidateMidpoint(c), minSec, maxSec, bucketCount)
 				if _, ok := buckets[bucket]; !ok {
 					score += 0.10
@@ -1369,6 +2359,109 @@ func semanticSelectDiverseCandidates(candidates, seed []semanticCandidate, topK
 	return selected
 }
 
+const (
+	semanticPositionBiasNone  = "none"
+	semanticPositionBiasFront = "front"
+	semanticPositionBiasBack  = "back"
+	// semanticPositionBiasStrength is the largest FinalScore nudge (±8%) at
+	// either end of the timeline; it tips close calls without overriding content.
+	semanticPositionBiasStrength = 0.08
+)
+
+// semanticApplyPositionBias (--position-bias) scales FinalScore by where a
+// candidate's midpoint falls: front favours the opening (news), back the
+// ending (tutorials). none returns candidates unchanged.
+func semanticApplyPositionBias(candidates []semanticCandidate, bias string, timelineSec float64) []semanticCandidate {
+	if bias == semanticPositionBiasNone || bias == "" || timelineSec <= 0 {
+		return candidates
+	}
+	out := append([]semanticCandidate(nil), candidates...)
+	for i := range out {
+		pos := clamp01(semanticCandidateMidpoint(out[i]) / timelineSec)
+		weight := 1 - 2*pos // +1 at the start, -1 at the end
+		if bias == semanticPositionBiasBack {
+			weight = -weight
+		}
+		out[i].FinalScore = roundMillis(clamp01(out[i].FinalScore * (1 + semanticPositionBiasStrength*weight)))
+	}
+	return out
+}
+
+// semanticMinCoverageSpan is the share of the timeline the selected set should
+// span before Stage C warns about clustering.
+const semanticMinCoverageSpan = 0.5
+
+// semanticCoverageSpan is the share of the timeline between the earliest
+// selected start and the latest selected end.
+func semanticCoverageSpan(selected []semanticCandidate, timelineSec float64) float64 {
+	if len(selected) == 0 || timelineSec <= 0 {
+		return 0
+	}
+	minSec, maxSec := semanticTimelineBounds(selected)
+	return clamp01((maxSec - minSec) / timelineSec)
+}
+
+func semanticHasFinalThirdPick(selected []semanticCandidate, timelineSec float64) bool {
+	for _, c := range selected {
+		if semanticCandidateMidpoint(c) >= timelineSec*2/3 {
+			return true
+		}
+	}
+	return false
+}
+
+// semanticEnforceSpread (--enforce-spread) swaps the last pick for the best
+// final-third candidate that still clears the overlap and duplicate gates,
+// when no selected clip lands in the final third. selected is in pick order.
+func semanticEnforceSpread(candidates, selected []semanticCandidate, timelineSec float64, threshold doctorThreshold, visualDiversity float64) ([]semanticCandidate, bool) {
+	if len(selected) < 2 || timelineSec <= 0 || semanticHasFinalThirdPick(selected, timelineSec) {
+		return selected, false
+	}
+	kept := selected[:len(selected)-1]
+	used := make(map[string]struct{}, len(selected))
+	for _, c := range selected {
+		used[semanticCandidateKey(c)] = struct{}{}
+	}
+	bestIdx := -1
+	for idx, c := range candidates {
+		if _, ok := used[semanticCandidateKey(c)]; ok {
+			continue
+		}
+		if semanticCandidateMidpoint(c) < timelineSec*2/3 {
+			continue
+		}
+		if c.DurationSec < threshold.ClipMinSec || c.DurationSec > threshold.ClipMaxSec {
+			continue
+		}
+		if !semanticCanAddCandidate(kept, c, threshold, visualDiversity) {
+			continue
+		}
+		if bestIdx < 0 || c.FinalScore > candidates[bestIdx].FinalScore {
+			bestIdx = idx
+		}
+	}
+	if bestIdx < 0 {
+		return selected, false
+	}
+	out := append(append([]semanticCandidate(nil), kept...), candidates[bestIdx])
+	return out, true
+}
+
+// semanticFinalizeOrder stamps each selected clip with its score rank and,
+// when chronological output is requested, reorders the set by start time.
+func semanticFinalizeOrder(selected []semanticCandidate, chronological bool) []semanticCandidate {
+	out := append([]semanticCandidate(nil), selected...)
+	for i := range out {
+		out[i].Rank = i + 1
+	}
+	if chronological {
+		sort.SliceStable(out, func(i, j int) bool {
+			return out[i].StartSec < out[j].StartSec
+		})
+	}
+	return out
+}
+
 func semanticSelectionBucketCount(topK int) int {
 	count := topK * 3
 	if count < 6 {
@@ -1556,7 +2649,7 @@ func semanticTopPreviewCandidates(candidates, selected []semanticCandidate, prev
 	return out
 }
 
-func semanticGeneratePreviewFiles(assetPath string, candidates []semanticCandidate, previewDir string) error {
+func semanticGeneratePreviewFiles(assetPath string, candidates []semanticCandidate, previewDir string, render semanticRenderOptions) error {
 	if len(candidates) == 0 {
 		return nil
 	}
@@ -1567,11 +2660,21 @@ func semanticGeneratePreviewFiles(assetPath string, candidates []semanticCandida
 	if err := os.MkdirAll(previewDir, 0o755); err != nil {
 		return err
 	}
+	absAssetPath, err := filepath.Abs(assetPath)
+	if err != nil {
+		return err
+	}
+	if abs, err := filepath.Abs(ffmpegPath); err == nil && strings.ContainsAny(ffmpegPath, `/\`) {
+		ffmpegPath = abs
+	}
 
 	for i := range candidates {
 		c := &candidates[i]
 		filename := fmt.Sprintf("%s.mp4", sanitizeFileName(c.ID))
-		outPath := filepath.Join(previewDir, filename)
+		absOutPath, err := filepath.Abs(filepath.Join(previewDir, filename))
+		if err != nil {
+			continue
+		}
 		duration := c.DurationSec
 		if duration <= 0 {
 			duration = c.EndSec - c.StartSec
@@ -1580,21 +2683,44 @@ func semanticGeneratePreviewFiles(assetPath string, candidates []semanticCandida
 			continue
 		}
 
+		vf := render.videoFilter()
+		var burnDir string
+		if len(render.burnCues) > 0 {
+			burnDir = semanticWriteBurnSubtitle(c.StartSec, c.StartSec+duration, render.burnCues)
+			if burnDir != "" {
+				vf += ",subtitles=" + semanticBurnSubtitleName
+			}
+		}
+
 		args := []string{
 			"-y",
 			"-ss", fmt.Sprintf("%.3f", c.StartSec),
 			"-t", fmt.Sprintf("%.3f", duration),
-			"-i", assetPath,
-			"-vf", "scale='min(960,iw)':-2",
+			"-i", absAssetPath,
+			"-vf", vf,
 			"-c:v", "libx264",
 			"-preset", "veryfast",
 			"-crf", "30",
+		}
+		if render.Loudnorm.Enabled {
+			args = append(args, "-af", loudnormFilter(ffmpegPath, assetPath, c.StartSec, duration, render.Loudnorm), "-ar", "48000")
+		}
+		args = append(args,
 			"-c:a", "aac",
 			"-movflags", "+faststart",
-			outPath,
-		}
+			absOutPath,
+		)
 		cmd := exec.Command(ffmpegPath, args...)
-		if err := cmd.Run(); err != nil {
+		if burnDir != "" {
+			// Run inside the temp dir so the subtitles filter gets a bare file
+			// name and no filter-escaping of the path is needed.
+			cmd.Dir = burnDir
+		}
+		err = cmd.Run()
+		if burnDir != "" {
+			_ = os.RemoveAll(burnDir)
+		}
+		if err != nil {
 			continue
 		}
 		c.PreviewPath = filepath.ToSlash(filepath.Join("previews", filename))
@@ -1602,6 +2728,128 @@ func semanticGeneratePreviewFiles(assetPath string, candidates []semanticCandida
 	return nil
 }
 
+const semanticBurnSubtitleName = "clip.srt"
+
+// semanticWriteBurnSubtitle writes the clip's rebased cues into a fresh temp
+// dir and returns it, or "" when no cue falls inside the clip.
+func semanticWriteBurnSubtitle(start, end float64, cues []subtitleCue) string {
+	srt, n := semanticRebasedSRT(start, end, cues)
+	if n == 0 {
+		return ""
+	}
+	dir, err := os.MkdirTemp("", "mingest-burn-")
+	if err != nil {
+		logWarn("semantic.burn_subs_failed", "start_sec", start, "error", err)
+		return ""
+	}
+	if err := os.WriteFile(filepath.Join(dir, semanticBurnSubtitleName), []byte(srt), 0o644); err != nil {
+		logWarn("semantic.burn_subs_failed", "start_sec", start, "error", err)
+		_ = os.RemoveAll(dir)
+		return ""
+	}
+	return dir
+}
+
+// semanticRenderOptions controls how clips are encoded (previews today).
+type semanticRenderOptions struct {
+	Loudnorm loudnormConfig
+	// Aspect reframes to its delivery size (--vertical is 9:16); Reframe is
+	// center (crop) or blur-pad (fit over a blurred, cropped copy of the same
+	// frame). Empty Aspect keeps the source geometry.
+	Vertical bool
+	Aspect   string
+	Reframe  string
+	// BurnSubs overlays the real subtitle onto previews; burnCues is filled
+	// by the pipeline and stays nil when only a template subtitle exists.
+	BurnSubs bool
+	burnCues []subtitleCue
+}
+
+func (r semanticRenderOptions) videoFilter() string {
+	w, h, ok := aspectFrameSize(r.Aspect)
+	if !ok {
+		return "scale='min(960,iw)':-2"
+	}
+	size := fmt.Sprintf("%d:%d", w, h)
+	fill := "scale=" + size + ":force_original_aspect_ratio=increase,crop=" + size
+	if r.Reframe == "blur-pad" {
+		return "split=2[bg][fg];[bg]" + fill + ",boxblur=20:2[bgb];" +
+			"[fg]scale=" + size + ":force_original_aspect_ratio=decrease[fgs];" +
+			"[bgb][fgs]overlay=(W-w)/2:(H-h)/2,setsar=1"
+	}
+	return fill + ",setsar=1"
+}
+
+// loudnormDefaultTarget matches the loudness YouTube and Shorts normalize playback to.
+const loudnormDefaultTarget = -14.0
+
+// loudnormConfig controls EBU R128 normalization when encoding clips.
+// Two-pass measures each clip first (an extra full decode of the clip) so the
+// second pass can apply linear gain instead of dynamic compression.
+type loudnormConfig struct {
+	Enabled    bool
+	TargetLUFS float64
+	TwoPass    bool
+}
+
+type loudnormMeasurement struct {
+	InputI       string `json:"input_i"`
+	InputTP      string `json:"input_tp"`
+	InputLRA     string `json:"input_lra"`
+	InputThresh  string `json:"input_thresh"`
+	TargetOffset string `json:"target_offset"`
+}
+
+// loudnormFilter returns the -af value for one clip. A failed two-pass
+// measurement falls back to single-pass.
+func loudnormFilter(ffmpegPath, assetPath string, start, duration float64, cfg loudnormConfig) string {
+	base := fmt.Sprintf("loudnorm=I=%.1f:TP=-1.5:LRA=11", cfg.TargetLUFS)
+	if !cfg.TwoPass {
+		return base
+	}
+	m, err := measureLoudnorm(ffmpegPath, assetPath, start, duration, base)
+	if err != nil {
+		logWarn("semantic.loudnorm_measure_failed", "start_sec", start, "error", err)
+		return base
+	}
+	return fmt.Sprintf("%s:measured_I=%s:measured_TP=%s:measured_LRA=%s:measured_thresh=%s:offset=%s:linear=true",
+		base, m.InputI, m.InputTP, m.InputLRA, m.InputThresh, m.TargetOffset)
+}
+
+func measureLoudnorm(ffmpegPath, assetPath string, start, duration float64, base string) (loudnormMeasurement, error) {
+	args := []string{
+		"-hide_banner",
+		"-nostats",
+		"-ss", fmt.Sprintf("%.3f", start),
+		"-t", fmt.Sprintf("%.3f", duration),
+		"-i", assetPath,
+		"-vn",
+		"-af", base + ":print_format=json",
+		"-f", "null",
+		"-",
+	}
+	cmd := exec.Command(ffmpegPath, args...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return loudnormMeasurement{}, err
+	}
+	// The measurement JSON is the last {...} block in ffmpeg's log output.
+	s := string(out)
+	open := strings.LastIndex(s, "{")
+	end := strings.LastIndex(s, "}")
+	if open < 0 || end < open {
+		return loudnormMeasurement{}, errors.New("未找到 loudnorm 测量结果")
+	}
+	var m loudnormMeasurement
+	if err := json.Unmarshal([]byte(s[open:end+1]), &m); err != nil {
+		return loudnormMeasurement{}, err
+	}
+	if m.InputI == "" || strings.Contains(m.InputI, "inf") {
+		return loudnormMeasurement{}, errors.New("片段没有有效音频")
+	}
+	return m, nil
+}
+
 func detectSemanticFFmpeg() (string, bool) {
 	exeDir, _ := executableDir()
 	wd, _ := os.Getwd()
@@ -1699,12 +2947,20 @@ func semanticVisualSimilarity(aHash, bHash string) (float64, bool) {
 	return clamp01(1.0 - distance), true
 }
 
-func writeSemanticReviewHTML(path string, candidates, selected []semanticCandidate, decisionsPath string) error {
+// semanticReviewEmbedWarnBytes is the embedded preview size above which a
+// self-contained review.html gets a size warning.
+const semanticReviewEmbedWarnBytes = 50 << 20
+
+// writeSemanticReviewHTML writes the review page. Previews are referenced by
+// relative path; with selfContained they are inlined as base64 data URIs so the
+// page works outside the bundle dir. It returns the embedded preview bytes.
+func writeSemanticReviewHTML(path string, candidates, selected []semanticCandidate, decisionsPath string, selfContained bool) (int64, error) {
 	selectedMap := make(map[string]struct{}, len(selected))
 	for _, s := range selected {
 		selectedMap[s.ID] = struct{}{}
 	}
 
+	var embedded int64
 	var b strings.Builder
 	b.WriteString("<!doctype html><html><head><meta charset=\"utf-8\"><title>Mingest Semantic Review</title>")
 	b.WriteString("<style>body{font-family:ui-sans-serif,system-ui;margin:24px;background:#f8fafc;color:#111}h1{margin-bottom:8px}.tip{background:#eef2ff;padding:10px;border-radius:8px;margin-bottom:16px}.grid{display:grid;grid-template-columns:repeat(auto-fill,minmax(320px,1fr));gap:14px}.card{background:#fff;border:1px solid #dbe2ea;border-radius:10px;padding:10px}.meta{font-size:12px;color:#475569}video{width:100%;border-radius:8px;background:#000}.tag{display:inline-block;border-radius:999px;background:#e2e8f0;padding:2px 8px;font-size:12px;margin-right:6px}</style>")
@@ -1729,8 +2985,18 @@ func writeSemanticReviewHTML(path string, candidates, selected []semanticCandida
 		b.WriteString(fmt.Sprintf("%.3fs - %.3fs", c.StartSec, c.EndSec))
 		b.WriteString("</div>")
 		if strings.TrimSpace(c.PreviewPath) != "" {
+			src := c.PreviewPath
+			if selfContained {
+				data, err := os.ReadFile(filepath.Join(filepath.Dir(path), filepath.FromSlash(c.PreviewPath)))
+				if err != nil {
+					logWarn("semantic.review_embed_failed", "preview", c.PreviewPath, "error", err.Error())
+				} else {
+					src = "data:video/mp4;base64," + base64.StdEncoding.EncodeToString(data)
+					embedded += int64(len(data))
+				}
+			}
 			b.WriteString("<video controls preload=\"metadata\" src=\"")
-			b.WriteString(template.HTMLEscapeString(c.PreviewPath))
+			b.WriteString(template.HTMLEscapeString(src))
 			b.WriteString("\"></video>")
 		} else {
 			b.WriteString("<div class=\"meta\">（无预览片段，使用时间戳评审）</div>")
@@ -1746,13 +3012,17 @@ func writeSemanticReviewHTML(path string, candidates, selected []semanticCandida
 		b.WriteString("</div>")
 	}
 	b.WriteString("</div></body></html>")
-	return os.WriteFile(path, []byte(b.String()), 0o644)
+	return embedded, os.WriteFile(path, []byte(b.String()), 0o644)
 }
 
 func semanticBuildDecisionTemplate(assetID, target string, candidates, selected []semanticCandidate) semanticDecisionFile {
 	selectedID := make(map[string]int, len(selected))
 	for i, s := range selected {
-		selectedID[s.ID] = i + 1
+		rank := s.Rank
+		if rank <= 0 {
+			rank = i + 1
+		}
+		selectedID[s.ID] = rank
 	}
 	items := make([]semanticDecisionItem, 0, len(candidates))
 	for _, c := range candidates {
@@ -1773,6 +3043,73 @@ func semanticBuildDecisionTemplate(assetID, target string, candidates, selected
 	}
 }
 
+// semanticPickDecisions walks the review candidates in the terminal and edits
+// decision in place. Prompts go to out (stderr) so `--json` output stays clean.
+func semanticPickDecisions(decision *semanticDecisionFile, candidates []semanticCandidate, in io.Reader, out io.Writer) {
+	byID := make(map[string]semanticCandidate, len(candidates))
+	for _, c := range candidates {
+		byID[c.ID] = c
+	}
+
+	reader := bufio.NewReader(in)
+	fmt.Fprintln(out, "逐条评审候选：k=保留  d=丢弃  数字=保留并设为该排名  回车=维持当前  q=结束（其余维持当前）")
+	for i := range decision.Items {
+		it := &decision.Items[i]
+		c, ok := byID[it.ID]
+		if !ok {
+			continue
+		}
+		current := "丢弃"
+		if it.Keep {
+			current = "保留"
+			if it.Rank > 0 {
+				current = fmt.Sprintf("保留 #%d", it.Rank)
+			}
+		}
+		fmt.Fprintln(out)
+		fmt.Fprintf(out, "[%d/%d] %s  %s - %s (%.1fs)  score=%.3f  type=%s  当前: %s\n",
+			i+1, len(decision.Items), c.ID, formatSRTTime(c.StartSec), formatSRTTime(c.EndSec), c.EndSec-c.StartSec, c.FinalScore, firstNonEmpty(c.Type, "-"), current)
+		fmt.Fprintf(out, "  %s\n", semanticShortText(c.Text, 120))
+
+		for {
+			fmt.Fprint(out, "> ")
+			line, err := reader.ReadString('\n')
+			answer := strings.ToLower(strings.TrimSpace(line))
+			if err != nil && answer == "" {
+				// stdin closed: keep remaining items as they are.
+				return
+			}
+			switch {
+			case answer == "":
+			case answer == "q":
+				return
+			case answer == "k" || answer == "y":
+				it.Keep = true
+			case answer == "d" || answer == "n":
+				it.Keep = false
+				it.Rank = 0
+			default:
+				rank, convErr := strconv.Atoi(answer)
+				if convErr != nil || rank <= 0 {
+					fmt.Fprintln(out, "  无法识别，请输入 k / d / 排名数字 / 回车 / q")
+					continue
+				}
+				it.Keep = true
+				it.Rank = rank
+			}
+			break
+		}
+	}
+}
+
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return (info.Mode() & os.ModeCharDevice) != 0
+}
+
 func semanticApplyDecisions(path string, candidates, selected []semanticCandidate, topK int, target string, visualDiversity float64) ([]semanticCandidate, error) {
 	decisionBytes, err := os.ReadFile(path)
 	if err != nil {
@@ -1837,28 +3174,69 @@ func semanticApplyDecisions(path string, candidates, selected []semanticCandidat
 	return final, nil
 }
 
-func semanticCandidatesToPrepClips(in []semanticCandidate) []prepClip {
+// writeSemanticClipSubtitles writes clip-NN.srt for each clip containing only the
+// cues that overlap it, trimmed to the clip and re-based so the clip starts at 00:00.
+func writeSemanticClipSubtitles(dir string, clips []prepClip, cues []subtitleCue) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(clips))
+	for i, c := range clips {
+		srt, _ := semanticRebasedSRT(c.StartSec, c.EndSec, cues)
+		path := filepath.Join(dir, fmt.Sprintf("clip-%02d.srt", i+1))
+		if err := os.WriteFile(path, []byte(srt), 0o644); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// semanticRebasedSRT renders the cues doctor attributes to [start, end) as SRT,
+// trimmed to the range and timed from its start, and returns the cue count.
+func semanticRebasedSRT(start, end float64, cues []subtitleCue) (string, int) {
+	clipCues := doctorClipCues(prepClip{StartSec: start, EndSec: end}, cues)
+	for i, cue := range clipCues {
+		clipCues[i].StartSec = math.Max(cue.StartSec, start) - start
+		clipCues[i].EndSec = math.Min(cue.EndSec, end) - start
+	}
+	return formatSRTCues(clipCues), len(clipCues)
+}
+
+// semanticCandidatesToPrepClips converts picks to plan clips. labelTemplate
+// (from `--label-template` or the prep plan) replaces the semantic-NN labels;
+// {type} is the candidate type.
+func semanticCandidatesToPrepClips(in []semanticCandidate, labelTemplate, title string) []prepClip {
 	out := make([]prepClip, 0, len(in))
 	for i, c := range in {
+		label := fmt.Sprintf("semantic-%02d", i+1)
+		if strings.TrimSpace(labelTemplate) != "" {
+			label = renderClipLabel(labelTemplate, i+1, firstNonEmpty(c.Type, "semantic"), title, c.StartSec)
+		}
 		out = append(out, prepClip{
 			Index:       i + 1,
 			StartSec:    roundMillis(c.StartSec),
 			EndSec:      roundMillis(c.EndSec),
 			DurationSec: roundMillis(c.DurationSec),
-			Label:       fmt.Sprintf("semantic-%02d", i+1),
+			Label:       label,
 			Reason:      "语义候选（AI + 人工决策）",
+			Rank:        c.Rank,
 		})
 	}
 	return out
 }
 
-func createSemanticArtifacts(asset prepResolvedAsset) (semanticArtifacts, error) {
+func createSemanticArtifacts(asset prepResolvedAsset, bundleDir string) (semanticArtifacts, error) {
 	ts := time.Now().UTC().Format("20060102T150405Z")
-	base := filepath.Join(filepath.Dir(asset.OutputPath), ".mingest", "semantic", asset.AssetID, ts)
-	previewDir := filepath.Join(base, "previews")
-	if err := os.MkdirAll(previewDir, 0o755); err != nil {
+	base := filepath.Join(mingestArtifactRoot(asset.OutputPath, asset.AssetID, bundleDir, "semantic"), ts)
+	artifacts := semanticArtifactsAt(base)
+	if err := os.MkdirAll(artifacts.PreviewDir, 0o755); err != nil {
 		return semanticArtifacts{}, err
 	}
+	return artifacts, nil
+}
+
+func semanticArtifactsAt(base string) semanticArtifacts {
 	return semanticArtifacts{
 		BundleDir:       base,
 		StageAPath:      filepath.Join(base, "stage-a-candidates.json"),
@@ -1866,8 +3244,120 @@ func createSemanticArtifacts(asset prepResolvedAsset) (semanticArtifacts, error)
 		StageCPath:      filepath.Join(base, "stage-c-selected.json"),
 		ReviewHTMLPath:  filepath.Join(base, "review.html"),
 		ReviewDecisions: filepath.Join(base, "review-decisions.template.json"),
-		PreviewDir:      previewDir,
-	}, nil
+		PreviewDir:      filepath.Join(base, "previews"),
+		StatePath:       filepath.Join(base, semanticStateFile),
+	}
+}
+
+const (
+	semanticStateFile     = "semantic-state.json"
+	semanticStateVersion  = "semantic-state-v1"
+	semanticStageAVersion = "semantic-a-v1"
+	semanticStageBVersion = "semantic-b-v1"
+)
+
+// semanticResumeState is written as semantic-state.json next to the stage
+// artifacts. Each completed stage records a fingerprint of its inputs so
+// `--resume` only reuses it while those inputs are unchanged.
+type semanticResumeState struct {
+	Version string                        `json:"version"`
+	Stages  map[string]semanticStageState `json:"stages"`
+}
+
+type semanticStageState struct {
+	Version     string `json:"version"`
+	InputKey    string `json:"input_key"`
+	CompletedAt string `json:"completed_at"`
+}
+
+type semanticStageFile struct {
+	Version string          `json:"version"`
+	Model   string          `json:"model,omitempty"`
+	Items   json.RawMessage `json:"items"`
+}
+
+// findSemanticResume picks the newest semantic dir that has a state file.
+// An empty BundleDir means there is nothing to resume.
+func findSemanticResume(asset prepResolvedAsset, bundleDir string) (semanticArtifacts, semanticResumeState) {
+	fresh := semanticResumeState{Version: semanticStateVersion, Stages: map[string]semanticStageState{}}
+	dir := latestArtifactDir(artifactRoots(asset, bundleDir, "semantic"), semanticStateFile)
+	if dir == "" {
+		return semanticArtifacts{}, fresh
+	}
+	artifacts := semanticArtifactsAt(dir)
+	b, err := os.ReadFile(artifacts.StatePath)
+	if err != nil {
+		return semanticArtifacts{}, fresh
+	}
+	var s semanticResumeState
+	if err := json.Unmarshal(b, &s); err != nil || s.Version != semanticStateVersion {
+		logWarn("semantic.resume_state_invalid", "path", artifacts.StatePath, "error", err)
+		return artifacts, fresh
+	}
+	if s.Stages == nil {
+		s.Stages = map[string]semanticStageState{}
+	}
+	if err := os.MkdirAll(artifacts.PreviewDir, 0o755); err != nil {
+		return semanticArtifacts{}, fresh
+	}
+	logInfo("semantic.resume", "dir", dir, "stages", len(s.Stages))
+	return artifacts, s
+}
+
+// loadStage returns the stage artifact when the recorded stage matches both
+// the schema version and the current input fingerprint.
+func (s semanticResumeState) loadStage(path, stage, version, key string) (semanticStageFile, bool) {
+	st, ok := s.Stages[stage]
+	if !ok || st.Version != version || st.InputKey != key {
+		if ok {
+			logInfo("semantic.resume_stage_stale", "stage", stage)
+		}
+		return semanticStageFile{}, false
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return semanticStageFile{}, false
+	}
+	var f semanticStageFile
+	if err := json.Unmarshal(b, &f); err != nil || f.Version != version {
+		logWarn("semantic.resume_artifact_invalid", "stage", stage, "path", path)
+		return semanticStageFile{}, false
+	}
+	logInfo("semantic.resume_stage_reused", "stage", stage, "path", path)
+	return f, true
+}
+
+// complete records a stage; recomputing Stage A also drops Stage B.
+func (s *semanticResumeState) complete(stage, version, key string) {
+	if stage == "a" {
+		delete(s.Stages, "b")
+	}
+	s.Stages[stage] = semanticStageState{
+		Version:     version,
+		InputKey:    key,
+		CompletedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+func (s semanticResumeState) save(path string) {
+	if err := writeJSONFile(path, s); err != nil {
+		logWarn("semantic.resume_state_write_failed", "path", path, "error", err)
+	}
+}
+
+func semanticStageFingerprint(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// semanticFileFingerprint hashes the file content; "" when unreadable.
+func semanticFileFingerprint(path string) string {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
 }
 
 func writeJSONFile(path string, v interface{}) error {
@@ -1891,9 +3381,11 @@ func buildSemanticJSONResult(state semanticRunState, opts semanticOptions, exitC
 		Model:           state.Model,
 		UsedLLM:         state.UsedLLM,
 		Applied:         opts.Apply && state.Artifacts.AppliedPlanPath != "",
+		CandidateLimit:  state.CandidateLimit,
 		CandidateCount:  len(state.Candidates),
 		SelectedCount:   len(state.Selected),
 		VisualDiversity: opts.VisualDiversity,
+		ResumedStages:   state.Resumed,
 		Artifacts:       state.Artifacts,
 		Warnings:        state.Warnings,
 	}
@@ -1902,7 +3394,7 @@ func buildSemanticJSONResult(state semanticRunState, opts semanticOptions, exitC
 	}
 	if opts.Apply && len(state.Selected) > 0 {
 		p := state.Plan
-		p.Clips = semanticCandidatesToPrepClips(state.Selected)
+		p.Clips = semanticCandidatesToPrepClips(state.Selected, firstNonEmpty(opts.LabelTemplate, p.Options.LabelTemplate), p.Asset.Title)
 		result.DoctorSummary = summarizeDoctorChecks(runDoctorChecks(doctorOptions{
 			Target: opts.Target,
 			Strict: opts.Strict,
@@ -1912,7 +3404,7 @@ func buildSemanticJSONResult(state semanticRunState, opts semanticOptions, exitC
 }
 
 func printSemanticJSON(v semanticJSONResult) {
-	data, err := json.Marshal(v)
+	data, err := marshalJSONResult(v)
 	if err != nil {
 		logError("json.marshal_failed", "context", "semantic_result", "error", err)
 		return
@@ -1937,8 +3429,14 @@ func printSemanticHuman(state semanticRunState, opts semanticOptions, exitCode i
 	fmt.Printf("provider: %s\n", firstNonEmpty(state.Provider, "rule-only"))
 	fmt.Printf("model: %s\n", firstNonEmpty(state.Model, "-"))
 	fmt.Printf("used_llm: %v\n", state.UsedLLM)
+	if state.CandidateLimit > 0 {
+		fmt.Printf("candidate_limit: %d\n", state.CandidateLimit)
+	}
 	fmt.Printf("candidate_count: %d\n", len(state.Candidates))
 	fmt.Printf("selected_count: %d\n", len(state.Selected))
+	if len(state.Resumed) > 0 {
+		fmt.Printf("resumed_stages: %s\n", strings.Join(state.Resumed, ","))
+	}
 	if strings.TrimSpace(state.Artifacts.BundleDir) != "" {
 		fmt.Printf("semantic_dir: %s\n", state.Artifacts.BundleDir)
 	}
@@ -1951,6 +3449,17 @@ func printSemanticHuman(state semanticRunState, opts semanticOptions, exitCode i
 	if opts.Apply && strings.TrimSpace(state.Artifacts.AppliedPlanPath) != "" {
 		fmt.Printf("applied_prep_plan: %s\n", state.Artifacts.AppliedPlanPath)
 		fmt.Printf("backup_prep_plan: %s\n", state.Artifacts.BackupPlanPath)
+		for _, p := range state.Artifacts.ClipSubtitles {
+			fmt.Printf("clip_subtitle: %s\n", p)
+		}
+	}
+	if strings.TrimSpace(state.Artifacts.StageCExplain) != "" {
+		fmt.Printf("stage_c_explain: %s\n", state.Artifacts.StageCExplain)
+	}
+	for _, e := range state.Explain {
+		fmt.Printf("explain: #%d %s base=%.3f semantic=%.3f final=%.3f novelty=%.3f selection=%.3f new_bucket=%v signals(hook=%.2f insight=%.2f controversy=%.2f density=%.2f question=%.2f)\n",
+			e.Rank, e.ID, e.BaseScore, e.SemanticScore, e.FinalScore, e.Novelty, e.SelectionScore, e.NewBucket,
+			e.Signals.Hook, e.Signals.Insight, e.Signals.Controversy, e.Signals.Density, e.Signals.Question)
 	}
 	for _, w := range state.Warnings {
 		fmt.Printf("warning: %s\n", w)
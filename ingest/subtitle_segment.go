@@ -0,0 +1,221 @@
+// media-ingest (mingest) - Media Ingestion CLI tool
+// Copyright (C) 2026  Harrison Wang <https://mingest.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ingest
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Subtitle segmentation modes for `prep --subtitle-segment`. words makes the
+// short, punchy cues burned-in shorts captions want; sentences regroups
+// word-level auto-captions into readable lines.
+const (
+	subtitleSegmentOff       = "off"
+	subtitleSegmentWords     = "words"
+	subtitleSegmentSentences = "sentences"
+)
+
+// subtitleSegmentMaxGapSec is the largest silence two cues may straddle and
+// still be merged; a longer pause reads as a new line anyway.
+const subtitleSegmentMaxGapSec = 0.5
+
+type prepSubtitleSegmentation struct {
+	Mode       string  `json:"mode"`
+	MaxChars   int     `json:"max_chars"`
+	MaxSec     float64 `json:"max_sec"`
+	CuesBefore int     `json:"cues_before"`
+	CuesAfter  int     `json:"cues_after"`
+}
+
+// subtitleSegmentLimits returns the per-cue character and duration caps for mode.
+func subtitleSegmentLimits(mode string) (maxChars int, maxSec float64) {
+	if mode == subtitleSegmentWords {
+		return 16, 2.5
+	}
+	return 42, 6
+}
+
+// applySubtitleSegmentation rewrites the SRT at path in place and describes
+// what changed. The file is left untouched when it has no cues.
+func applySubtitleSegmentation(path, mode string) (*prepSubtitleSegmentation, error) {
+	cues, err := parseSubtitleCues(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(cues) == 0 {
+		return nil, fmt.Errorf("字幕文件无可用条目: %s", path)
+	}
+	maxChars, maxSec := subtitleSegmentLimits(mode)
+	out := segmentSubtitleCues(cues, maxChars, maxSec)
+	if err := os.WriteFile(path, []byte(formatSRTCues(out)), 0o644); err != nil {
+		return nil, err
+	}
+	return &prepSubtitleSegmentation{
+		Mode:       mode,
+		MaxChars:   maxChars,
+		MaxSec:     maxSec,
+		CuesBefore: len(cues),
+		CuesAfter:  len(out),
+	}, nil
+}
+
+// segmentSubtitleCues first splits cues longer than maxChars (timing is shared
+// out by character count), then merges neighbours while the result stays
+// within maxChars and maxSec, the gap is short and no sentence ends between them.
+func segmentSubtitleCues(cues []subtitleCue, maxChars int, maxSec float64) []subtitleCue {
+	split := make([]subtitleCue, 0, len(cues))
+	for _, c := range cues {
+		c.Text = strings.Join(strings.Fields(c.Text), " ")
+		if c.Text == "" {
+			continue
+		}
+		split = append(split, splitSubtitleCue(c, maxChars)...)
+	}
+
+	merged := make([]subtitleCue, 0, len(split))
+	for _, c := range split {
+		if n := len(merged); n > 0 {
+			cur := &merged[n-1]
+			text := joinSubtitleText(cur.Text, c.Text)
+			if c.StartSec-cur.EndSec <= subtitleSegmentMaxGapSec &&
+				c.EndSec-cur.StartSec <= maxSec &&
+				utf8.RuneCountInString(text) <= maxChars &&
+				!endsSubtitleSentence(cur.Text) {
+				cur.Text = text
+				cur.EndSec = c.EndSec
+				continue
+			}
+		}
+		merged = append(merged, c)
+	}
+	return merged
+}
+
+func splitSubtitleCue(c subtitleCue, maxChars int) []subtitleCue {
+	total := utf8.RuneCountInString(c.Text)
+	if total <= maxChars {
+		return []subtitleCue{c}
+	}
+
+	var pieces []string
+	cur := ""
+	for _, tok := range subtitleTokens(c.Text) {
+		next := joinSubtitleText(cur, tok)
+		if cur != "" && utf8.RuneCountInString(next) > maxChars {
+			pieces = append(pieces, cur)
+			next = tok
+		}
+		cur = next
+		// Prefer breaking after punctuation once a piece is half full.
+		if endsSubtitleClause(cur) && utf8.RuneCountInString(cur) >= maxChars/2 {
+			pieces = append(pieces, cur)
+			cur = ""
+		}
+	}
+	if cur != "" {
+		pieces = append(pieces, cur)
+	}
+
+	out := make([]subtitleCue, 0, len(pieces))
+	span := c.EndSec - c.StartSec
+	start := c.StartSec
+	done := 0
+	for i, p := range pieces {
+		done += utf8.RuneCountInString(p)
+		end := c.StartSec + span*float64(done)/float64(total)
+		if i == len(pieces)-1 {
+			end = c.EndSec
+		}
+		out = append(out, subtitleCue{StartSec: roundMillis(start), EndSec: roundMillis(end), Text: p})
+		start = end
+	}
+	return out
+}
+
+// subtitleTokens splits on spaces, and CJK runs into single characters since
+// they carry no spaces to break on.
+func subtitleTokens(text string) []string {
+	var toks []string
+	for _, field := range strings.Fields(text) {
+		word := ""
+		for _, r := range field {
+			if isCJKRune(r) {
+				if word != "" {
+					toks = append(toks, word)
+					word = ""
+				}
+				toks = append(toks, string(r))
+				continue
+			}
+			word += string(r)
+		}
+		if word != "" {
+			// Trailing punctuation after CJK stays with the previous character.
+			if len(toks) > 0 && isCJKText(toks[len(toks)-1]) && strings.IndexFunc(word, func(r rune) bool { return !unicode.IsPunct(r) }) < 0 {
+				toks[len(toks)-1] += word
+			} else {
+				toks = append(toks, word)
+			}
+		}
+	}
+	return toks
+}
+
+// joinSubtitleText joins with a space except where either side is CJK.
+func joinSubtitleText(a, b string) string {
+	if a == "" {
+		return b
+	}
+	if b == "" {
+		return a
+	}
+	last, _ := utf8.DecodeLastRuneInString(a)
+	first, _ := utf8.DecodeRuneInString(b)
+	if isCJKRune(last) || isCJKRune(first) || isCJKPunct(last) || isCJKPunct(first) {
+		return a + b
+	}
+	return a + " " + b
+}
+
+func endsSubtitleSentence(text string) bool {
+	r, _ := utf8.DecodeLastRuneInString(strings.TrimSpace(text))
+	return strings.ContainsRune(".!?。！？…", r)
+}
+
+func endsSubtitleClause(text string) bool {
+	r, _ := utf8.DecodeLastRuneInString(strings.TrimSpace(text))
+	return endsSubtitleSentence(text) || strings.ContainsRune(",;:，、；：", r)
+}
+
+// isCJKRune covers scripts written without spaces; Hangul uses spaces and is
+// left to the word path.
+func isCJKRune(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
+
+func isCJKPunct(r rune) bool {
+	return r >= 0x3000 && r <= 0x303F || r >= 0xFF00 && r <= 0xFFEF
+}
+
+func isCJKText(s string) bool {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return isCJKRune(r) || isCJKPunct(r)
+}
@@ -0,0 +1,302 @@
+// media-ingest (mingest) - Media Ingestion CLI tool
+// Copyright (C) 2026  Harrison Wang <https://mingest.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ingest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Contact sheet tiles are scaled to this width before tiling.
+const thumbnailTileWidth = 480
+
+type thumbnailOptions struct {
+	AssetRef string
+	At       string
+	Out      string
+	GridCols int
+	GridRows int
+	JSON     bool
+}
+
+type thumbnailJSONResult struct {
+	OK         bool    `json:"ok"`
+	ExitCode   int     `json:"exit_code"`
+	Error      string  `json:"error,omitempty"`
+	AssetID    string  `json:"asset_id,omitempty"`
+	AssetPath  string  `json:"asset_path,omitempty"`
+	OutputPath string  `json:"output_path,omitempty"`
+	AtSec      float64 `json:"at_sec,omitempty"`
+	Grid       string  `json:"grid,omitempty"`
+}
+
+func parseThumbnailOptions(args []string) (thumbnailOptions, error) {
+	opts := thumbnailOptions{}
+	var grid string
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		switch {
+		case arg == "--at":
+			if i+1 >= len(args) {
+				return thumbnailOptions{}, fmt.Errorf("`--at` 缺少参数")
+			}
+			i++
+			opts.At = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--at="):
+			opts.At = strings.TrimSpace(strings.TrimPrefix(arg, "--at="))
+		case arg == "--out":
+			if i+1 >= len(args) {
+				return thumbnailOptions{}, fmt.Errorf("`--out` 缺少参数")
+			}
+			i++
+			opts.Out = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--out="):
+			opts.Out = strings.TrimSpace(strings.TrimPrefix(arg, "--out="))
+		case arg == "--grid":
+			if i+1 >= len(args) {
+				return thumbnailOptions{}, fmt.Errorf("`--grid` 缺少参数")
+			}
+			i++
+			grid = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--grid="):
+			grid = strings.TrimSpace(strings.TrimPrefix(arg, "--grid="))
+		case arg == "--json":
+			opts.JSON = true
+		case strings.HasPrefix(arg, "-"):
+			return thumbnailOptions{}, fmt.Errorf("不支持的参数: %s", arg)
+		default:
+			if opts.AssetRef != "" {
+				return thumbnailOptions{}, fmt.Errorf("`mingest thumbnail` 仅支持一个 asset_ref")
+			}
+			opts.AssetRef = arg
+		}
+	}
+	if strings.TrimSpace(opts.AssetRef) == "" {
+		return thumbnailOptions{}, fmt.Errorf("缺少 asset_ref。用法: mingest thumbnail <asset_ref> [--at <sec|percent>] [--grid <NxM>] [--out <path>]")
+	}
+	if grid != "" {
+		cols, rows, err := parseThumbnailGrid(grid)
+		if err != nil {
+			return thumbnailOptions{}, err
+		}
+		if opts.At != "" {
+			return thumbnailOptions{}, fmt.Errorf("`--grid` 按全片均匀取帧，不能与 `--at` 同时使用")
+		}
+		opts.GridCols, opts.GridRows = cols, rows
+	}
+	if opts.At == "" {
+		opts.At = "50%"
+	}
+	if _, _, err := parseThumbnailAt(opts.At); err != nil {
+		return thumbnailOptions{}, err
+	}
+	if opts.Out != "" {
+		switch strings.ToLower(filepath.Ext(opts.Out)) {
+		case ".jpg", ".jpeg", ".png", "":
+		default:
+			return thumbnailOptions{}, fmt.Errorf("`--out` 仅支持 .jpg|.jpeg|.png")
+		}
+	}
+	return opts, nil
+}
+
+// parseThumbnailGrid parses "NxM" as N columns by M rows.
+func parseThumbnailGrid(raw string) (int, int, error) {
+	parts := strings.Split(strings.ToLower(raw), "x")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("`--grid` 格式应为 NxM（如 4x3）: %s", raw)
+	}
+	cols, err1 := strconv.Atoi(strings.TrimSpace(parts[0]))
+	rows, err2 := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err1 != nil || err2 != nil || cols < 1 || rows < 1 || cols > 10 || rows > 10 {
+		return 0, 0, fmt.Errorf("`--grid` 的行列数需在 1-10: %s", raw)
+	}
+	return cols, rows, nil
+}
+
+// parseThumbnailAt returns the position either as seconds or, with a trailing
+// "%", as a fraction of the duration.
+func parseThumbnailAt(raw string) (value float64, percent bool, err error) {
+	s := strings.TrimSpace(raw)
+	if strings.HasSuffix(s, "%") {
+		v, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(s, "%")), 64)
+		if err != nil || v < 0 || v > 100 {
+			return 0, false, fmt.Errorf("`--at` 百分比需在 0-100%%: %s", raw)
+		}
+		return v / 100, true, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 {
+		return 0, false, fmt.Errorf("`--at` 必须是非负秒数或百分比（如 90、50%%）: %s", raw)
+	}
+	return v, false, nil
+}
+
+func runThumbnail(opts thumbnailOptions) int {
+	result := executeThumbnail(opts)
+	if opts.JSON {
+		printThumbnailJSON(result)
+		return result.ExitCode
+	}
+	if !result.OK {
+		logError("thumbnail.failed", "exit_code", result.ExitCode, "detail", result.Error)
+		return result.ExitCode
+	}
+	fmt.Printf("asset_id: %s\n", result.AssetID)
+	fmt.Printf("output_path: %s\n", result.OutputPath)
+	if result.Grid != "" {
+		fmt.Printf("grid: %s\n", result.Grid)
+	} else {
+		fmt.Printf("at_sec: %.3f\n", result.AtSec)
+	}
+	return exitOK
+}
+
+func executeThumbnail(opts thumbnailOptions) thumbnailJSONResult {
+	fail := func(code int, msg string) thumbnailJSONResult {
+		return thumbnailJSONResult{OK: false, ExitCode: code, Error: msg}
+	}
+
+	asset, err := resolvePrepAsset(opts.AssetRef)
+	if err != nil {
+		return fail(exitDownloadFailed, err.Error())
+	}
+	if strings.TrimSpace(asset.AssetID) == "" {
+		assetID, err := computeAssetID(asset.OutputPath)
+		if err != nil {
+			return fail(exitDownloadFailed, fmt.Sprintf("生成 asset_id 失败: %v", err))
+		}
+		asset.AssetID = assetID
+	}
+
+	ffprobePath, err := detectPrepFFprobe()
+	if err != nil {
+		var depErr dependencyError
+		if errors.As(err, &depErr) {
+			return fail(depErr.ExitCode, depErr.Message)
+		}
+		return fail(exitDownloadFailed, fmt.Sprintf("依赖检测失败: %v", err))
+	}
+	ffmpegPath, ok := detectSemanticFFmpeg()
+	if !ok {
+		return fail(exitFFmpegMissing, "未找到 ffmpeg。请将 ffmpeg 放在工作目录或程序同目录，或加入 PATH。")
+	}
+
+	probe, err := probeMediaFile(ffprobePath, asset.OutputPath)
+	if err != nil {
+		return fail(exitDownloadFailed, fmt.Sprintf("读取媒体元数据失败: %v", err))
+	}
+	if probe.AudioOnly {
+		return fail(exitUsage, "素材没有视频流，无法截取封面")
+	}
+	if probe.DurationSec <= 0 {
+		return fail(exitDownloadFailed, "无法读取素材时长")
+	}
+
+	result := thumbnailJSONResult{
+		OK:        true,
+		ExitCode:  exitOK,
+		AssetID:   asset.AssetID,
+		AssetPath: asset.OutputPath,
+	}
+
+	var args []string
+	if opts.GridCols > 0 {
+		result.Grid = fmt.Sprintf("%dx%d", opts.GridCols, opts.GridRows)
+		result.OutputPath = thumbnailOutputPath(asset.OutputPath, opts.Out, "contact-sheet")
+		// Sample tiles evenly across the whole video, then tile them into one frame.
+		count := opts.GridCols * opts.GridRows
+		fps := float64(count) / probe.DurationSec
+		args = []string{
+			"-y",
+			"-hide_banner",
+			"-loglevel", "error",
+			"-i", asset.OutputPath,
+			"-vf", fmt.Sprintf("fps=%.6f,scale=%d:-2,tile=%dx%d", fps, thumbnailTileWidth, opts.GridCols, opts.GridRows),
+			"-frames:v", "1",
+		}
+	} else {
+		value, percent, _ := parseThumbnailAt(opts.At)
+		at := value
+		if percent {
+			at = probe.DurationSec * value
+		}
+		if at >= probe.DurationSec {
+			// The last frame sits slightly before the reported duration.
+			at = probe.DurationSec - 0.1
+		}
+		if at < 0 {
+			at = 0
+		}
+		result.AtSec = roundMillis(at)
+		result.OutputPath = thumbnailOutputPath(asset.OutputPath, opts.Out, "thumbnail")
+		args = []string{
+			"-y",
+			"-hide_banner",
+			"-loglevel", "error",
+			"-ss", fmt.Sprintf("%.3f", at),
+			"-i", asset.OutputPath,
+			"-frames:v", "1",
+		}
+	}
+	if ext := strings.ToLower(filepath.Ext(result.OutputPath)); ext == ".jpg" || ext == ".jpeg" {
+		args = append(args, "-q:v", "2")
+	}
+	args = append(args, "-update", "1", result.OutputPath)
+
+	if err := os.MkdirAll(filepath.Dir(result.OutputPath), 0o755); err != nil {
+		return fail(exitDownloadFailed, fmt.Sprintf("创建输出目录失败: %v", err))
+	}
+	cmd := exec.Command(ffmpegPath, args...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fail(exitDownloadFailed, fmt.Sprintf("ffmpeg 截图失败: %v %s", err, strings.TrimSpace(string(out))))
+	}
+	if !fileExists(result.OutputPath) {
+		return fail(exitDownloadFailed, "ffmpeg 未生成图片")
+	}
+	logInfo("thumbnail.written", "asset_id", asset.AssetID, "path", result.OutputPath)
+	return result
+}
+
+// thumbnailOutputPath defaults to <asset name>.<kind>.jpg next to the asset;
+// an --out directory receives the same file name.
+func thumbnailOutputPath(assetPath, out, kind string) string {
+	base := strings.TrimSuffix(filepath.Base(assetPath), filepath.Ext(assetPath))
+	name := fmt.Sprintf("%s.%s.jpg", base, kind)
+	out = strings.TrimSpace(out)
+	if out == "" {
+		return filepath.Join(filepath.Dir(assetPath), name)
+	}
+	if dirExists(out) || filepath.Ext(out) == "" {
+		return filepath.Join(out, name)
+	}
+	return out
+}
+
+func printThumbnailJSON(v thumbnailJSONResult) {
+	data, err := marshalJSONResult(v)
+	if err != nil {
+		logError("json.marshal_failed", "context", "thumbnail_result", "error", err)
+		return
+	}
+	fmt.Println(string(data))
+}
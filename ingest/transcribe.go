@@ -0,0 +1,292 @@
+// media-ingest (mingest) - Media Ingestion CLI tool
+// Copyright (C) 2026  Harrison Wang <https://mingest.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ingest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type transcribeOptions struct {
+	AssetRef string
+	Lang     string
+	Model    string
+	Format   string
+	Out      string
+	Device   string
+	JSON     bool
+}
+
+type transcribeJSONResult struct {
+	OK               bool    `json:"ok"`
+	ExitCode         int     `json:"exit_code"`
+	Error            string  `json:"error,omitempty"`
+	AssetID          string  `json:"asset_id,omitempty"`
+	AssetPath        string  `json:"asset_path,omitempty"`
+	OutputPath       string  `json:"output_path,omitempty"`
+	Format           string  `json:"format,omitempty"`
+	Model            string  `json:"model,omitempty"`
+	Language         string  `json:"language,omitempty"`
+	DetectedLanguage string  `json:"detected_language,omitempty"`
+	CueCount         int     `json:"cue_count,omitempty"`
+	QualityScore     float64 `json:"quality_score,omitempty"`
+	QualityNote      string  `json:"quality_note,omitempty"`
+}
+
+func parseTranscribeOptions(args []string) (transcribeOptions, error) {
+	opts := transcribeOptions{Lang: "auto"}
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		switch {
+		case arg == "--lang":
+			if i+1 >= len(args) {
+				return transcribeOptions{}, fmt.Errorf("`--lang` 缺少参数")
+			}
+			i++
+			opts.Lang = strings.ToLower(strings.TrimSpace(args[i]))
+		case strings.HasPrefix(arg, "--lang="):
+			opts.Lang = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--lang=")))
+		case arg == "--model":
+			if i+1 >= len(args) {
+				return transcribeOptions{}, fmt.Errorf("`--model` 缺少参数")
+			}
+			i++
+			opts.Model = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--model="):
+			opts.Model = strings.TrimSpace(strings.TrimPrefix(arg, "--model="))
+		case arg == "--format":
+			if i+1 >= len(args) {
+				return transcribeOptions{}, fmt.Errorf("`--format` 缺少参数")
+			}
+			i++
+			opts.Format = strings.ToLower(strings.TrimSpace(args[i]))
+		case strings.HasPrefix(arg, "--format="):
+			opts.Format = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--format=")))
+		case arg == "--out":
+			if i+1 >= len(args) {
+				return transcribeOptions{}, fmt.Errorf("`--out` 缺少参数")
+			}
+			i++
+			opts.Out = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--out="):
+			opts.Out = strings.TrimSpace(strings.TrimPrefix(arg, "--out="))
+		case arg == "--whisper-device":
+			if i+1 >= len(args) {
+				return transcribeOptions{}, fmt.Errorf("`--whisper-device` 缺少参数")
+			}
+			i++
+			opts.Device = strings.ToLower(strings.TrimSpace(args[i]))
+		case strings.HasPrefix(arg, "--whisper-device="):
+			opts.Device = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--whisper-device=")))
+		case arg == "--json":
+			opts.JSON = true
+		case strings.HasPrefix(arg, "-"):
+			return transcribeOptions{}, fmt.Errorf("不支持的参数: %s", arg)
+		default:
+			if opts.AssetRef != "" {
+				return transcribeOptions{}, fmt.Errorf("`mingest transcribe` 仅支持一个 asset_ref")
+			}
+			opts.AssetRef = arg
+		}
+	}
+	if strings.TrimSpace(opts.AssetRef) == "" {
+		return transcribeOptions{}, fmt.Errorf("缺少 asset_ref。用法: mingest transcribe <asset_ref|path> [--lang <auto|zh|en|...>] [--model <name>] [--format <srt|vtt|txt>] [--out <path>]")
+	}
+	if opts.Lang == "" {
+		opts.Lang = "auto"
+	}
+	if !validWhisperDevice(opts.Device) {
+		return transcribeOptions{}, fmt.Errorf("`--whisper-device` 仅支持 cpu|cuda")
+	}
+	// An --out file name picks the format when --format is not given.
+	outExt := strings.TrimPrefix(strings.ToLower(filepath.Ext(opts.Out)), ".")
+	switch outExt {
+	case "", "srt", "vtt", "txt":
+	default:
+		if !dirExists(opts.Out) {
+			return transcribeOptions{}, fmt.Errorf("`--out` 仅支持 .srt|.vtt|.txt 或目录")
+		}
+		outExt = ""
+	}
+	if opts.Format == "" {
+		opts.Format = firstNonEmpty(outExt, "srt")
+	}
+	switch opts.Format {
+	case "srt", "vtt", "txt":
+	default:
+		return transcribeOptions{}, fmt.Errorf("`--format` 仅支持 srt|vtt|txt")
+	}
+	if outExt != "" && outExt != opts.Format && !dirExists(opts.Out) {
+		return transcribeOptions{}, fmt.Errorf("`--out` 扩展名与 `--format %s` 不一致", opts.Format)
+	}
+	return opts, nil
+}
+
+func runTranscribe(opts transcribeOptions) int {
+	result := executeTranscribe(opts)
+	if opts.JSON {
+		printTranscribeJSON(result)
+		return result.ExitCode
+	}
+	if !result.OK {
+		logError("transcribe.failed", "exit_code", result.ExitCode, "detail", result.Error)
+		return result.ExitCode
+	}
+	fmt.Printf("asset_id: %s\n", result.AssetID)
+	fmt.Printf("output_path: %s\n", result.OutputPath)
+	fmt.Printf("model: %s\n", result.Model)
+	if result.DetectedLanguage != "" {
+		fmt.Printf("detected_language: %s\n", result.DetectedLanguage)
+	}
+	fmt.Printf("cue_count: %d\n", result.CueCount)
+	if result.QualityNote != "" {
+		fmt.Printf("quality: %.3f (%s)\n", result.QualityScore, result.QualityNote)
+	}
+	return exitOK
+}
+
+func executeTranscribe(opts transcribeOptions) transcribeJSONResult {
+	fail := func(code int, msg string) transcribeJSONResult {
+		return transcribeJSONResult{OK: false, ExitCode: code, Error: msg}
+	}
+
+	asset, err := resolvePrepAsset(opts.AssetRef)
+	if err != nil {
+		return fail(exitDownloadFailed, err.Error())
+	}
+	if strings.TrimSpace(asset.AssetID) == "" {
+		assetID, err := computeAssetID(asset.OutputPath)
+		if err != nil {
+			return fail(exitDownloadFailed, fmt.Sprintf("生成 asset_id 失败: %v", err))
+		}
+		asset.AssetID = assetID
+	}
+
+	whisperPath, ok := detectWhisperBinary()
+	if !ok {
+		return fail(exitDownloadFailed, fmt.Sprintf("未找到 whisper CLI（后端 %s，查找 %s）。请安装对应程序，或通过 MINGEST_WHISPER_PATH 指定路径", whisperBackend(), strings.Join(whisperBinaryNames(whisperBackend()), "/")))
+	}
+
+	tempDir, err := os.MkdirTemp("", "mingest-transcribe-*")
+	if err != nil {
+		return fail(exitDownloadFailed, fmt.Sprintf("创建临时目录失败: %v", err))
+	}
+	defer os.RemoveAll(tempDir)
+
+	model := firstNonEmpty(opts.Model, strings.TrimSpace(os.Getenv("MINGEST_WHISPER_MODEL")), prepWhisperDefaultModel)
+	logInfo("transcribe.start", "asset_id", asset.AssetID, "model", model, "lang", opts.Lang)
+	var progress io.Writer
+	if !opts.JSON {
+		progress = statusWriter()
+	}
+	srtPath, detected, err := runWhisperTranscribe(whisperPath, asset.OutputPath, opts.Lang, model, opts.Device, tempDir, progress)
+	if err != nil {
+		return fail(exitDownloadFailed, err.Error())
+	}
+	cues, err := parseSubtitleCues(srtPath)
+	if err != nil {
+		return fail(exitDownloadFailed, fmt.Sprintf("读取 Whisper 字幕失败: %v", err))
+	}
+	if len(cues) == 0 {
+		return fail(exitDownloadFailed, "Whisper 未识别出任何语音")
+	}
+
+	result := transcribeJSONResult{
+		OK:               true,
+		ExitCode:         exitOK,
+		AssetID:          asset.AssetID,
+		AssetPath:        asset.OutputPath,
+		OutputPath:       transcribeOutputPath(asset.OutputPath, opts.Out, opts.Format),
+		Format:           opts.Format,
+		Model:            model,
+		Language:         opts.Lang,
+		DetectedLanguage: detected,
+		CueCount:         len(cues),
+	}
+
+	// Quality is informational only; without ffprobe coverage is not scored
+	// against the media duration.
+	durationSec := 0.0
+	if ffprobePath, err := detectPrepFFprobe(); err == nil {
+		if probe, err := probeMediaFile(ffprobePath, asset.OutputPath); err == nil {
+			durationSec = probe.DurationSec
+		}
+	}
+	if score, note, err := evaluateSubtitleFileQuality(srtPath, durationSec); err == nil {
+		result.QualityScore = roundMillis(score)
+		result.QualityNote = note
+	}
+
+	if err := os.MkdirAll(filepath.Dir(result.OutputPath), 0o755); err != nil {
+		return fail(exitDownloadFailed, fmt.Sprintf("创建输出目录失败: %v", err))
+	}
+	switch opts.Format {
+	case "vtt":
+		err = writeExportVTT(result.OutputPath, srtPath, false)
+	case "txt":
+		err = writeTranscriptText(result.OutputPath, cues)
+	default:
+		err = copySubtitleFile(srtPath, result.OutputPath)
+	}
+	if err != nil {
+		return fail(exitDownloadFailed, fmt.Sprintf("写入转写结果失败: %v", err))
+	}
+	logInfo("transcribe.written", "asset_id", asset.AssetID, "path", result.OutputPath, "cues", len(cues))
+	return result
+}
+
+// transcribeOutputPath defaults to <asset name>.<format> next to the asset;
+// an --out directory receives the same file name.
+func transcribeOutputPath(assetPath, out, format string) string {
+	base := strings.TrimSuffix(filepath.Base(assetPath), filepath.Ext(assetPath))
+	name := base + "." + format
+	out = strings.TrimSpace(out)
+	if out == "" {
+		return filepath.Join(filepath.Dir(assetPath), name)
+	}
+	if dirExists(out) || filepath.Ext(out) == "" {
+		return filepath.Join(out, name)
+	}
+	return out
+}
+
+// writeTranscriptText writes one cue per line without timings.
+func writeTranscriptText(path string, cues []subtitleCue) error {
+	var b bytes.Buffer
+	for _, c := range cues {
+		text := strings.Join(strings.Fields(c.Text), " ")
+		if text == "" {
+			continue
+		}
+		b.WriteString(text)
+		b.WriteString("\n")
+	}
+	return os.WriteFile(path, b.Bytes(), 0o644)
+}
+
+func printTranscribeJSON(v transcribeJSONResult) {
+	data, err := marshalJSONResult(v)
+	if err != nil {
+		logError("json.marshal_failed", "context", "transcribe_result", "error", err)
+		return
+	}
+	fmt.Println(string(data))
+}
@@ -0,0 +1,470 @@
+// media-ingest (mingest) - Media Ingestion CLI tool
+// Copyright (C) 2026  Harrison Wang <https://mingest.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ingest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultTranslateBatchSize = 40
+
+type translateOptions struct {
+	AssetRef   string
+	To         string
+	Provider   string
+	Model      string
+	BaseURL    string
+	APIKey     string
+	Proxy      string
+	LLMTimeout time.Duration
+	BatchSize  int
+	Out        string
+	BundleDir  string
+	Bundle     string
+	JSON       bool
+}
+
+type translateJSONResult struct {
+	OK         bool     `json:"ok"`
+	ExitCode   int      `json:"exit_code"`
+	Error      string   `json:"error,omitempty"`
+	AssetID    string   `json:"asset_id,omitempty"`
+	To         string   `json:"to,omitempty"`
+	SourcePath string   `json:"source_path,omitempty"`
+	OutputPath string   `json:"output_path,omitempty"`
+	Provider   string   `json:"provider,omitempty"`
+	Model      string   `json:"model,omitempty"`
+	CueCount   int      `json:"cue_count,omitempty"`
+	BatchCount int      `json:"batch_count,omitempty"`
+	RetryCount int      `json:"retry_count,omitempty"`
+	Warnings   []string `json:"warnings,omitempty"`
+}
+
+// translateLangNames spells out common targets in the prompt; other codes are
+// passed through as-is.
+var translateLangNames = map[string]string{
+	"zh":    "简体中文",
+	"zh-cn": "简体中文",
+	"zh-tw": "繁體中文",
+	"en":    "English",
+	"ja":    "日本語",
+	"ko":    "한국어",
+	"es":    "Español",
+	"fr":    "Français",
+	"de":    "Deutsch",
+}
+
+var translateOutputSchema = llmOutputSchema{
+	Name:        "subtitle_translation",
+	Description: "按编号逐条返回字幕译文",
+	Schema: map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": false,
+		"required":             []string{"items"},
+		"properties": map[string]interface{}{
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type":                 "object",
+					"additionalProperties": false,
+					"required":             []string{"i", "text"},
+					"properties": map[string]interface{}{
+						"i":    map[string]interface{}{"type": "integer"},
+						"text": map[string]interface{}{"type": "string"},
+					},
+				},
+			},
+		},
+	},
+}
+
+type translateItem struct {
+	I    int    `json:"i"`
+	Text string `json:"text"`
+}
+
+func parseTranslateOptions(args []string) (translateOptions, error) {
+	opts := translateOptions{Provider: "auto", BatchSize: defaultTranslateBatchSize}
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		switch {
+		case arg == "--to":
+			if i+1 >= len(args) {
+				return translateOptions{}, fmt.Errorf("`--to` 缺少参数")
+			}
+			i++
+			opts.To = strings.ToLower(strings.TrimSpace(args[i]))
+		case strings.HasPrefix(arg, "--to="):
+			opts.To = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--to=")))
+		case arg == "--provider":
+			if i+1 >= len(args) {
+				return translateOptions{}, fmt.Errorf("`--provider` 缺少参数")
+			}
+			i++
+			opts.Provider = strings.ToLower(strings.TrimSpace(args[i]))
+		case strings.HasPrefix(arg, "--provider="):
+			opts.Provider = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--provider=")))
+		case arg == "--model":
+			if i+1 >= len(args) {
+				return translateOptions{}, fmt.Errorf("`--model` 缺少参数")
+			}
+			i++
+			opts.Model = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--model="):
+			opts.Model = strings.TrimSpace(strings.TrimPrefix(arg, "--model="))
+		case arg == "--base-url":
+			if i+1 >= len(args) {
+				return translateOptions{}, fmt.Errorf("`--base-url` 缺少参数")
+			}
+			i++
+			opts.BaseURL = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--base-url="):
+			opts.BaseURL = strings.TrimSpace(strings.TrimPrefix(arg, "--base-url="))
+		case arg == "--api-key":
+			if i+1 >= len(args) {
+				return translateOptions{}, fmt.Errorf("`--api-key` 缺少参数")
+			}
+			i++
+			opts.APIKey = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--api-key="):
+			opts.APIKey = strings.TrimSpace(strings.TrimPrefix(arg, "--api-key="))
+		case arg == "--proxy":
+			if i+1 >= len(args) {
+				return translateOptions{}, fmt.Errorf("`--proxy` 缺少参数")
+			}
+			i++
+			opts.Proxy = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--proxy="):
+			opts.Proxy = strings.TrimSpace(strings.TrimPrefix(arg, "--proxy="))
+		case arg == "--llm-timeout":
+			if i+1 >= len(args) {
+				return translateOptions{}, fmt.Errorf("`--llm-timeout` 缺少参数")
+			}
+			i++
+			d, err := parseTimeoutValue(args[i])
+			if err != nil {
+				return translateOptions{}, fmt.Errorf("`--llm-timeout` %v", err)
+			}
+			opts.LLMTimeout = d
+		case strings.HasPrefix(arg, "--llm-timeout="):
+			d, err := parseTimeoutValue(strings.TrimPrefix(arg, "--llm-timeout="))
+			if err != nil {
+				return translateOptions{}, fmt.Errorf("`--llm-timeout` %v", err)
+			}
+			opts.LLMTimeout = d
+		case arg == "--batch-size":
+			if i+1 >= len(args) {
+				return translateOptions{}, fmt.Errorf("`--batch-size` 缺少参数")
+			}
+			i++
+			n, err := strconv.Atoi(strings.TrimSpace(args[i]))
+			if err != nil {
+				return translateOptions{}, fmt.Errorf("`--batch-size` 必须是整数")
+			}
+			opts.BatchSize = n
+		case strings.HasPrefix(arg, "--batch-size="):
+			n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(arg, "--batch-size=")))
+			if err != nil {
+				return translateOptions{}, fmt.Errorf("`--batch-size` 必须是整数")
+			}
+			opts.BatchSize = n
+		case arg == "--out":
+			if i+1 >= len(args) {
+				return translateOptions{}, fmt.Errorf("`--out` 缺少参数")
+			}
+			i++
+			opts.Out = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--out="):
+			opts.Out = strings.TrimSpace(strings.TrimPrefix(arg, "--out="))
+		case arg == "--bundle-dir":
+			if i+1 >= len(args) {
+				return translateOptions{}, fmt.Errorf("`--bundle-dir` 缺少参数")
+			}
+			i++
+			opts.BundleDir = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--bundle-dir="):
+			opts.BundleDir = strings.TrimSpace(strings.TrimPrefix(arg, "--bundle-dir="))
+		case arg == "--bundle":
+			if i+1 >= len(args) {
+				return translateOptions{}, fmt.Errorf("`--bundle` 缺少参数")
+			}
+			i++
+			opts.Bundle = strings.TrimSpace(args[i])
+		case strings.HasPrefix(arg, "--bundle="):
+			opts.Bundle = strings.TrimSpace(strings.TrimPrefix(arg, "--bundle="))
+		case arg == "--json":
+			opts.JSON = true
+		case strings.HasPrefix(arg, "-"):
+			return translateOptions{}, fmt.Errorf("不支持的参数: %s", arg)
+		default:
+			if opts.AssetRef != "" {
+				return translateOptions{}, fmt.Errorf("`mingest translate-subtitles` 仅支持一个 asset_ref")
+			}
+			opts.AssetRef = arg
+		}
+	}
+	if strings.TrimSpace(opts.AssetRef) == "" {
+		return translateOptions{}, fmt.Errorf("缺少 asset_ref。用法: mingest translate-subtitles <asset_ref> --to <lang> [--provider <v>] [--model <name>] [--out <path>]")
+	}
+	if opts.To == "" {
+		return translateOptions{}, fmt.Errorf("缺少 `--to`（目标语言，如 zh、en、ja）")
+	}
+	switch opts.Provider {
+	case "auto", "openai", "openrouter", "anthropic", "gemini":
+	default:
+		return translateOptions{}, fmt.Errorf("`--provider` 仅支持 auto|openai|openrouter|anthropic|gemini")
+	}
+	if opts.BatchSize < 1 || opts.BatchSize > 200 {
+		return translateOptions{}, fmt.Errorf("`--batch-size` 需在 1-200")
+	}
+	if opts.Out != "" && !strings.EqualFold(filepath.Ext(opts.Out), ".srt") {
+		return translateOptions{}, fmt.Errorf("`--out` 仅支持 .srt")
+	}
+	proxy, err := resolveProxy(opts.Proxy)
+	if err != nil {
+		return translateOptions{}, err
+	}
+	opts.Proxy = proxy
+	return opts, nil
+}
+
+func runTranslate(opts translateOptions) int {
+	result := executeTranslate(opts)
+	if opts.JSON {
+		printTranslateJSON(result)
+		return result.ExitCode
+	}
+	if !result.OK {
+		logError("translate.failed", "exit_code", result.ExitCode, "detail", result.Error)
+		return result.ExitCode
+	}
+	fmt.Printf("asset_id: %s\n", result.AssetID)
+	fmt.Printf("source_path: %s\n", result.SourcePath)
+	fmt.Printf("output_path: %s\n", result.OutputPath)
+	fmt.Printf("provider: %s\n", result.Provider)
+	fmt.Printf("model: %s\n", result.Model)
+	fmt.Printf("cue_count: %d\n", result.CueCount)
+	for _, w := range result.Warnings {
+		fmt.Printf("warning: %s\n", w)
+	}
+	return exitOK
+}
+
+func executeTranslate(opts translateOptions) translateJSONResult {
+	fail := func(code int, msg string) translateJSONResult {
+		return translateJSONResult{OK: false, ExitCode: code, Error: msg}
+	}
+
+	asset, err := resolvePrepAsset(opts.AssetRef)
+	if err != nil {
+		return fail(exitDownloadFailed, err.Error())
+	}
+	if strings.TrimSpace(asset.AssetID) == "" {
+		assetID, err := computeAssetID(asset.OutputPath)
+		if err != nil {
+			return fail(exitDownloadFailed, fmt.Sprintf("生成 asset_id 失败: %v", err))
+		}
+		asset.AssetID = assetID
+	}
+	_, planPath, err := resolvePrepBundle(asset, opts.BundleDir, opts.Bundle)
+	if err != nil {
+		return fail(exitDownloadFailed, err.Error())
+	}
+	plan, err := readPrepPlan(planPath)
+	if err != nil {
+		return fail(exitDownloadFailed, fmt.Sprintf("读取 prep-plan.json 失败: %v", err))
+	}
+	cues, subtitlePath, hasRealSubtitle := loadDoctorSubtitle(plan)
+	if !hasRealSubtitle || len(cues) == 0 {
+		return fail(exitDownloadFailed, "prep 结果中只有字幕模板，没有可翻译的字幕")
+	}
+
+	llmCfg, err := resolveSemanticLLMConfig(semanticOptions{
+		Provider:       opts.Provider,
+		Model:          opts.Model,
+		FallbackModels: "none",
+		BaseURL:        opts.BaseURL,
+		APIKey:         opts.APIKey,
+		Proxy:          opts.Proxy,
+		LLMTimeout:     opts.LLMTimeout,
+		Temperature:    defaultSemanticTemperature,
+	})
+	if err != nil {
+		return fail(exitUsage, err.Error())
+	}
+
+	// A single-file plan's subtitle path may no longer exist (or point at the
+	// bundle's old location); write the translation next to the plan instead.
+	outputBase := subtitlePath
+	if !fileExists(subtitlePath) {
+		outputBase = filepath.Join(filepath.Dir(planPath), filepath.Base(subtitlePath))
+	}
+
+	result := translateJSONResult{
+		OK:         true,
+		ExitCode:   exitOK,
+		AssetID:    asset.AssetID,
+		To:         opts.To,
+		SourcePath: subtitlePath,
+		OutputPath: translateOutputPath(outputBase, opts.Out, opts.To),
+		Provider:   llmCfg.Provider,
+		Model:      llmCfg.Model,
+		CueCount:   len(cues),
+	}
+
+	client := newLLMClient(llmCfg, translateOutputSchema)
+	translated := make([]subtitleCue, len(cues))
+	for start := 0; start < len(cues); start += opts.BatchSize {
+		end := min(start+opts.BatchSize, len(cues))
+		texts, retries, err := translateSubtitleBatch(client, llmCfg.Timeout, cues[start:end], opts.To)
+		result.RetryCount += retries
+		result.BatchCount++
+		if err != nil {
+			return fail(exitDownloadFailed, fmt.Sprintf("翻译第 %d-%d 条字幕失败: %v", start+1, end, err))
+		}
+		for i, text := range texts {
+			cue := cues[start+i]
+			cue.Text = text
+			translated[start+i] = cue
+		}
+		logInfo("translate.batch_done", "from", start+1, "to", end, "total", len(cues))
+	}
+	if result.RetryCount > 0 {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("模型调用遇到限流或服务端错误，共重试 %d 次", result.RetryCount))
+	}
+
+	if err := os.MkdirAll(filepath.Dir(result.OutputPath), 0o755); err != nil {
+		return fail(exitDownloadFailed, fmt.Sprintf("创建输出目录失败: %v", err))
+	}
+	if err := os.WriteFile(result.OutputPath, []byte(formatSRTCues(translated)), 0o644); err != nil {
+		return fail(exitDownloadFailed, fmt.Sprintf("写入译文字幕失败: %v", err))
+	}
+	logInfo("translate.written", "asset_id", asset.AssetID, "path", result.OutputPath, "provider", llmCfg.Provider, "model", llmCfg.Model)
+	return result
+}
+
+// translateSubtitleBatch translates one batch and returns the texts in cue
+// order. A reply that drops, adds or reorders cues is re-requested once so
+// timings always stay 1:1 with the source.
+func translateSubtitleBatch(client semanticLLMClient, timeout time.Duration, cues []subtitleCue, to string) ([]string, int, error) {
+	items := make([]translateItem, len(cues))
+	for i, c := range cues {
+		items[i] = translateItem{I: i + 1, Text: strings.TrimSpace(c.Text)}
+	}
+	payload, _ := json.Marshal(map[string]interface{}{"items": items})
+	lang := firstNonEmpty(translateLangNames[to], to)
+
+	systemPrompt := "你是专业字幕译者。逐条翻译字幕，保持口语化与简洁，不合并、不拆分、不遗漏条目。仅输出 JSON。"
+	userPrompt := "" +
+		"把下面每条字幕翻译成 " + lang + "。\n" +
+		"要求:\n" +
+		"1) 每条输入对应一条输出，i 保持不变，条数相同。\n" +
+		"2) 人名、品牌等专有名词可保留原文。\n" +
+		"3) 原文已是目标语言时原样返回。\n\n" +
+		"输出格式:\n" +
+		`{"items":[{"i":1,"text":"..."}]}` + "\n\n" +
+		"字幕:\n" + string(payload)
+
+	totalRetries := 0
+	var lastErr error
+	for attempt := 0; attempt < 2; attempt++ {
+		raw, retries, err := semanticCompleteWithRetry(client, timeout, systemPrompt, userPrompt)
+		totalRetries += retries
+		if err != nil {
+			return nil, totalRetries, err
+		}
+		texts, err := parseTranslateResponse(raw, len(cues))
+		if err == nil {
+			return texts, totalRetries, nil
+		}
+		lastErr = err
+		logWarn("translate.batch_misaligned", "attempt", attempt+1, "error", err)
+	}
+	return nil, totalRetries, lastErr
+}
+
+func parseTranslateResponse(raw string, want int) ([]string, error) {
+	raw = strings.TrimSpace(raw)
+	if !strings.HasPrefix(raw, "{") {
+		if fixed := extractFirstJSONObject(raw); fixed != "" {
+			raw = fixed
+		}
+	}
+	var parsed struct {
+		Items []translateItem `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+		return nil, fmt.Errorf("解析 JSON 失败: %w", err)
+	}
+	if len(parsed.Items) != want {
+		return nil, fmt.Errorf("译文条数 %d 与原文 %d 不一致", len(parsed.Items), want)
+	}
+	texts := make([]string, want)
+	for _, it := range parsed.Items {
+		if it.I < 1 || it.I > want || texts[it.I-1] != "" {
+			return nil, fmt.Errorf("译文编号无效或重复: %d", it.I)
+		}
+		text := strings.TrimSpace(it.Text)
+		if text == "" {
+			return nil, errors.New("译文包含空条目")
+		}
+		texts[it.I-1] = text
+	}
+	return texts, nil
+}
+
+// translateOutputPath defaults to <subtitle name>.<lang>.translated.srt next
+// to the source subtitle.
+func translateOutputPath(subtitlePath, out, to string) string {
+	if strings.TrimSpace(out) != "" {
+		return out
+	}
+	base := strings.TrimSuffix(filepath.Base(subtitlePath), filepath.Ext(subtitlePath))
+	return filepath.Join(filepath.Dir(subtitlePath), fmt.Sprintf("%s.%s.translated.srt", base, to))
+}
+
+// formatSRTCues renders cues as SRT, keeping every cue and its timing.
+func formatSRTCues(cues []subtitleCue) string {
+	var b strings.Builder
+	for i, c := range cues {
+		b.WriteString(strconv.Itoa(i + 1))
+		b.WriteByte('\n')
+		b.WriteString(formatSRTTime(c.StartSec))
+		b.WriteString(" --> ")
+		b.WriteString(formatSRTTime(c.EndSec))
+		b.WriteByte('\n')
+		b.WriteString(strings.TrimSpace(c.Text))
+		b.WriteString("\n\n")
+	}
+	return b.String()
+}
+
+func printTranslateJSON(v translateJSONResult) {
+	data, err := marshalJSONResult(v)
+	if err != nil {
+		logError("json.marshal_failed", "context", "translate_result", "error", err)
+		return
+	}
+	fmt.Println(string(data))
+}
@@ -0,0 +1,162 @@
+// media-ingest (mingest) - Media Ingestion CLI tool
+// Copyright (C) 2026  Harrison Wang <https://mingest.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ingest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type verifyOptions struct {
+	AssetRef string
+	JSON     bool
+}
+
+type verifyJSONResult struct {
+	OK           bool   `json:"ok"`
+	ExitCode     int    `json:"exit_code"`
+	Error        string `json:"error,omitempty"`
+	AssetID      string `json:"asset_id,omitempty"`
+	AssetPath    string `json:"asset_path,omitempty"`
+	ExpectedSHA  string `json:"expected_sha256,omitempty"`
+	ActualSHA    string `json:"actual_sha256,omitempty"`
+	AssetIDMatch bool   `json:"asset_id_match"`
+}
+
+func parseVerifyOptions(args []string) (verifyOptions, error) {
+	opts := verifyOptions{}
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		switch {
+		case arg == "--json":
+			opts.JSON = true
+		case strings.HasPrefix(arg, "-"):
+			return verifyOptions{}, fmt.Errorf("不支持的参数: %s", arg)
+		default:
+			if opts.AssetRef != "" {
+				return verifyOptions{}, fmt.Errorf("`mingest verify` 仅支持一个 asset_ref")
+			}
+			opts.AssetRef = arg
+		}
+	}
+	if strings.TrimSpace(opts.AssetRef) == "" {
+		return verifyOptions{}, fmt.Errorf("缺少 asset_ref。用法: mingest verify <asset_ref>")
+	}
+	return opts, nil
+}
+
+func runVerify(opts verifyOptions) int {
+	records, err := readAssetRecords()
+	if err != nil {
+		return verifyExitWithErr(opts.JSON, exitVerifyFailed, fmt.Sprintf("读取素材索引失败: %v", err))
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return parseRecordTime(records[i]).After(parseRecordTime(records[j]))
+	})
+
+	// Match by the index rather than by recomputing asset_id from the file:
+	// a corrupted file may no longer hash to its original asset_id.
+	var rec assetRecord
+	found := false
+	for _, r := range records {
+		if !prepRecordMatchesRef(r, opts.AssetRef) {
+			continue
+		}
+		if !found {
+			rec = r
+			found = true
+		}
+		if strings.TrimSpace(r.ContentSHA256) != "" {
+			rec = r
+			break
+		}
+	}
+	if !found {
+		return verifyExitWithErr(opts.JSON, exitVerifyFailed, fmt.Sprintf("未在索引中找到素材: %s", opts.AssetRef))
+	}
+	expected := strings.ToLower(strings.TrimSpace(rec.ContentSHA256))
+	if expected == "" {
+		return verifyExitWithErr(opts.JSON, exitVerifyFailed, fmt.Sprintf("素材 %s 未记录 content_sha256（请使用 `mingest get --verify` 重新下载以记录校验值）", rec.AssetID))
+	}
+
+	path, ok := resolveLocalAssetPath(rec.OutputPath)
+	if !ok {
+		return verifyExitWithErr(opts.JSON, exitVerifyFailed, fmt.Sprintf("本地文件不存在: %s", strings.TrimSpace(rec.OutputPath)))
+	}
+
+	logInfo("verify.started", "asset_id", rec.AssetID, "path", path)
+	actual, err := computeContentSHA256(path)
+	if err != nil {
+		return verifyExitWithErr(opts.JSON, exitVerifyFailed, fmt.Sprintf("计算 content_sha256 失败: %v", err))
+	}
+	currentID, _ := computeAssetID(path)
+
+	result := verifyJSONResult{
+		OK:           actual == expected,
+		ExitCode:     exitOK,
+		AssetID:      strings.TrimSpace(rec.AssetID),
+		AssetPath:    path,
+		ExpectedSHA:  expected,
+		ActualSHA:    actual,
+		AssetIDMatch: currentID == strings.TrimSpace(rec.AssetID),
+	}
+	if !result.OK {
+		result.ExitCode = exitVerifyFailed
+		result.Error = "文件内容与记录的 content_sha256 不一致（可能下载中断或文件损坏）"
+		logError("verify.mismatch", "asset_id", result.AssetID, "expected", expected, "actual", actual)
+	} else {
+		logInfo("verify.ok", "asset_id", result.AssetID)
+	}
+
+	if opts.JSON {
+		printVerifyJSON(result)
+		return result.ExitCode
+	}
+	fmt.Printf("asset_id: %s\n", result.AssetID)
+	fmt.Printf("asset_path: %s\n", result.AssetPath)
+	fmt.Printf("expected_sha256: %s\n", result.ExpectedSHA)
+	fmt.Printf("actual_sha256: %s\n", result.ActualSHA)
+	if result.OK {
+		fmt.Println("result: OK")
+	} else {
+		fmt.Println("result: MISMATCH")
+	}
+	return result.ExitCode
+}
+
+func verifyExitWithErr(asJSON bool, exitCode int, msg string) int {
+	if asJSON {
+		printVerifyJSON(verifyJSONResult{
+			OK:       false,
+			ExitCode: exitCode,
+			Error:    msg,
+		})
+	} else {
+		logError("verify.failed", "exit_code", exitCode, "detail", msg)
+	}
+	return exitCode
+}
+
+func printVerifyJSON(v verifyJSONResult) {
+	data, err := marshalJSONResult(v)
+	if err != nil {
+		logError("json.marshal_failed", "context", "verify_result", "error", err)
+		return
+	}
+	fmt.Println(string(data))
+}
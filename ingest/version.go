@@ -0,0 +1,215 @@
+// media-ingest (mingest) - Media Ingestion CLI tool
+// Copyright (C) 2026  Harrison Wang <https://mingest.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ingest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultReleaseCheckURL = "https://api.github.com/repos/harrisonwang/media-ingest/releases/latest"
+	releaseCheckTimeout    = 5 * time.Second
+)
+
+type versionOptions struct {
+	Check bool
+	JSON  bool
+}
+
+type versionJSONResult struct {
+	OK              bool   `json:"ok"`
+	ExitCode        int    `json:"exit_code"`
+	Error           string `json:"error,omitempty"`
+	Current         string `json:"current"`
+	Latest          string `json:"latest,omitempty"`
+	UpdateAvailable bool   `json:"update_available"`
+	Checked         bool   `json:"checked"`
+	SkippedReason   string `json:"skipped_reason,omitempty"`
+	ReleaseURL      string `json:"release_url,omitempty"`
+}
+
+func parseVersionOptions(args []string) (versionOptions, error) {
+	opts := versionOptions{}
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		switch {
+		case arg == "--check":
+			opts.Check = true
+		case arg == "--json":
+			opts.JSON = true
+		default:
+			return versionOptions{}, fmt.Errorf("不支持的参数: %s", arg)
+		}
+	}
+	return opts, nil
+}
+
+// runVersion prints the embedded version and, with --check, compares it with
+// the latest published release. It never updates anything, and a failed
+// check (offline, timeout, bad response) is reported as skipped, not as an error.
+func runVersion(opts versionOptions) int {
+	result := versionJSONResult{
+		OK:       true,
+		ExitCode: exitOK,
+		Current:  strings.TrimSpace(version),
+	}
+
+	if opts.Check {
+		checkURL := firstNonEmpty(strings.TrimSpace(os.Getenv("MINGEST_RELEASE_URL")), defaultReleaseCheckURL)
+		latest, releaseURL, err := fetchLatestRelease(checkURL)
+		if err != nil {
+			result.SkippedReason = fmt.Sprintf("无法获取最新版本，已跳过检查: %v", err)
+			logWarn("version.check_skipped", "url", checkURL, "error", err)
+		} else {
+			result.Checked = true
+			result.Latest = latest
+			result.ReleaseURL = releaseURL
+			if cmp, ok := compareReleaseVersions(result.Current, latest); ok {
+				result.UpdateAvailable = cmp < 0
+			} else {
+				result.SkippedReason = "当前为开发版本，无法与发布版本比较"
+			}
+		}
+	}
+
+	if opts.JSON {
+		printVersionJSON(result)
+		return result.ExitCode
+	}
+
+	printVersion()
+	if !opts.Check {
+		return exitOK
+	}
+	if result.Latest != "" {
+		fmt.Printf("latest: %s\n", result.Latest)
+	}
+	switch {
+	case result.UpdateAvailable:
+		fmt.Printf("update_available: true\n")
+		if result.ReleaseURL != "" {
+			fmt.Printf("release_url: %s\n", result.ReleaseURL)
+		}
+	case result.Checked && result.SkippedReason == "":
+		fmt.Printf("update_available: false\n")
+	}
+	if result.SkippedReason != "" {
+		fmt.Printf("warning: %s\n", result.SkippedReason)
+	}
+	return exitOK
+}
+
+func fetchLatestRelease(checkURL string) (tag string, htmlURL string, err error) {
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if proxy, perr := resolveProxy(""); perr == nil && proxy != "" {
+		proxyURL, _ := url.Parse(proxy)
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	client := &http.Client{Timeout: releaseCheckTimeout, Transport: transport}
+
+	req, err := http.NewRequest(http.MethodGet, checkURL, nil)
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "mingest/"+strings.TrimSpace(version))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	var release struct {
+		TagName string `json:"tag_name"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&release); err != nil {
+		return "", "", fmt.Errorf("解析发布信息失败: %w", err)
+	}
+	if strings.TrimSpace(release.TagName) == "" {
+		return "", "", fmt.Errorf("发布信息缺少 tag_name")
+	}
+	return strings.TrimSpace(release.TagName), strings.TrimSpace(release.HTMLURL), nil
+}
+
+// compareReleaseVersions compares dotted numeric versions (a leading "v" and
+// any -prerelease/+build suffix are ignored). ok is false when either side is
+// not a release version, e.g. "dev" or "dev-abc1234".
+func compareReleaseVersions(a, b string) (cmp int, ok bool) {
+	pa, okA := parseReleaseVersion(a)
+	pb, okB := parseReleaseVersion(b)
+	if !okA || !okB {
+		return 0, false
+	}
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1, true
+			}
+			return 1, true
+		}
+	}
+	return 0, true
+}
+
+func parseReleaseVersion(v string) ([]int, bool) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	if v == "" {
+		return nil, false
+	}
+	parts := strings.Split(v, ".")
+	out := make([]int, 0, len(parts))
+	for _, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		out = append(out, n)
+	}
+	return out, true
+}
+
+func printVersionJSON(v versionJSONResult) {
+	data, err := marshalJSONResult(v)
+	if err != nil {
+		logError("json.marshal_failed", "context", "version_result", "error", err)
+		return
+	}
+	fmt.Println(string(data))
+}
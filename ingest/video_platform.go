@@ -19,6 +19,7 @@ package ingest
 import (
 	"net/url"
 	"strings"
+	"time"
 )
 
 // videoPlatform describes per-site behavior (cookies, auth signals, etc.).
@@ -42,6 +43,18 @@ type videoPlatform struct {
 	// AuthCookieNames are used as a heuristic to detect whether a cookie jar is
 	// likely authenticated for this platform.
 	AuthCookieNames []string
+
+	// ConsentSelectors are CSS selectors for the "accept" button of consent
+	// dialogs and age gates, tried in order by `mingest auth --consent-url`.
+	ConsentSelectors []string
+
+	// ConsentCookieNames are set once consent has been recorded. When listed,
+	// auto-consent only counts as done if one of them is present.
+	ConsentCookieNames []string
+
+	// RateLimitCooldown is how long to wait after an HTTP 429 before retrying
+	// the same auth source. Zero uses defaultRateLimitCooldown.
+	RateLimitCooldown time.Duration
 }
 
 func (p videoPlatform) MatchesURL(u *url.URL) bool {